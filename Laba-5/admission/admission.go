@@ -0,0 +1,245 @@
+// Package admission - прийом, переведення й виписка пацієнтів і додавання
+// ліжок у палати. Кожен окремий запис робить Store (у Mongo - умовним
+// оновленням, тож ліжко не займуть двічі, а палата не переповниться), а
+// пакет складає ці записи в одиницю роботи: якщо падає наступний крок,
+// попередні скасовуються, і ліжко не лишається зайнятим виписаним пацієнтом.
+package admission
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrNoFreeBed       = errors.New("no free bed")
+	ErrAlreadyAdmitted = errors.New("patient is already admitted")
+	ErrNotActive       = errors.New("admission is not active")
+	ErrSameBed         = errors.New("patient is already on this bed")
+	ErrConflict        = errors.New("admission was changed concurrently")
+	ErrWardFull        = errors.New("ward is full")
+	ErrBedNotFree      = errors.New("bed is occupied or does not exist")
+	ErrBedNotInTrash   = errors.New("bed is not in trash")
+)
+
+// Request - куди покласти пацієнта: конкретне ліжко або будь-яке
+// вільне ліжко відділення
+type Request struct {
+	PatientID    primitive.ObjectID
+	BedID        primitive.ObjectID
+	DepartmentID primitive.ObjectID
+}
+
+// Store - атомарні записи, з яких складаються операції. Кожен метод
+// перевіряє свою умову сам і повертає помилку пакета, якщо вона не
+// виконана; ctx - контекст одиниці роботи.
+type Store interface {
+	// ClaimBed займає вільне живе ліжко req.BedID або будь-яке вільне у
+	// req.DepartmentID, крім exclude (ErrNoFreeBed)
+	ClaimBed(ctx context.Context, req Request, admission models.Admission, exclude primitive.ObjectID) (models.Bed, error)
+	// ReleaseBed звільняє ліжко, лише якщо воно все ще належить admissionID
+	ReleaseBed(ctx context.Context, bedID, admissionID primitive.ObjectID) error
+
+	// InsertAdmission - нова активна госпіталізація (ErrAlreadyAdmitted)
+	InsertAdmission(ctx context.Context, admission models.Admission) error
+	DeleteAdmission(ctx context.Context, id primitive.ObjectID) error
+	// MoveAdmission переносить активну госпіталізацію з ліжка from на bed
+	// і дописує transfer (ErrConflict, якщо її вже змінили)
+	MoveAdmission(ctx context.Context, id, from primitive.ObjectID, bed models.Bed, transfer models.BedTransfer) error
+	// UnmoveAdmission повертає госпіталізацію на попереднє ліжко
+	UnmoveAdmission(ctx context.Context, previous models.Admission) error
+	// SetStatus змінює статус активної госпіталізації (ErrNotActive);
+	// dischargedAt nil прибирає час виписки
+	SetStatus(ctx context.Context, id primitive.ObjectID, from, to string, dischargedAt *time.Time) error
+
+	// ReserveBed займає місце в живій палаті, якщо beds < capacity (ErrWardFull)
+	ReserveBed(ctx context.Context, wardID primitive.ObjectID) (models.Ward, error)
+	UnreserveBed(ctx context.Context, wardID primitive.ObjectID) error
+	InsertBed(ctx context.Context, bed models.Bed) (primitive.ObjectID, error)
+	// TrashBed позначає видаленим живе вільне ліжко (ErrBedNotFree)
+	TrashBed(ctx context.Context, bedID primitive.ObjectID, mark models.SoftDelete) (models.Bed, error)
+	// UntrashBed повертає ліжко з кошика (ErrBedNotInTrash)
+	UntrashBed(ctx context.Context, bedID primitive.ObjectID) error
+}
+
+func placeOnBed(admission *models.Admission, bed models.Bed) {
+	admission.BedID = bed.ID
+	admission.WardID = bed.WardID
+	admission.DepartmentID = bed.DepartmentID
+	admission.HospitalID = bed.HospitalID
+}
+
+// Admit займає ліжко і створює госпіталізацію; якщо пацієнт уже лежить,
+// ліжко звільняється
+func Admit(u *db.UnitOfWork, s Store, req Request, now time.Time) (models.Admission, error) {
+	admission := models.Admission{
+		ID:         primitive.NewObjectID(),
+		PatientID:  req.PatientID,
+		Status:     models.AdmissionActive,
+		AdmittedAt: now,
+	}
+
+	var bed models.Bed
+	err := u.Step(func(ctx context.Context) error {
+		var err error
+		bed, err = s.ClaimBed(ctx, req, admission, primitive.NilObjectID)
+		return err
+	}, func(ctx context.Context) error {
+		return s.ReleaseBed(ctx, bed.ID, admission.ID)
+	})
+	if err != nil {
+		return admission, err
+	}
+	placeOnBed(&admission, bed)
+
+	err = u.Step(func(ctx context.Context) error {
+		return s.InsertAdmission(ctx, admission)
+	}, func(ctx context.Context) error {
+		return s.DeleteAdmission(ctx, admission.ID)
+	})
+	return admission, err
+}
+
+// Transfer спочатку займає нове ліжко, потім переносить госпіталізацію і
+// лише тоді звільняє старе ліжко
+func Transfer(u *db.UnitOfWork, s Store, admission models.Admission, req Request, now time.Time) (models.Admission, error) {
+	if admission.Status != models.AdmissionActive {
+		return admission, ErrNotActive
+	}
+	if req.BedID == admission.BedID {
+		return admission, ErrSameBed
+	}
+	previous := admission
+
+	var bed models.Bed
+	err := u.Step(func(ctx context.Context) error {
+		var err error
+		bed, err = s.ClaimBed(ctx, req, admission, admission.BedID)
+		return err
+	}, func(ctx context.Context) error {
+		return s.ReleaseBed(ctx, bed.ID, admission.ID)
+	})
+	if err != nil {
+		return previous, err
+	}
+
+	transfer := models.BedTransfer{FromBedID: previous.BedID, ToBedID: bed.ID, At: now}
+	err = u.Step(func(ctx context.Context) error {
+		return s.MoveAdmission(ctx, admission.ID, previous.BedID, bed, transfer)
+	}, func(ctx context.Context) error {
+		return s.UnmoveAdmission(ctx, previous)
+	})
+	if err != nil {
+		return previous, err
+	}
+
+	err = u.Step(func(ctx context.Context) error {
+		return s.ReleaseBed(ctx, previous.BedID, admission.ID)
+	}, nil)
+	if err != nil {
+		return previous, err
+	}
+
+	placeOnBed(&admission, bed)
+	admission.Transfers = append(admission.Transfers, transfer)
+	return admission, nil
+}
+
+// Discharge виписує пацієнта і звільняє ліжко; якщо ліжко звільнити не
+// вдалося, виписка скасовується
+func Discharge(u *db.UnitOfWork, s Store, admission models.Admission, now time.Time) (models.Admission, error) {
+	if admission.Status != models.AdmissionActive {
+		return admission, ErrNotActive
+	}
+	err := u.Step(func(ctx context.Context) error {
+		return s.SetStatus(ctx, admission.ID, models.AdmissionActive, models.AdmissionDischarged, &now)
+	}, func(ctx context.Context) error {
+		return s.SetStatus(ctx, admission.ID, models.AdmissionDischarged, models.AdmissionActive, nil)
+	})
+	if err != nil {
+		return admission, err
+	}
+
+	err = u.Step(func(ctx context.Context) error {
+		return s.ReleaseBed(ctx, admission.BedID, admission.ID)
+	}, nil)
+	if err != nil {
+		return admission, err
+	}
+
+	admission.Status = models.AdmissionDischarged
+	admission.DischargedAt = &now
+	return admission, nil
+}
+
+// AddBed резервує місце в палаті і створює ліжко; якщо вставка падає,
+// місце повертається
+func AddBed(u *db.UnitOfWork, s Store, bed models.Bed) (models.Bed, error) {
+	var ward models.Ward
+	err := u.Step(func(ctx context.Context) error {
+		var err error
+		ward, err = s.ReserveBed(ctx, bed.WardID)
+		return err
+	}, func(ctx context.Context) error {
+		return s.UnreserveBed(ctx, bed.WardID)
+	})
+	if err != nil {
+		return bed, err
+	}
+
+	bed.DepartmentID = ward.DepartmentID
+	bed.HospitalID = ward.HospitalID
+	bed.PatientID = nil
+	bed.AdmissionID = nil
+	bed.SoftDelete = models.SoftDelete{}
+
+	err = u.Step(func(ctx context.Context) error {
+		id, err := s.InsertBed(ctx, bed)
+		bed.ID = id
+		return err
+	}, nil)
+	return bed, err
+}
+
+// RemoveBed кладе вільне ліжко в кошик і звільняє його місце в палаті;
+// якщо лічильник палати не оновився, ліжко повертається
+func RemoveBed(u *db.UnitOfWork, s Store, bedID primitive.ObjectID, by string, now time.Time) (models.Bed, error) {
+	var bed models.Bed
+	err := u.Step(func(ctx context.Context) error {
+		var err error
+		bed, err = s.TrashBed(ctx, bedID, models.SoftDelete{DeletedAt: &now, DeletedBy: by})
+		return err
+	}, func(ctx context.Context) error {
+		return s.UntrashBed(ctx, bedID)
+	})
+	if err != nil {
+		return bed, err
+	}
+
+	err = u.Step(func(ctx context.Context) error {
+		return s.UnreserveBed(ctx, bed.WardID)
+	}, nil)
+	return bed, err
+}
+
+// RestoreBed займає місце в палаті і повертає ліжко з кошика; якщо
+// ліжка в кошику вже немає, місце звільняється
+func RestoreBed(u *db.UnitOfWork, s Store, bed models.Bed) error {
+	err := u.Step(func(ctx context.Context) error {
+		_, err := s.ReserveBed(ctx, bed.WardID)
+		return err
+	}, func(ctx context.Context) error {
+		return s.UnreserveBed(ctx, bed.WardID)
+	})
+	if err != nil {
+		return err
+	}
+	return u.Step(func(ctx context.Context) error {
+		return s.UntrashBed(ctx, bed.ID)
+	}, nil)
+}
@@ -0,0 +1,41 @@
+// Package cache - кеш результатів читання з Mongo з інвалідацією по
+// колекції. Memory - реалізація в пам'яті процесу; спільний кеш
+// (наприклад, Redis) має реалізувати той самий інтерфейс Cache.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Entry - закешований результат. Version - версія колекції, з якої його
+// прочитано, Modified - час останньої відомої зміни цієї колекції.
+type Entry struct {
+	Value    []byte
+	Version  uint64
+	Modified time.Time
+}
+
+// Stats - лічильники однієї колекції
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+	Bytes         int
+}
+
+type Cache interface {
+	// Get повертає живий запис за ключем
+	Get(ctx context.Context, collection, key string) (Entry, bool)
+	// Set зберігає запис, лише якщо entry.Version ще поточна: так результат,
+	// прочитаний до запису в колекцію, не потрапить у кеш після інвалідації
+	Set(ctx context.Context, collection, key string, entry Entry)
+	// Version - поточна версія колекції і час її останньої зміни
+	Version(ctx context.Context, collection string) (uint64, time.Time)
+	// Invalidate скидає всі записи колекції і підвищує її версію
+	Invalidate(ctx context.Context, collection string)
+	// Stats - лічильники по колекціях
+	Stats() map[string]Stats
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Options - межі кешу в пам'яті; нуль означає "без обмеження"
+type Options struct {
+	TTL        time.Duration
+	MaxEntries int
+	MaxBytes   int
+}
+
+// Memory - LRU-кеш у пам'яті процесу з TTL і межами за кількістю
+// записів і сумарним розміром
+type Memory struct {
+	opts Options
+	now  func() time.Time
+
+	mu          sync.Mutex
+	lru         *list.List // спереду - нещодавно використані
+	items       map[string]*list.Element
+	bytes       int
+	collections map[string]*collectionState
+}
+
+type memoryItem struct {
+	collection, key string
+	entry           Entry
+	expires         time.Time
+}
+
+type collectionState struct {
+	version  uint64
+	modified time.Time
+	stats    Stats
+}
+
+func NewMemory(opts Options) *Memory {
+	return &Memory{
+		opts:        opts,
+		now:         time.Now,
+		lru:         list.New(),
+		items:       map[string]*list.Element{},
+		collections: map[string]*collectionState{},
+	}
+}
+
+// SetClock підміняє годинник - для тестів TTL
+func (m *Memory) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	m.now = now
+	m.mu.Unlock()
+}
+
+func itemKey(collection, key string) string {
+	return collection + "\x00" + key
+}
+
+func (m *Memory) state(collection string) *collectionState {
+	st, ok := m.collections[collection]
+	if !ok {
+		st = &collectionState{modified: m.now().UTC().Truncate(time.Second)}
+		m.collections[collection] = st
+	}
+	return st
+}
+
+func (m *Memory) Get(_ context.Context, collection, key string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.state(collection)
+
+	el, ok := m.items[itemKey(collection, key)]
+	if ok && m.opts.TTL > 0 && !m.now().Before(el.Value.(*memoryItem).expires) {
+		m.remove(el)
+		ok = false
+	}
+	if !ok {
+		st.stats.Misses++
+		return Entry{}, false
+	}
+	m.lru.MoveToFront(el)
+	st.stats.Hits++
+	return el.Value.(*memoryItem).entry, true
+}
+
+func (m *Memory) Set(_ context.Context, collection, key string, entry Entry) {
+	size := len(entry.Value)
+	if m.opts.MaxBytes > 0 && size > m.opts.MaxBytes {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.state(collection).version != entry.Version {
+		return
+	}
+
+	if el, ok := m.items[itemKey(collection, key)]; ok {
+		m.remove(el)
+	}
+	item := &memoryItem{collection: collection, key: key, entry: entry, expires: m.now().Add(m.opts.TTL)}
+	m.items[itemKey(collection, key)] = m.lru.PushFront(item)
+	m.bytes += size
+	st := m.state(collection)
+	st.stats.Entries++
+	st.stats.Bytes += size
+
+	for m.overflow() {
+		oldest := m.lru.Back()
+		m.state(oldest.Value.(*memoryItem).collection).stats.Evictions++
+		m.remove(oldest)
+	}
+}
+
+func (m *Memory) overflow() bool {
+	return m.opts.MaxEntries > 0 && m.lru.Len() > m.opts.MaxEntries ||
+		m.opts.MaxBytes > 0 && m.bytes > m.opts.MaxBytes
+}
+
+func (m *Memory) remove(el *list.Element) {
+	item := m.lru.Remove(el).(*memoryItem)
+	delete(m.items, itemKey(item.collection, item.key))
+	size := len(item.entry.Value)
+	m.bytes -= size
+	st := m.state(item.collection)
+	st.stats.Entries--
+	st.stats.Bytes -= size
+}
+
+func (m *Memory) Version(_ context.Context, collection string) (uint64, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.state(collection)
+	return st.version, st.modified
+}
+
+func (m *Memory) Invalidate(_ context.Context, collection string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.state(collection)
+	st.version++
+	// Last-Modified має секундну точність - зберігаємо так само
+	st.modified = m.now().UTC().Truncate(time.Second)
+	st.stats.Invalidations++
+
+	for el := m.lru.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*memoryItem).collection == collection {
+			m.remove(el)
+		}
+		el = next
+	}
+}
+
+func (m *Memory) Stats() map[string]Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]Stats, len(m.collections))
+	for name, st := range m.collections {
+		out[name] = st.stats
+	}
+	return out
+}
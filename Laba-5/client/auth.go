@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// refreshBefore - за скільки до exp токен вважається застарілим:
+// запас на розбіжність годинників і тривалість самого запиту
+const refreshBefore = time.Minute
+
+// Login входить як username і повертає JWT. Токен не запам'ятовується:
+// для автоматичного входу задайте Options.Username і Options.Password.
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	var out struct {
+		Token string `json:"token"`
+	}
+	creds := map[string]string{"username": username, "password": password}
+	err := c.call(ctx, Request{Method: http.MethodPost, Path: "/login", Anonymous: true}, creds, &out)
+	if err != nil {
+		return "", err
+	}
+	if out.Token == "" {
+		return "", errors.New("client: login returned no token")
+	}
+	return out.Token, nil
+}
+
+// Token - поточний JWT (після автоматичного входу - отриманий від сервера)
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+func (c *Client) canLogin() bool {
+	return c.opts.Username != "" && c.opts.Password != ""
+}
+
+// currentToken віддає дійсний токен, за потреби входячи заново.
+// Вхід - під м'ютексом, щоб паралельні запити не логінилися кожен окремо.
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fresh := c.token != "" && (c.expiresAt.IsZero() || time.Now().Add(refreshBefore).Before(c.expiresAt))
+	if fresh || !c.canLogin() {
+		return c.token, nil
+	}
+	token, err := c.Login(ctx, c.opts.Username, c.opts.Password)
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expiresAt = token, TokenExpiry(token)
+	return c.token, nil
+}
+
+// invalidate забуває токен після 401, щоб наступний запит увійшов знову
+func (c *Client) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token, c.expiresAt = "", time.Time{}
+}
+
+// TokenExpiry читає exp з JWT без перевірки підпису (ключ знає лише сервер);
+// нуль - якщо exp немає
+func TokenExpiry(token string) time.Time {
+	if token == "" {
+		return time.Time{}
+	}
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt.Time
+}
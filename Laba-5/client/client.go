@@ -0,0 +1,282 @@
+// Package client - типізований Go-клієнт hospital-api на типах models.
+// Сам входить через /login і перевходить, коли JWT спливає, повторює
+// ідемпотентні запити при мережевих збоях і 429/502/503/504, а помилки
+// повертає як *Error з категорією для errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Значення Options за замовчуванням
+const (
+	DefaultMaxRetries = 2
+	DefaultBackoff    = 200 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+	DefaultTimeout    = 30 * time.Second
+)
+
+// Options налаштовує клієнт. Username і Password вмикають автоматичний
+// вхід; Token - готовий JWT без перевходу; APIKey потрібен лікарням,
+// відділенням і лікарям.
+type Options struct {
+	HTTPClient *http.Client
+	Username   string
+	Password   string
+	Token      string
+	APIKey     string
+	// Language іде в Accept-Language: мова повідомлень про помилки
+	Language string
+	// MaxRetries - скільки разів повторити збійний запит;
+	// 0 - DefaultMaxRetries, від'ємне - без повторів
+	MaxRetries int
+	// Backoff - перша пауза між спробами, далі вона подвоюється до MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client безпечний для одночасного використання з кількох горутин
+type Client struct {
+	baseURL string
+	opts    Options
+	http    *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// New створює клієнт для API за адресою baseURL (http://localhost:8080)
+func New(baseURL string, opts Options) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	switch {
+	case opts.MaxRetries == 0:
+		opts.MaxRetries = DefaultMaxRetries
+	case opts.MaxRetries < 0:
+		opts.MaxRetries = 0
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		opts:      opts,
+		http:      opts.HTTPClient,
+		token:     opts.Token,
+		expiresAt: TokenExpiry(opts.Token),
+	}
+}
+
+// Request - довільний запит до API для методів, яких немає в клієнті
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+	// Idempotent дозволяє повтор POST: клієнт додає Idempotency-Key,
+	// і сервер віддасть збережену відповідь замість повторного запису
+	Idempotent bool
+	// Anonymous - не входити і не надсилати токен (/login)
+	Anonymous bool
+}
+
+// Response - успішна відповідь API
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// JSON повідомляє, чи тіло відповіді - JSON (а не текстове повідомлення)
+func (r *Response) JSON() bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+// Do виконує запит з авторизацією і повторами; коди поза 2xx - *Error
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	headers := http.Header{}
+	headers.Set("Accept", "application/json")
+	if req.Body != nil {
+		headers.Set("Content-Type", "application/json")
+	}
+	if c.opts.APIKey != "" {
+		headers.Set("X-API-KEY", c.opts.APIKey)
+	}
+	if c.opts.Language != "" {
+		headers.Set("Accept-Language", c.opts.Language)
+	}
+	retryable := retryableMethod(req.Method)
+	if req.Method == http.MethodPost && req.Idempotent {
+		headers.Set("Idempotency-Key", newKey())
+		retryable = true
+	}
+
+	target := c.baseURL + req.Path
+	if len(req.Query) > 0 {
+		target += "?" + req.Query.Encode()
+	}
+
+	relogged := false
+	for attempt := 0; ; attempt++ {
+		if !req.Anonymous {
+			token, err := c.currentToken(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if token != "" {
+				headers.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		resp, err := c.send(ctx, req.Method, target, headers, req.Body)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var apiErr *Error
+		if err == nil {
+			apiErr = &Error{StatusCode: resp.StatusCode, Method: req.Method, Path: req.Path,
+				Message: strings.TrimSpace(string(resp.Body)), Body: resp.Body}
+			err = apiErr
+			// Токен відкликали або сервер перезапустився з іншим ключем -
+			// входимо заново один раз, це не витрачає спробу
+			if resp.StatusCode == http.StatusUnauthorized && !req.Anonymous && !relogged && c.canLogin() {
+				relogged = true
+				c.invalidate()
+				attempt--
+				continue
+			}
+		}
+
+		if !retryable || attempt >= c.opts.MaxRetries || !shouldRetry(resp, err) {
+			return nil, err
+		}
+		if err := sleep(ctx, c.delay(attempt, resp)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, headers http.Header, body []byte) (*Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header = headers.Clone()
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: data}, nil
+}
+
+// retryableMethod - методи, повтор яких не створює дублікатів
+func retryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry - мережева помилка (resp == nil) або тимчасова відповідь.
+// 409 з Retry-After - запит з тим самим Idempotency-Key сервер ще виконує.
+func shouldRetry(resp *Response, err error) bool {
+	if resp == nil {
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return resp.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+// delay - експоненційна пауза або Retry-After сервера, якщо він довший
+func (c *Client) delay(attempt int, resp *Response) time.Duration {
+	d := c.opts.Backoff << attempt
+	if d > c.opts.MaxBackoff || d <= 0 {
+		d = c.opts.MaxBackoff
+	}
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if after := time.Duration(seconds) * time.Second; after > d {
+				d = min(after, c.opts.MaxBackoff)
+			}
+		}
+	}
+	return d
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func newKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// call - Do з JSON-тілом in і розбором відповіді в out (якщо out != nil)
+func (c *Client) call(ctx context.Context, req Request, in, out interface{}) error {
+	if in != nil {
+		body, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		req.Body = body
+	}
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(resp.Body) == 0 {
+		return nil
+	}
+	if !resp.JSON() {
+		return errors.New("client: expected JSON from " + req.Method + " " + req.Path + ", got " + resp.Header.Get("Content-Type"))
+	}
+	return json.Unmarshal(resp.Body, out)
+}
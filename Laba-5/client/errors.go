@@ -0,0 +1,71 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Категорії помилок API для errors.Is: client.ErrNotFound тощо
+var (
+	ErrBadRequest    = errors.New("bad request")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrNotAcceptable = errors.New("not acceptable")
+	ErrValidation    = errors.New("validation failed")
+	ErrRateLimited   = errors.New("rate limited")
+	ErrUnavailable   = errors.New("service unavailable")
+	ErrServer        = errors.New("server error")
+)
+
+// Error - відповідь API з кодом поза 2xx. Message - текст помилки сервера,
+// Body - сире тіло (наприклад, JSON з порушеннями графіка для 422).
+type Error struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Message    string
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// Unwrap дає категорію за кодом статусу, тож працює errors.Is(err, ErrNotFound)
+func (e *Error) Unwrap() error {
+	return statusError(e.StatusCode)
+}
+
+func statusError(status int) error {
+	switch status {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusNotAcceptable:
+		return ErrNotAcceptable
+	case http.StatusUnprocessableEntity:
+		return ErrValidation
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return ErrUnavailable
+	}
+	if status >= 500 {
+		return ErrServer
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Фільтри списків. Поля з тегом query - це query-параметри API:
+// порожні рядки, нульові ObjectID, nil-вказівники і false не надсилаються.
+
+type HospitalFilter struct {
+	Name           string `query:"name"`
+	City           string `query:"city"`
+	Beds           *int   `query:"beds"`
+	MinBeds        *int   `query:"minBeds"`
+	MaxBeds        *int   `query:"maxBeds"`
+	IncludeDeleted bool   `query:"includeDeleted"`
+}
+
+type DepartmentFilter struct {
+	Name           string             `query:"name"`
+	HospitalID     primitive.ObjectID `query:"hospitalId"`
+	Floor          *int               `query:"floor"`
+	MinFloor       *int               `query:"minFloor"`
+	MaxFloor       *int               `query:"maxFloor"`
+	IncludeDeleted bool               `query:"includeDeleted"`
+}
+
+type DoctorFilter struct {
+	Name            string             `query:"name"`
+	Specialty       string             `query:"specialty"`
+	Department      primitive.ObjectID `query:"department"`
+	ExperienceYears *int               `query:"experience_years"`
+	MinExperience   *int               `query:"minExperience"`
+	MaxExperience   *int               `query:"maxExperience"`
+	IncludeDeleted  bool               `query:"includeDeleted"`
+}
+
+type StaffFilter struct {
+	Name           string             `query:"name"`
+	Role           string             `query:"role"`
+	Shift          string             `query:"shift"`
+	HospitalID     primitive.ObjectID `query:"hospitalId"`
+	DepartmentID   primitive.ObjectID `query:"departmentId"`
+	IncludeDeleted bool               `query:"includeDeleted"`
+}
+
+type MedicineFilter struct {
+	Name           string `query:"name"`
+	Dosage         string `query:"dosage"`
+	Manufacturer   string `query:"manufacturer"`
+	IncludeDeleted bool   `query:"includeDeleted"`
+}
+
+type AppointmentFilter struct {
+	PatientID primitive.ObjectID `query:"patientId"`
+	DoctorID  primitive.ObjectID `query:"doctorId"`
+	// Date - день у форматі YYYY-MM-DD
+	Date string `query:"date"`
+	// Status - один зі станів models.AppointmentStatuses
+	Status         string `query:"status"`
+	IncludeDeleted bool   `query:"includeDeleted"`
+}
+
+type SeriesFilter struct {
+	PatientID primitive.ObjectID `query:"patientId"`
+	DoctorID  primitive.ObjectID `query:"doctorId"`
+}
+
+type WardFilter struct {
+	HospitalID     primitive.ObjectID `query:"hospitalId"`
+	DepartmentID   primitive.ObjectID `query:"departmentId"`
+	IncludeDeleted bool               `query:"includeDeleted"`
+}
+
+type BedFilter struct {
+	HospitalID   primitive.ObjectID `query:"hospitalId"`
+	DepartmentID primitive.ObjectID `query:"departmentId"`
+	WardID       primitive.ObjectID `query:"wardId"`
+	// Free: true - лише вільні, false - лише зайняті, nil - усі
+	Free           *bool `query:"free"`
+	IncludeDeleted bool  `query:"includeDeleted"`
+}
+
+type AdmissionFilter struct {
+	PatientID    primitive.ObjectID `query:"patientId"`
+	HospitalID   primitive.ObjectID `query:"hospitalId"`
+	DepartmentID primitive.ObjectID `query:"departmentId"`
+	// Status - models.AdmissionActive або models.AdmissionDischarged
+	Status string `query:"status"`
+}
+
+type ShiftFilter struct {
+	HospitalID     primitive.ObjectID `query:"hospitalId"`
+	DepartmentID   primitive.ObjectID `query:"departmentId"`
+	IncludeDeleted bool               `query:"includeDeleted"`
+}
+
+type RosterFilter struct {
+	StaffID      primitive.ObjectID `query:"staffId"`
+	DepartmentID primitive.ObjectID `query:"departmentId"`
+	// From і To - межі включно, YYYY-MM-DD
+	From string `query:"from"`
+	To   string `query:"to"`
+}
+
+// Int - вказівник для числових полів фільтра: DoctorFilter{MinExperience: client.Int(5)}
+func Int(v int) *int { return &v }
+
+// Bool - вказівник для BedFilter.Free
+func Bool(v bool) *bool { return &v }
+
+var objectIDType = reflect.TypeOf(primitive.ObjectID{})
+
+// Values перетворює фільтр (структуру з тегами query) на query-параметри
+func Values(filter interface{}) url.Values {
+	query := url.Values{}
+	v := reflect.Indirect(reflect.ValueOf(filter))
+	if !v.IsValid() {
+		return query
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("query")
+		if name == "" {
+			continue
+		}
+		if value, ok := queryValue(v.Field(i)); ok {
+			query.Set(name, value)
+		}
+	}
+	return query
+}
+
+func queryValue(v reflect.Value) (string, bool) {
+	if v.Type() == objectIDType {
+		id := v.Interface().(primitive.ObjectID)
+		return id.Hex(), !id.IsZero()
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "", false
+		}
+		value, _ := queryValue(v.Elem())
+		return value, true
+	case reflect.String:
+		return v.String(), v.String() != ""
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), v.Bool()
+	}
+	return "", false
+}
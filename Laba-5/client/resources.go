@@ -0,0 +1,422 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"hospital-api/models"
+	"hospital-api/scheduling"
+	"hospital-api/search"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Спільні запити ресурсів; create - POST з Idempotency-Key, тож його
+// теж можна безпечно повторити
+
+func list[T any](ctx context.Context, c *Client, path string, filter interface{}) ([]T, error) {
+	var out []T
+	err := c.call(ctx, Request{Method: http.MethodGet, Path: path, Query: Values(filter)}, nil, &out)
+	return out, err
+}
+
+func get[T any](ctx context.Context, c *Client, path string, id primitive.ObjectID) (T, error) {
+	var out T
+	err := c.call(ctx, Request{Method: http.MethodGet, Path: itemPath(path, id)}, nil, &out)
+	return out, err
+}
+
+func create[T any](ctx context.Context, c *Client, path string, in interface{}) (T, error) {
+	var out T
+	err := c.call(ctx, Request{Method: http.MethodPost, Path: path, Idempotent: true}, in, &out)
+	return out, err
+}
+
+func (c *Client) update(ctx context.Context, path string, id primitive.ObjectID, in interface{}) error {
+	return c.call(ctx, Request{Method: http.MethodPut, Path: itemPath(path, id)}, in, nil)
+}
+
+func (c *Client) remove(ctx context.Context, path string, id primitive.ObjectID) error {
+	return c.call(ctx, Request{Method: http.MethodDelete, Path: itemPath(path, id)}, nil, nil)
+}
+
+func itemPath(path string, id primitive.ObjectID) string {
+	return path + "/" + id.Hex()
+}
+
+// ------------------ Лікарні (потрібен APIKey) ------------------
+
+func (c *Client) ListHospitals(ctx context.Context, filter HospitalFilter) ([]models.Hospital, error) {
+	return list[models.Hospital](ctx, c, "/hospitals", filter)
+}
+
+func (c *Client) GetHospital(ctx context.Context, id primitive.ObjectID) (models.Hospital, error) {
+	return get[models.Hospital](ctx, c, "/hospitals", id)
+}
+
+func (c *Client) CreateHospital(ctx context.Context, hospital models.Hospital) (models.Hospital, error) {
+	return create[models.Hospital](ctx, c, "/hospitals", hospital)
+}
+
+func (c *Client) UpdateHospital(ctx context.Context, id primitive.ObjectID, hospital models.Hospital) error {
+	return c.update(ctx, "/hospitals", id, hospital)
+}
+
+// DeleteHospital м'яко видаляє лікарню разом з відділеннями, палатами і персоналом
+func (c *Client) DeleteHospital(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/hospitals", id)
+}
+
+// ------------------ Відділення (потрібен APIKey) ------------------
+
+func (c *Client) ListDepartments(ctx context.Context, filter DepartmentFilter) ([]models.Department, error) {
+	return list[models.Department](ctx, c, "/departments", filter)
+}
+
+func (c *Client) GetDepartment(ctx context.Context, id primitive.ObjectID) (models.Department, error) {
+	return get[models.Department](ctx, c, "/departments", id)
+}
+
+func (c *Client) CreateDepartment(ctx context.Context, department models.Department) (models.Department, error) {
+	return create[models.Department](ctx, c, "/departments", department)
+}
+
+func (c *Client) UpdateDepartment(ctx context.Context, id primitive.ObjectID, department models.Department) error {
+	return c.update(ctx, "/departments", id, department)
+}
+
+func (c *Client) DeleteDepartment(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/departments", id)
+}
+
+// ------------------ Лікарі (потрібен APIKey) ------------------
+
+func (c *Client) ListDoctors(ctx context.Context, filter DoctorFilter) ([]models.Doctor, error) {
+	return list[models.Doctor](ctx, c, "/doctors", filter)
+}
+
+func (c *Client) GetDoctor(ctx context.Context, id primitive.ObjectID) (models.Doctor, error) {
+	return get[models.Doctor](ctx, c, "/doctors", id)
+}
+
+func (c *Client) CreateDoctor(ctx context.Context, doctor models.Doctor) (models.Doctor, error) {
+	return create[models.Doctor](ctx, c, "/doctors", doctor)
+}
+
+func (c *Client) UpdateDoctor(ctx context.Context, id primitive.ObjectID, doctor models.Doctor) error {
+	return c.update(ctx, "/doctors", id, doctor)
+}
+
+func (c *Client) DeleteDoctor(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/doctors", id)
+}
+
+// ------------------ Персонал ------------------
+
+func (c *Client) ListStaff(ctx context.Context, filter StaffFilter) ([]models.Staff, error) {
+	return list[models.Staff](ctx, c, "/staff", filter)
+}
+
+func (c *Client) GetStaffMember(ctx context.Context, id primitive.ObjectID) (models.Staff, error) {
+	return get[models.Staff](ctx, c, "/staff", id)
+}
+
+func (c *Client) CreateStaffMember(ctx context.Context, member models.Staff) (models.Staff, error) {
+	return create[models.Staff](ctx, c, "/staff", member)
+}
+
+func (c *Client) UpdateStaffMember(ctx context.Context, id primitive.ObjectID, member models.Staff) error {
+	return c.update(ctx, "/staff", id, member)
+}
+
+func (c *Client) DeleteStaffMember(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/staff", id)
+}
+
+// ------------------ Ліки ------------------
+
+func (c *Client) ListMedications(ctx context.Context, filter MedicineFilter) ([]models.Medicine, error) {
+	return list[models.Medicine](ctx, c, "/medications", filter)
+}
+
+func (c *Client) GetMedicine(ctx context.Context, id primitive.ObjectID) (models.Medicine, error) {
+	return get[models.Medicine](ctx, c, "/medications", id)
+}
+
+func (c *Client) CreateMedicine(ctx context.Context, medicine models.Medicine) (models.Medicine, error) {
+	return create[models.Medicine](ctx, c, "/medications", medicine)
+}
+
+func (c *Client) UpdateMedicine(ctx context.Context, id primitive.ObjectID, medicine models.Medicine) error {
+	return c.update(ctx, "/medications", id, medicine)
+}
+
+func (c *Client) DeleteMedicine(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/medications", id)
+}
+
+// DispenseMedicine видає ліки пацієнту; без залишку - ErrConflict
+func (c *Client) DispenseMedicine(ctx context.Context, medicineID primitive.ObjectID, dispensation models.Dispensation) (models.Dispensation, error) {
+	return create[models.Dispensation](ctx, c, itemPath("/medications", medicineID)+"/dispense", dispensation)
+}
+
+// ------------------ Записи до лікаря ------------------
+
+func (c *Client) ListAppointments(ctx context.Context, filter AppointmentFilter) ([]models.Appointment, error) {
+	return list[models.Appointment](ctx, c, "/appointments", filter)
+}
+
+func (c *Client) GetAppointment(ctx context.Context, id primitive.ObjectID) (models.Appointment, error) {
+	return get[models.Appointment](ctx, c, "/appointments", id)
+}
+
+// CreateAppointment записує пацієнта; зайнятий слот лікаря - ErrConflict
+func (c *Client) CreateAppointment(ctx context.Context, appointment models.Appointment) (models.Appointment, error) {
+	return create[models.Appointment](ctx, c, "/appointments", appointment)
+}
+
+func (c *Client) UpdateAppointment(ctx context.Context, id primitive.ObjectID, appointment models.Appointment) error {
+	return c.update(ctx, "/appointments", id, appointment)
+}
+
+func (c *Client) DeleteAppointment(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/appointments", id)
+}
+
+// TransitionAppointment виконує дію життєвого циклу (models.AppointmentTransitions,
+// наприклад "check-in"); недозволений для поточного стану перехід - ErrConflict
+func (c *Client) TransitionAppointment(ctx context.Context, id primitive.ObjectID, action string) (models.Appointment, error) {
+	return create[models.Appointment](ctx, c, itemPath("/appointments", id)+"/"+action, nil)
+}
+
+// CancelAppointment скасовує запис; причина обов'язкова
+func (c *Client) CancelAppointment(ctx context.Context, id primitive.ObjectID, reason string) (models.Appointment, error) {
+	return create[models.Appointment](ctx, c, itemPath("/appointments", id)+"/cancel", models.AppointmentCancellation{Reason: reason})
+}
+
+// ------------------ Серії повторюваних записів ------------------
+
+// Series - серія разом з її записами за датою
+type Series struct {
+	Series       models.AppointmentSeries `json:"series"`
+	Appointments []models.Appointment     `json:"appointments"`
+}
+
+func (c *Client) ListSeries(ctx context.Context, filter SeriesFilter) ([]models.AppointmentSeries, error) {
+	return list[models.AppointmentSeries](ctx, c, "/appointment-series", filter)
+}
+
+func (c *Client) GetSeries(ctx context.Context, id primitive.ObjectID) (Series, error) {
+	return get[Series](ctx, c, "/appointment-series", id)
+}
+
+// CreateSeries бронює всі записи серії; якщо лікар зайнятий хоч на один - ErrConflict
+func (c *Client) CreateSeries(ctx context.Context, series models.AppointmentSeries) (Series, error) {
+	return create[Series](ctx, c, "/appointment-series", series)
+}
+
+// RescheduleOccurrence переносить запис серії; following - разом з усіма наступними
+func (c *Client) RescheduleOccurrence(ctx context.Context, seriesID, appointmentID primitive.ObjectID, change models.OccurrenceChange, following bool) (Series, error) {
+	var out Series
+	err := c.call(ctx, Request{Method: http.MethodPut, Path: occurrencePath(seriesID, appointmentID), Query: scopeQuery(following)}, change, &out)
+	return out, err
+}
+
+// CancelOccurrence скасовує запис серії; following - разом з усіма наступними
+func (c *Client) CancelOccurrence(ctx context.Context, seriesID, appointmentID primitive.ObjectID, reason string, following bool) (Series, error) {
+	var out Series
+	req := Request{Method: http.MethodPost, Path: occurrencePath(seriesID, appointmentID) + "/cancel", Query: scopeQuery(following), Idempotent: true}
+	err := c.call(ctx, req, models.AppointmentCancellation{Reason: reason}, &out)
+	return out, err
+}
+
+func occurrencePath(seriesID, appointmentID primitive.ObjectID) string {
+	return itemPath("/appointment-series", seriesID) + "/occurrences/" + appointmentID.Hex()
+}
+
+func scopeQuery(following bool) url.Values {
+	if following {
+		return url.Values{"scope": {"following"}}
+	}
+	return nil
+}
+
+// ------------------ Палати й ліжка ------------------
+
+func (c *Client) ListWards(ctx context.Context, filter WardFilter) ([]models.Ward, error) {
+	return list[models.Ward](ctx, c, "/wards", filter)
+}
+
+func (c *Client) GetWard(ctx context.Context, id primitive.ObjectID) (models.Ward, error) {
+	return get[models.Ward](ctx, c, "/wards", id)
+}
+
+func (c *Client) CreateWard(ctx context.Context, ward models.Ward) (models.Ward, error) {
+	return create[models.Ward](ctx, c, "/wards", ward)
+}
+
+func (c *Client) DeleteWard(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/wards", id)
+}
+
+func (c *Client) ListBeds(ctx context.Context, filter BedFilter) ([]models.Bed, error) {
+	return list[models.Bed](ctx, c, "/beds", filter)
+}
+
+func (c *Client) GetBed(ctx context.Context, id primitive.ObjectID) (models.Bed, error) {
+	return get[models.Bed](ctx, c, "/beds", id)
+}
+
+func (c *Client) CreateBed(ctx context.Context, bed models.Bed) (models.Bed, error) {
+	return create[models.Bed](ctx, c, "/beds", bed)
+}
+
+func (c *Client) DeleteBed(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/beds", id)
+}
+
+// ------------------ Госпіталізації ------------------
+
+// BedRequest - куди покласти пацієнта: конкретне ліжко або будь-яке
+// вільне у відділенні
+type BedRequest struct {
+	PatientID    primitive.ObjectID `json:"patientId,omitempty"`
+	BedID        primitive.ObjectID `json:"bedId,omitempty"`
+	DepartmentID primitive.ObjectID `json:"departmentId,omitempty"`
+}
+
+func (c *Client) ListAdmissions(ctx context.Context, filter AdmissionFilter) ([]models.Admission, error) {
+	return list[models.Admission](ctx, c, "/admissions", filter)
+}
+
+func (c *Client) GetAdmission(ctx context.Context, id primitive.ObjectID) (models.Admission, error) {
+	return get[models.Admission](ctx, c, "/admissions", id)
+}
+
+// AdmitPatient госпіталізує пацієнта; вільних ліжок немає - ErrConflict
+func (c *Client) AdmitPatient(ctx context.Context, req BedRequest) (models.Admission, error) {
+	return create[models.Admission](ctx, c, "/admissions", req)
+}
+
+func (c *Client) TransferPatient(ctx context.Context, admissionID primitive.ObjectID, req BedRequest) (models.Admission, error) {
+	return create[models.Admission](ctx, c, itemPath("/admissions", admissionID)+"/transfer", req)
+}
+
+func (c *Client) DischargePatient(ctx context.Context, admissionID primitive.ObjectID) (models.Admission, error) {
+	return create[models.Admission](ctx, c, itemPath("/admissions", admissionID)+"/discharge", nil)
+}
+
+// ------------------ Зміни та графік ------------------
+
+func (c *Client) ListShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error) {
+	return list[models.Shift](ctx, c, "/shifts", filter)
+}
+
+func (c *Client) GetShift(ctx context.Context, id primitive.ObjectID) (models.Shift, error) {
+	return get[models.Shift](ctx, c, "/shifts", id)
+}
+
+func (c *Client) CreateShift(ctx context.Context, shift models.Shift) (models.Shift, error) {
+	return create[models.Shift](ctx, c, "/shifts", shift)
+}
+
+func (c *Client) UpdateShift(ctx context.Context, id primitive.ObjectID, shift models.Shift) error {
+	return c.update(ctx, "/shifts", id, shift)
+}
+
+func (c *Client) DeleteShift(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/shifts", id)
+}
+
+func (c *Client) ListRoster(ctx context.Context, filter RosterFilter) ([]models.RosterEntry, error) {
+	return list[models.RosterEntry](ctx, c, "/roster", filter)
+}
+
+// RosterAssignment - призначення працівника на зміну в день date (YYYY-MM-DD)
+type RosterAssignment struct {
+	StaffID primitive.ObjectID `json:"staffId"`
+	ShiftID primitive.ObjectID `json:"shiftId"`
+	Date    string             `json:"date"`
+}
+
+// AssignShift додає запис у графік. Якщо він порушує правила відпочинку
+// чи тижневих годин, повертається ErrValidation з порушеннями (див. Violations);
+// force зберігає запис попри них.
+func (c *Client) AssignShift(ctx context.Context, assignment RosterAssignment, force bool) (models.RosterEntry, error) {
+	var out models.RosterEntry
+	req := Request{Method: http.MethodPost, Path: "/roster", Idempotent: true}
+	if force {
+		req.Query = url.Values{"force": {"true"}}
+	}
+	err := c.call(ctx, req, assignment, &out)
+	return out, err
+}
+
+func (c *Client) DeleteRosterEntry(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/roster", id)
+}
+
+// Violations дістає порушення правил графіка з помилки AssignShift
+func Violations(err error) ([]scheduling.Violation, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		return nil, false
+	}
+	var rejection struct {
+		Violations []scheduling.Violation `json:"violations"`
+	}
+	if json.Unmarshal(apiErr.Body, &rejection) != nil || len(rejection.Violations) == 0 {
+		return nil, false
+	}
+	return rejection.Violations, true
+}
+
+// ------------------ Вебхуки (лише admin) ------------------
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return list[models.Webhook](ctx, c, "/webhooks", nil)
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id primitive.ObjectID) (models.Webhook, error) {
+	return get[models.Webhook](ctx, c, "/webhooks", id)
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, hook models.Webhook) (models.Webhook, error) {
+	return create[models.Webhook](ctx, c, "/webhooks", hook)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id primitive.ObjectID, hook models.Webhook) error {
+	return c.update(ctx, "/webhooks", id, hook)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	return c.remove(ctx, "/webhooks", id)
+}
+
+// ------------------ Пошук ------------------
+
+// SearchResult - відповідь /search
+type SearchResult struct {
+	Query string       `json:"query"`
+	Hits  []search.Hit `json:"hits"`
+}
+
+// Search шукає по лікарях, персоналу, ліках і відділеннях; types обмежує
+// ресурси, limit <= 0 - значення сервера
+func (c *Client) Search(ctx context.Context, query string, types []string, limit int) (SearchResult, error) {
+	q := url.Values{"q": {query}}
+	if len(types) > 0 {
+		q.Set("type", strings.Join(types, ","))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var out SearchResult
+	err := c.call(ctx, Request{Method: http.MethodGet, Path: "/search", Query: q}, nil, &out)
+	return out, err
+}
@@ -0,0 +1,12 @@
+// hospitalctl - командний клієнт hospital-api (див. пакет hospitalctl)
+package main
+
+import (
+	"os"
+
+	"hospital-api/hospitalctl"
+)
+
+func main() {
+	os.Exit(hospitalctl.Main())
+}
@@ -0,0 +1,294 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Колекція, у якій зберігаються застосовані міграції
+const migrationsCollection = "schema_migrations"
+
+// ErrUnknownMigration - у базі застосовано міграцію, якої немає в коді
+var ErrUnknownMigration = errors.New("database has migrations unknown to this build")
+
+// Migration - один версійований крок схеми.
+// Up і Down мають бути ідемпотентними: повторний запуск не повинен падати.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, database *mongo.Database) error
+	Down    func(ctx context.Context, database *mongo.Database) error
+}
+
+// MigrationStatus описує стан міграції для команди status.
+// Unknown - застосована в базі версія, якої немає в цій збірці.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	Unknown   bool       `json:"unknown,omitempty"`
+}
+
+// MigrationRecord - запис про застосовану міграцію
+type MigrationRecord struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// MigrationLog зберігає, які міграції застосовано. У базі це колекція
+// schema_migrations, у тестах - підробка в пам'яті.
+type MigrationLog interface {
+	Applied(ctx context.Context) (map[int]MigrationRecord, error)
+	Record(ctx context.Context, rec MigrationRecord) error
+	Forget(ctx context.Context, version int) error
+}
+
+// Migrator застосовує й відкочує Steps над Database, ведучи облік у Log
+type Migrator struct {
+	Database *mongo.Database
+	Steps    []Migration
+	Log      MigrationLog
+}
+
+// NewMigrator - мігратор з усіма відомими міграціями й обліком у самій базі
+func NewMigrator(database *mongo.Database) *Migrator {
+	return &Migrator{Database: database, Steps: Migrations(), Log: collectionLog{database}}
+}
+
+// Migrations повертає всі відомі міграції, відсортовані за версією
+func Migrations() []Migration {
+	return sortedMigrations(migrations)
+}
+
+func sortedMigrations(steps []Migration) []Migration {
+	sorted := make([]Migration, len(steps))
+	copy(sorted, steps)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+// applied читає облік і відмовляє, якщо в базі є версії, яких ця збірка
+// не знає: база новіша за код, і будь-який крок може її зіпсувати
+func (mg *Migrator) applied(ctx context.Context) (map[int]MigrationRecord, error) {
+	applied, err := mg.Log.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]bool, len(mg.Steps))
+	for _, m := range mg.Steps {
+		known[m.Version] = true
+	}
+	var unknown []int
+	for version := range applied {
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Ints(unknown)
+		return nil, fmt.Errorf("%w: %v", ErrUnknownMigration, unknown)
+	}
+	return applied, nil
+}
+
+// Up застосовує всі ще не застосовані міграції по черзі
+func (mg *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := mg.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range sortedMigrations(mg.Steps) {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := m.Up(ctx, mg.Database); err != nil {
+			return done, fmt.Errorf("migration %d (%s) up: %w", m.Version, m.Name, err)
+		}
+		rec := MigrationRecord{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}
+		if err := mg.Log.Record(ctx, rec); err != nil {
+			return done, fmt.Errorf("migration %d (%s) record: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down відкочує steps останніх застосованих міграцій
+func (mg *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := mg.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all := sortedMigrations(mg.Steps)
+	var done []Migration
+	for i := len(all) - 1; i >= 0 && len(done) < steps; i-- {
+		m := all[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := m.Down(ctx, mg.Database); err != nil {
+			return done, fmt.Errorf("migration %d (%s) down: %w", m.Version, m.Name, err)
+		}
+		if err := mg.Log.Forget(ctx, m.Version); err != nil {
+			return done, fmt.Errorf("migration %d (%s) record: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Status повертає стан кожної відомої міграції, а наприкінці - застосовані
+// версії, яких ця збірка не знає (з позначкою Unknown)
+func (mg *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := mg.Log.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, m := range sortedMigrations(mg.Steps) {
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if rec, ok := applied[m.Version]; ok {
+			at := rec.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, st)
+	}
+	var unknown []MigrationStatus
+	for _, rec := range applied {
+		at := rec.AppliedAt
+		unknown = append(unknown, MigrationStatus{Version: rec.Version, Name: rec.Name, Applied: true, AppliedAt: &at, Unknown: true})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Version < unknown[j].Version })
+	return append(statuses, unknown...), nil
+}
+
+// MigrateUp застосовує всі ще не застосовані міграції по черзі
+func MigrateUp(ctx context.Context, database *mongo.Database) ([]Migration, error) {
+	return NewMigrator(database).Up(ctx)
+}
+
+// MigrateDown відкочує steps останніх застосованих міграцій
+func MigrateDown(ctx context.Context, database *mongo.Database, steps int) ([]Migration, error) {
+	return NewMigrator(database).Down(ctx, steps)
+}
+
+// MigrationsStatus повертає стан кожної відомої міграції
+func MigrationsStatus(ctx context.Context, database *mongo.Database) ([]MigrationStatus, error) {
+	return NewMigrator(database).Status(ctx)
+}
+
+// collectionLog - облік міграцій у колекції schema_migrations
+type collectionLog struct {
+	database *mongo.Database
+}
+
+func (l collectionLog) Applied(ctx context.Context) (map[int]MigrationRecord, error) {
+	cursor, err := l.database.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []MigrationRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]MigrationRecord, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+func (l collectionLog) Record(ctx context.Context, rec MigrationRecord) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := l.database.Collection(migrationsCollection).ReplaceOne(ctx, bson.M{"_id": rec.Version}, rec, opts)
+	return err
+}
+
+func (l collectionLog) Forget(ctx context.Context, version int) error {
+	_, err := l.database.Collection(migrationsCollection).DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
+
+// --- Допоміжні кроки для міграцій ---
+
+// Коди помилок MongoDB, які означають "вже зроблено"
+const (
+	codeNamespaceNotFound = 26
+	codeIndexNotFound     = 27
+	codeNamespaceExists   = 48
+)
+
+func hasErrorCode(err error, code int) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return int(cmdErr.Code) == code
+	}
+	return false
+}
+
+func createIndexes(ctx context.Context, database *mongo.Database, collection string, indexes ...mongo.IndexModel) error {
+	_, err := database.Collection(collection).Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+func dropIndexes(ctx context.Context, database *mongo.Database, collection string, names ...string) error {
+	for _, name := range names {
+		_, err := database.Collection(collection).Indexes().DropOne(ctx, name)
+		if err != nil && !hasErrorCode(err, codeIndexNotFound) && !hasErrorCode(err, codeNamespaceNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func ensureCollection(ctx context.Context, database *mongo.Database, collection string) error {
+	err := database.CreateCollection(ctx, collection)
+	if err != nil && !hasErrorCode(err, codeNamespaceExists) {
+		return err
+	}
+	return nil
+}
+
+// setValidator встановлює $jsonSchema валідатор. Рівень moderate не чіпає
+// вже наявні невалідні документи, поки їх не оновлять.
+func setValidator(ctx context.Context, database *mongo.Database, collection string, schema bson.M) error {
+	if err := ensureCollection(ctx, database, collection); err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: bson.M{"$jsonSchema": schema}},
+		{Key: "validationLevel", Value: "moderate"},
+	}
+	return database.RunCommand(ctx, cmd).Err()
+}
+
+func removeValidator(ctx context.Context, database *mongo.Database, collection string) error {
+	cmd := bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: bson.M{}},
+	}
+	err := database.RunCommand(ctx, cmd).Err()
+	if err != nil && !hasErrorCode(err, codeNamespaceNotFound) {
+		return err
+	}
+	return nil
+}
@@ -3,22 +3,86 @@ package db
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Назва бази, з якою працює API
+const DatabaseName = "hospital_db"
+
 var Client *mongo.Client
 
 func Connect(uri string) *mongo.Client {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(writeMonitor()))
 	if err != nil {
 		log.Fatal(err)
 	}
 	Client = client
 	return client
 }
+
+// Database повертає основну базу hospital_db
+func Database() *mongo.Database {
+	return Client.Database(DatabaseName)
+}
+
+// Collection повертає колекцію з основної бази
+func Collection(name string) *mongo.Collection {
+	return Database().Collection(name)
+}
+
+// writeCommands - команди, що змінюють колекцію
+var writeCommands = map[string]bool{
+	"insert": true, "update": true, "delete": true, "findAndModify": true, "drop": true,
+}
+
+var (
+	writeMu        sync.RWMutex
+	writeListeners []func(collection string)
+	pendingWrites  sync.Map // RequestID команди → колекція
+)
+
+// OnWrite реєструє fn, яку викликають після кожного запису в колекцію
+// основної бази через цей клієнт. Запис у транзакції сповіщається одразу,
+// ще до коміту.
+func OnWrite(fn func(collection string)) {
+	writeMu.Lock()
+	writeListeners = append(writeListeners, fn)
+	writeMu.Unlock()
+}
+
+func writeMonitor() *event.CommandMonitor {
+	// Сповіщаємо після відповіді сервера, а не на старті: інакше читач
+	// між стартом і записом закешував би ще старі дані
+	finished := func(requestID int64) {
+		collection, ok := pendingWrites.LoadAndDelete(requestID)
+		if !ok {
+			return
+		}
+		writeMu.RLock()
+		defer writeMu.RUnlock()
+		for _, fn := range writeListeners {
+			fn(collection.(string))
+		}
+	}
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if !writeCommands[e.CommandName] || e.DatabaseName != DatabaseName {
+				return
+			}
+			if collection, ok := e.Command.Lookup(e.CommandName).StringValueOK(); ok {
+				pendingWrites.Store(e.RequestID, collection)
+			}
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) { finished(e.RequestID) },
+		// Невдала команда могла встигнути частину записів (insertMany)
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) { finished(e.RequestID) },
+	}
+}
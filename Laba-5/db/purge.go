@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SoftDeleteCollections - колекції, де DELETE лише позначає документ
+// полями deletedAt/deletedBy
+var SoftDeleteCollections = []string{
+	"hospitals", "departments", "doctors", "staff", "medications",
+	"appointments", "wards", "beds", "shifts",
+}
+
+// DefaultRetention - скільки зберігати видалені документи до purge
+const DefaultRetention = 90 * 24 * time.Hour
+
+// PurgeDeleted назавжди видаляє документи, позначені видаленими раніше за
+// before, і повертає кількість видалених по колекціях
+func PurgeDeleted(ctx context.Context, database *mongo.Database, before time.Time) (map[string]int64, error) {
+	purged := map[string]int64{}
+	for _, name := range SoftDeleteCollections {
+		res, err := database.Collection(name).DeleteMany(ctx, bson.M{"deletedAt": bson.M{"$lt": before}})
+		if err != nil {
+			return purged, err
+		}
+		if res.DeletedCount > 0 {
+			purged[name] = res.DeletedCount
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,480 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Усі міграції схеми hospital_db. Нові кроки додаються в кінець
+// з наступним номером версії; вже застосовані кроки не змінюються.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "filter_indexes",
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := createIndexes(ctx, database, "appointments",
+				mongo.IndexModel{Keys: bson.D{{Key: "doctorId", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetName("doctorId_date")},
+				mongo.IndexModel{Keys: bson.D{{Key: "patientId", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetName("patientId_date")},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, database, "departments",
+				mongo.IndexModel{Keys: bson.D{{Key: "hospital_id", Value: 1}}, Options: options.Index().SetName("hospital_id")},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, database, "doctors",
+				mongo.IndexModel{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("name")},
+				mongo.IndexModel{Keys: bson.D{{Key: "department", Value: 1}}, Options: options.Index().SetName("department")},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, database, "medications",
+				mongo.IndexModel{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("name")},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "staff",
+				mongo.IndexModel{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetName("name")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "appointments", "doctorId_date", "patientId_date"); err != nil {
+				return err
+			}
+			if err := dropIndexes(ctx, database, "departments", "hospital_id"); err != nil {
+				return err
+			}
+			if err := dropIndexes(ctx, database, "doctors", "name", "department"); err != nil {
+				return err
+			}
+			if err := dropIndexes(ctx, database, "medications", "name"); err != nil {
+				return err
+			}
+			return dropIndexes(ctx, database, "staff", "name")
+		},
+	},
+	{
+		Version: 2,
+		Name:    "json_schema_validators",
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			for collection, schema := range validators {
+				if err := setValidator(ctx, database, collection, schema); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			for collection := range validators {
+				if err := removeValidator(ctx, database, collection); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 3,
+		Name:    "doctor_department_reference",
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := convertDoctorDepartments(ctx, database); err != nil {
+				return err
+			}
+			if err := setValidator(ctx, database, "doctors", doctorsSchema("objectId")); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "staff",
+				mongo.IndexModel{Keys: bson.D{{Key: "hospital_id", Value: 1}}, Options: options.Index().SetName("hospital_id")},
+				mongo.IndexModel{Keys: bson.D{{Key: "department_id", Value: 1}}, Options: options.Index().SetName("department_id")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "staff", "hospital_id", "department_id"); err != nil {
+				return err
+			}
+			// Повертаємо рядкове поле department
+			_, err := database.Collection("doctors").UpdateMany(ctx,
+				bson.M{"department": bson.M{"$type": "objectId"}},
+				bson.A{bson.M{"$set": bson.M{"department": bson.M{"$toString": "$department"}}}},
+			)
+			if err != nil {
+				return err
+			}
+			return setValidator(ctx, database, "doctors", doctorsSchema("string"))
+		},
+	},
+	{
+		Version: 4,
+		Name:    "wards_beds_admissions",
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := createIndexes(ctx, database, "wards",
+				mongo.IndexModel{Keys: bson.D{{Key: "department_id", Value: 1}}, Options: options.Index().SetName("department_id")},
+				mongo.IndexModel{Keys: bson.D{{Key: "hospital_id", Value: 1}}, Options: options.Index().SetName("hospital_id")},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, database, "beds",
+				mongo.IndexModel{Keys: bson.D{{Key: "ward_id", Value: 1}}, Options: options.Index().SetName("ward_id")},
+				mongo.IndexModel{Keys: bson.D{{Key: "department_id", Value: 1}, {Key: "patientId", Value: 1}}, Options: options.Index().SetName("department_id_patientId")},
+				mongo.IndexModel{Keys: bson.D{{Key: "hospital_id", Value: 1}}, Options: options.Index().SetName("hospital_id")},
+			); err != nil {
+				return err
+			}
+			// Один пацієнт - не більше однієї активної госпіталізації
+			return createIndexes(ctx, database, "admissions",
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "patientId", Value: 1}},
+					Options: options.Index().SetName("active_patient").SetUnique(true).
+						SetPartialFilterExpression(bson.M{"status": "active"}),
+				},
+				mongo.IndexModel{Keys: bson.D{{Key: "department_id", Value: 1}, {Key: "status", Value: 1}}, Options: options.Index().SetName("department_id_status")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "wards", "department_id", "hospital_id"); err != nil {
+				return err
+			}
+			if err := dropIndexes(ctx, database, "beds", "ward_id", "department_id_patientId", "hospital_id"); err != nil {
+				return err
+			}
+			return dropIndexes(ctx, database, "admissions", "active_patient", "department_id_status")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "shifts_roster",
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := createIndexes(ctx, database, "shifts",
+				mongo.IndexModel{Keys: bson.D{{Key: "department_id", Value: 1}}, Options: options.Index().SetName("department_id")},
+			); err != nil {
+				return err
+			}
+			// Працівник не може стояти на одній зміні двічі за день
+			return createIndexes(ctx, database, "roster",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "staffId", Value: 1}, {Key: "date", Value: 1}, {Key: "shiftId", Value: 1}},
+					Options: options.Index().SetName("staffId_date_shiftId").SetUnique(true),
+				},
+				mongo.IndexModel{Keys: bson.D{{Key: "department_id", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetName("department_id_date")},
+				mongo.IndexModel{Keys: bson.D{{Key: "shiftId", Value: 1}}, Options: options.Index().SetName("shiftId")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "shifts", "department_id"); err != nil {
+				return err
+			}
+			return dropIndexes(ctx, database, "roster", "staffId_date_shiftId", "department_id_date", "shiftId")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "webhooks",
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := createIndexes(ctx, database, "webhooks",
+				mongo.IndexModel{Keys: bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}}, Options: options.Index().SetName("events_active")},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "webhook_dead_letters",
+				mongo.IndexModel{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "failedAt", Value: -1}}, Options: options.Index().SetName("webhookId_failedAt")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "webhooks", "events_active"); err != nil {
+				return err
+			}
+			return dropIndexes(ctx, database, "webhook_dead_letters", "webhookId_failedAt")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "soft_delete",
+		// Індекс лише по видалених документах - для purge і ?includeDeleted
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			for _, name := range SoftDeleteCollections {
+				if err := createIndexes(ctx, database, name, mongo.IndexModel{
+					Keys: bson.D{{Key: "deletedAt", Value: 1}},
+					Options: options.Index().SetName("deletedAt").
+						SetPartialFilterExpression(bson.M{"deletedAt": bson.M{"$exists": true}}),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			for _, name := range SoftDeleteCollections {
+				if err := dropIndexes(ctx, database, name, "deletedAt"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 8,
+		Name:    "idempotency_keys",
+		// TTL-індекс: Mongo сам видаляє ключі після expiresAt
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			return createIndexes(ctx, database, "idempotency_keys", mongo.IndexModel{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+			})
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			return dropIndexes(ctx, database, "idempotency_keys", "expiresAt_ttl")
+		},
+	},
+	{
+		Version: 9,
+		Name:    "dispensations",
+		// booking_locks створюємо заздалегідь: у транзакції на старих
+		// серверах колекцію неявно створити не можна
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := ensureCollection(ctx, database, "booking_locks"); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "dispensations",
+				mongo.IndexModel{Keys: bson.D{{Key: "medicineId", Value: 1}, {Key: "dispensedAt", Value: -1}}, Options: options.Index().SetName("medicineId_dispensedAt")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			return dropIndexes(ctx, database, "dispensations", "medicineId_dispensedAt")
+		},
+	},
+	{
+		Version: 10,
+		Name:    "appointment_status",
+		// Наявні записи стають booked; час переходів з'являється лише в нових
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			_, err := database.Collection("appointments").UpdateMany(ctx,
+				bson.M{"status": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"status": "booked"}},
+			)
+			if err != nil {
+				return err
+			}
+			if err := setValidator(ctx, database, "appointments", appointmentsSchema(true)); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "appointments",
+				mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetName("status_date")},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "appointments", "status_date"); err != nil {
+				return err
+			}
+			return setValidator(ctx, database, "appointments", appointmentsSchema(false))
+		},
+	},
+	{
+		Version: 11,
+		Name:    "appointment_series",
+		// Серію вставляють у транзакції разом із записами, тож колекція
+		// має існувати заздалегідь
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := ensureCollection(ctx, database, "appointment_series"); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, database, "appointment_series",
+				mongo.IndexModel{Keys: bson.D{{Key: "patientId", Value: 1}, {Key: "start", Value: 1}}, Options: options.Index().SetName("patientId_start")},
+				mongo.IndexModel{Keys: bson.D{{Key: "doctorId", Value: 1}, {Key: "start", Value: 1}}, Options: options.Index().SetName("doctorId_start")},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "appointments",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "seriesId", Value: 1}, {Key: "date", Value: 1}},
+					Options: options.Index().SetName("seriesId_date").SetPartialFilterExpression(bson.M{"seriesId": bson.M{"$exists": true}}),
+				},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "appointments", "seriesId_date"); err != nil {
+				return err
+			}
+			return dropIndexes(ctx, database, "appointment_series", "patientId_start", "doctorId_start")
+		},
+	},
+	{
+		Version: 12,
+		Name:    "calendar",
+		// unavailability читає checkSlot у транзакції запису; uid унікальний
+		// для лікаря, щоб повторний імпорт .ics замінював блоки
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if err := ensureCollection(ctx, database, "unavailability"); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, database, "unavailability",
+				mongo.IndexModel{Keys: bson.D{{Key: "doctorId", Value: 1}, {Key: "start", Value: 1}}, Options: options.Index().SetName("doctorId_start")},
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "doctorId", Value: 1}, {Key: "uid", Value: 1}},
+					Options: options.Index().SetName("doctorId_uid").SetUnique(true).
+						SetPartialFilterExpression(bson.M{"uid": bson.M{"$exists": true}}),
+				},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "calendar_tokens",
+				mongo.IndexModel{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetName("tokenHash").SetUnique(true)},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			if err := dropIndexes(ctx, database, "calendar_tokens", "tokenHash"); err != nil {
+				return err
+			}
+			return dropIndexes(ctx, database, "unavailability", "doctorId_start", "doctorId_uid")
+		},
+	},
+	{
+		Version: 13,
+		Name:    "calendar_feed_tokens",
+		// Токен тепер відкриває одну стрічку. Старі токени (_id - ім'я
+		// користувача) ні до якої стрічки не прив'язані, тож видаляються
+		Up: func(ctx context.Context, database *mongo.Database) error {
+			if _, err := database.Collection("calendar_tokens").DeleteMany(ctx, bson.M{"feed": bson.M{"$exists": false}}); err != nil {
+				return err
+			}
+			return createIndexes(ctx, database, "calendar_tokens",
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "username", Value: 1}, {Key: "feed", Value: 1}, {Key: "feedId", Value: 1}},
+					Options: options.Index().SetName("username_feed_feedId").SetUnique(true),
+				},
+			)
+		},
+		Down: func(ctx context.Context, database *mongo.Database) error {
+			return dropIndexes(ctx, database, "calendar_tokens", "username_feed_feedId")
+		},
+	},
+}
+
+// convertDoctorDepartments перетворює рядкове doctors.department на ObjectID.
+// Рядок може бути hex-ідентифікатором або назвою відділення; якщо
+// відділення не знайдено, поле прибирається.
+func convertDoctorDepartments(ctx context.Context, database *mongo.Database) error {
+	doctors := database.Collection("doctors")
+	cursor, err := doctors.Find(ctx, bson.M{"department": bson.M{"$type": "string"}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			Department string             `bson:"department"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		update := bson.M{"$unset": bson.M{"department": ""}}
+		if depID, err := primitive.ObjectIDFromHex(doc.Department); err == nil {
+			update = bson.M{"$set": bson.M{"department": depID}}
+		} else if doc.Department != "" {
+			var dep struct {
+				ID primitive.ObjectID `bson:"_id"`
+			}
+			nameFilter := bson.M{"name": doc.Department}
+			opts := options.FindOne().SetCollation(&options.Collation{Locale: "uk", Strength: 2})
+			if err := database.Collection("departments").FindOne(ctx, nameFilter, opts).Decode(&dep); err == nil {
+				update = bson.M{"$set": bson.M{"department": dep.ID}}
+			} else {
+				log.Printf("doctor %s: department %q not found, reference removed", doc.ID.Hex(), doc.Department)
+			}
+		}
+
+		if _, err := doctors.UpdateByID(ctx, doc.ID, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Типи BSON для цілих чисел: драйвер пише int як int32 або int64
+var bsonInt = bson.A{"int", "long"}
+
+// $jsonSchema валідатори для основних колекцій
+var validators = map[string]bson.M{
+	"hospitals": {
+		"bsonType": "object",
+		"required": bson.A{"name"},
+		"properties": bson.M{
+			"name":     bson.M{"bsonType": "string", "minLength": 1},
+			"location": bson.M{"bsonType": "string"},
+			"beds":     bson.M{"bsonType": bsonInt, "minimum": 0},
+		},
+	},
+	"departments": {
+		"bsonType": "object",
+		"required": bson.A{"name", "hospital_id"},
+		"properties": bson.M{
+			"name":        bson.M{"bsonType": "string", "minLength": 1},
+			"hospital_id": bson.M{"bsonType": "objectId"},
+			"floor":       bson.M{"bsonType": bsonInt},
+		},
+	},
+	"doctors": doctorsSchema("string"),
+	"staff": {
+		"bsonType": "object",
+		"required": bson.A{"name"},
+		"properties": bson.M{
+			"name":  bson.M{"bsonType": "string", "minLength": 1},
+			"role":  bson.M{"bsonType": "string"},
+			"shift": bson.M{"bsonType": "string"},
+		},
+	},
+	"medications": {
+		"bsonType": "object",
+		"required": bson.A{"name"},
+		"properties": bson.M{
+			"name":         bson.M{"bsonType": "string", "minLength": 1},
+			"dosage":       bson.M{"bsonType": "string"},
+			"manufacturer": bson.M{"bsonType": "string"},
+			"stock":        bson.M{"bsonType": bsonInt, "minimum": 0},
+		},
+	},
+	"appointments": appointmentsSchema(false),
+}
+
+// Схема лікарів; до міграції 3 department був рядком
+func doctorsSchema(departmentType string) bson.M {
+	return bson.M{
+		"bsonType": "object",
+		"required": bson.A{"name"},
+		"properties": bson.M{
+			"name":             bson.M{"bsonType": "string", "minLength": 1},
+			"specialty":        bson.M{"bsonType": "string"},
+			"department":       bson.M{"bsonType": departmentType},
+			"experience_years": bson.M{"bsonType": bsonInt, "minimum": 0},
+		},
+	}
+}
+
+// Схема записів; з міграції 10 - зі статусом і часом переходів
+func appointmentsSchema(lifecycle bool) bson.M {
+	properties := bson.M{
+		"patientId": bson.M{"bsonType": "objectId"},
+		"doctorId":  bson.M{"bsonType": "objectId"},
+		"date":      bson.M{"bsonType": "date"},
+	}
+	if lifecycle {
+		properties["status"] = bson.M{"enum": bson.A{"booked", "checked_in", "in_progress", "completed", "cancelled", "no_show"}}
+		for _, field := range []string{"checkedInAt", "startedAt", "completedAt", "cancelledAt", "noShowAt"} {
+			properties[field] = bson.M{"bsonType": "date"}
+		}
+		properties["cancelReason"] = bson.M{"bsonType": "string"}
+	}
+	return bson.M{
+		"bsonType":   "object",
+		"required":   bson.A{"patientId", "doctorId", "date"},
+		"properties": properties,
+	}
+}
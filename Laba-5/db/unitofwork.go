@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Код IllegalOperation: транзакції на standalone-сервері не підтримуються
+const codeIllegalOperation = 20
+
+// UnitOfWork - кілька записів, що мають застосуватися разом.
+// На replica set кроки виконуються в одній транзакції сесії; на окремому
+// mongod - по черзі, а при помилці вже виконані кроки скасовуються
+// своїми undo у зворотному порядку.
+type UnitOfWork struct {
+	ctx           context.Context
+	transactional bool
+	undo          []func(context.Context) error
+}
+
+// Context - контекст, який треба передавати в усі запити всередині роботи:
+// у транзакції це контекст сесії
+func (u *UnitOfWork) Context() context.Context {
+	return u.ctx
+}
+
+// Transactional - чи виконується робота в транзакції
+func (u *UnitOfWork) Transactional() bool {
+	return u.transactional
+}
+
+// Step виконує запис do. undo запам'ятовується, лише якщо do вдався, і
+// викликається тільки без транзакції - коли падає один із наступних кроків.
+// undo може бути nil для кроків, що нічого не змінюють (перевірки).
+func (u *UnitOfWork) Step(do, undo func(ctx context.Context) error) error {
+	if err := do(u.ctx); err != nil {
+		return err
+	}
+	if undo != nil && !u.transactional {
+		u.undo = append(u.undo, undo)
+	}
+	return nil
+}
+
+// rollback скасовує виконані кроки; undo запускаються навіть після
+// скасування ctx запиту, інакше дані лишилися б наполовину зміненими
+func (u *UnitOfWork) rollback(cause error) error {
+	ctx := context.WithoutCancel(u.ctx)
+	var failed []error
+	for i := len(u.undo) - 1; i >= 0; i-- {
+		if err := u.undo[i](ctx); err != nil {
+			failed = append(failed, err)
+		}
+	}
+	u.undo = nil
+	if len(failed) > 0 {
+		return fmt.Errorf("%w (rollback failed: %v)", cause, errors.Join(failed...))
+	}
+	return cause
+}
+
+// RunInTransaction виконує fn як одну одиницю роботи. Помилка з fn
+// повертається як є (errors.Is працює), тож обробники можуть
+// відрізнити власні помилки-конфлікти від помилок бази.
+//
+// На replica set fn може бути викликана кілька разів: драйвер повторює
+// транзакцію після тимчасових конфліктів запису.
+func RunInTransaction(ctx context.Context, fn func(*UnitOfWork) error) error {
+	if supportsTransactions(ctx) {
+		err := runTransaction(ctx, fn)
+		if !hasServerErrorCode(err, codeIllegalOperation) {
+			return err
+		}
+		// Сервер усе ж не вміє транзакцій - далі працюємо з компенсацією
+		transactions.set(false)
+	}
+
+	u := &UnitOfWork{ctx: ctx}
+	if err := fn(u); err != nil {
+		return u.rollback(err)
+	}
+	return nil
+}
+
+func runTransaction(ctx context.Context, fn func(*UnitOfWork) error) error {
+	session, err := Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(&UnitOfWork{ctx: sc, transactional: true})
+	})
+	return err
+}
+
+// transactions кешує, чи підтримує сервер транзакції; невдала перевірка
+// не кешується, щоб спробувати знову з наступним запитом
+var transactions cachedFlag
+
+type cachedFlag struct {
+	mu    sync.Mutex
+	known bool
+	value bool
+}
+
+func (f *cachedFlag) set(v bool) {
+	f.mu.Lock()
+	f.known, f.value = true, v
+	f.mu.Unlock()
+}
+
+func (f *cachedFlag) get() (value, known bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.value, f.known
+}
+
+// supportsTransactions - чи сервер є членом replica set або mongos
+func supportsTransactions(ctx context.Context) bool {
+	if Client == nil {
+		return false
+	}
+	if v, ok := transactions.get(); ok {
+		return v
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := Client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false
+	}
+	v := hello.SetName != "" || hello.Msg == "isdbgrid"
+	transactions.set(v)
+	return v
+}
+
+func hasServerErrorCode(err error, code int) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(code)
+}
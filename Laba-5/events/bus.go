@@ -0,0 +1,135 @@
+// Package events розсилає підписникам зміни документів hospital_db.
+// Джерело змін - Mongo change streams, а на standalone mongod, де їх
+// немає, - періодичне опитування колекцій (див. Watch).
+package events
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	Created = "created"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+// Event - зміна одного документа. Data - документ після зміни у вигляді
+// моделі API; для Deleted його немає.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Resource   string      `json:"resource"`
+	ResourceID string      `json:"resourceId"`
+	Time       time.Time   `json:"time"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// Filter обмежує події ресурсами та id документів; порожній список - без обмежень
+type Filter struct {
+	Resources []string
+	IDs       []string
+}
+
+// ParseFilter будує фільтр зі списків через кому, як у query-параметрах
+func ParseFilter(resources, ids string) Filter {
+	return Filter{Resources: splitList(resources), IDs: splitList(ids)}
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func (f Filter) Match(e Event) bool {
+	return contains(f.Resources, e.Resource) && contains(f.IDs, e.ResourceID)
+}
+
+func contains(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription - канал подій одного підписника. Канал закривається
+// після Close або коли підписник не встигає читати й буфер переповнено -
+// тоді клієнт має перепідключитися.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+	once   sync.Once
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.close()
+}
+
+// close викликається під s.bus.mu
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		delete(s.bus.subs, s)
+		close(s.ch)
+	})
+}
+
+// Bus - розсилка подій у пам'яті процесу
+type Bus struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+	seq  uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: map[*Subscription]struct{}{}}
+}
+
+// Subscribe підписує на події, що проходять filter; buffer - скільки подій
+// може чекати в каналі, поки підписник їх не прочитав
+func (b *Bus) Subscribe(filter Filter, buffer int) *Subscription {
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Publish присвоює події порядковий ID і віддає її всім відповідним
+// підписникам, не блокуючись на повільних
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e.ID = strconv.FormatUint(b.seq, 10)
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	for sub := range b.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			sub.close()
+		}
+	}
+}
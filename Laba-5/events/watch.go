@@ -0,0 +1,251 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Resource - колекція, зміни якої публікуються, і перетворення її
+// документа на модель API
+type Resource struct {
+	Name   string
+	Decode func(bson.Raw) (interface{}, error)
+}
+
+// Model - Resource, документи якого декодуються у T
+func Model[T any](collection string) Resource {
+	return Resource{Name: collection, Decode: func(raw bson.Raw) (interface{}, error) {
+		var v T
+		err := bson.Unmarshal(raw, &v)
+		return v, err
+	}}
+}
+
+// Change streams на standalone mongod: 40573 - "only supported on replica sets",
+// 20 - IllegalOperation у старших версіях
+var standaloneCodes = []int32{40573, 20}
+
+// Watch публікує в bus зміни колекцій resources, доки ctx не скасовано.
+// Спершу пробує change stream на всю базу; якщо mongod не є replica set,
+// переходить на опитування з інтервалом interval.
+func Watch(ctx context.Context, database *mongo.Database, bus *Bus, interval time.Duration, resources ...Resource) error {
+	byName := map[string]Resource{}
+	names := bson.A{}
+	for _, r := range resources {
+		byName[r.Name] = r
+		names = append(names, r.Name)
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"ns.coll":       bson.M{"$in": names},
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+	}}}}
+
+	var resumeToken bson.Raw
+	for {
+		opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+		if resumeToken != nil {
+			opts.SetResumeAfter(resumeToken)
+		}
+		stream, err := database.Watch(ctx, pipeline, opts)
+		if err != nil {
+			if isStandalone(err) {
+				log.Println("events: change streams unavailable, polling every", interval)
+				return Poll(ctx, database, bus, interval, resources...)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("events: cannot open change stream:", err)
+			if !sleep(ctx, interval) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		for stream.Next(ctx) {
+			var change struct {
+				OperationType string `bson:"operationType"`
+				NS            struct {
+					Coll string `bson:"coll"`
+				} `bson:"ns"`
+				DocumentKey struct {
+					ID primitive.ObjectID `bson:"_id"`
+				} `bson:"documentKey"`
+				FullDocument      bson.Raw `bson:"fullDocument"`
+				UpdateDescription struct {
+					RemovedFields []string `bson:"removedFields"`
+				} `bson:"updateDescription"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				log.Println("events: bad change event:", err)
+				continue
+			}
+			resumeToken = stream.ResumeToken()
+
+			resource, ok := byName[change.NS.Coll]
+			if !ok {
+				continue
+			}
+			event := Event{Resource: resource.Name, ResourceID: change.DocumentKey.ID.Hex()}
+			switch {
+			case change.OperationType == "delete" || softDeleted(change.FullDocument):
+				event.Type = Deleted
+			case change.OperationType == "insert" || restored(change.UpdateDescription.RemovedFields):
+				event.Type = Created
+			default:
+				event.Type = Updated
+			}
+			// fullDocument порожній, якщо документ уже видалили до lookup
+			if event.Type != Deleted && len(change.FullDocument) > 0 {
+				if data, err := resource.Decode(change.FullDocument); err == nil {
+					event.Data = data
+				}
+			}
+			bus.Publish(event)
+		}
+		err = stream.Err()
+		stream.Close(context.Background())
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Println("events: change stream interrupted, resuming:", err)
+		if !sleep(ctx, interval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// softDeleted - чи документ позначено видаленим (models.SoftDelete);
+// для підписників м'яке видалення виглядає як звичайне
+func softDeleted(doc bson.Raw) bool {
+	if len(doc) == 0 {
+		return false
+	}
+	_, err := doc.LookupErr("deletedAt")
+	return err == nil
+}
+
+// restored - чи оновлення зняло позначку видалення
+func restored(removed []string) bool {
+	for _, field := range removed {
+		if field == "deletedAt" {
+			return true
+		}
+	}
+	return false
+}
+
+func isStandalone(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	for _, code := range standaloneCodes {
+		if serverErr.HasErrorCode(int(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Poll - запасний варіант без change streams: раз на interval читає
+// колекції й публікує різницю з попереднім знімком. Перше успішне читання
+// кожної колекції лише запам'ятовує стан, тож події про вже наявні
+// документи не надсилаються.
+func Poll(ctx context.Context, database *mongo.Database, bus *Bus, interval time.Duration, resources ...Resource) error {
+	snapshots := make([]Snapshot, len(resources))
+	// seen[i] - чи колекцію вже хоч раз прочитано успішно
+	seen := make([]bool, len(resources))
+	for {
+		for i, resource := range resources {
+			docs, err := readAll(ctx, database.Collection(resource.Name))
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Println("events: poll", resource.Name+":", err)
+				continue
+			}
+			changes, next := snapshots[i].Diff(resource, docs)
+			snapshots[i] = next
+			if seen[i] {
+				for _, e := range changes {
+					bus.Publish(e)
+				}
+			}
+			seen[i] = true
+		}
+		if !sleep(ctx, interval) {
+			return ctx.Err()
+		}
+	}
+}
+
+func readAll(ctx context.Context, col *mongo.Collection) ([]bson.Raw, error) {
+	cursor, err := col.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.Raw
+	for cursor.Next(ctx) {
+		docs = append(docs, append(bson.Raw(nil), cursor.Current...))
+	}
+	return docs, cursor.Err()
+}
+
+// Snapshot - стан колекції між опитуваннями: _id -> сирий документ
+type Snapshot map[primitive.ObjectID]string
+
+// Diff порівнює знімок з актуальними документами docs і повертає
+// події змін та новий знімок
+func (s Snapshot) Diff(resource Resource, docs []bson.Raw) ([]Event, Snapshot) {
+	next := make(Snapshot, len(docs))
+	var out []Event
+	for _, raw := range docs {
+		id, ok := raw.Lookup("_id").ObjectIDOK()
+		if !ok || softDeleted(raw) {
+			// М'яко видалений документ зникає зі знімка - звідси подія deleted
+			continue
+		}
+		next[id] = string(raw)
+
+		old, seen := s[id]
+		if seen && old == string(raw) {
+			continue
+		}
+		event := Event{Type: Created, Resource: resource.Name, ResourceID: id.Hex()}
+		if seen {
+			event.Type = Updated
+		}
+		if data, err := resource.Decode(raw); err == nil {
+			event.Data = data
+		}
+		out = append(out, event)
+	}
+	for id := range s {
+		if _, ok := next[id]; !ok {
+			out = append(out, Event{Type: Deleted, Resource: resource.Name, ResourceID: id.Hex()})
+		}
+	}
+	return out, next
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,480 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hospital-api/admission"
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Госпіталізація: прийом, переведення, виписка та зайнятість ліжок.
+// Інваріанти тримає сама база: ліжко займається умовним оновленням
+// (лише якщо вільне), а унікальний індекс на активні госпіталізації
+// не дає покласти одного пацієнта двічі.
+func AdmissionRoutes(router *Router) {
+	admin := RequireRole("admin")
+
+	admissions := router.Group("/admissions", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	admissions.HandleFunc(http.MethodGet, "", listAdmissions).
+		Describe("List admissions").Query(admissionParams...).Returns([]models.Admission{})
+	admissions.HandleFunc(http.MethodPost, "", admitPatient, admin).
+		Describe("Admit a patient to a bed or to any free bed of a department").
+		Accepts(bedRequest{}).Returns(models.Admission{})
+	admissions.HandleFunc(http.MethodGet, "/{id}", getAdmission).
+		Describe("Get an admission").Returns(models.Admission{})
+	admissions.HandleFunc(http.MethodPost, "/{id}/transfer", transferPatient, admin).
+		Describe("Move an admitted patient to another bed").Accepts(bedRequest{}).Returns(models.Admission{})
+	admissions.HandleFunc(http.MethodPost, "/{id}/discharge", dischargePatient, admin).
+		Describe("Discharge a patient and free the bed").Returns(models.Admission{})
+
+	occupancy := router.Group("", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	occupancy.HandleFunc(http.MethodGet, "/hospitals/{id}/occupancy", hospitalOccupancy).
+		Describe("Live bed occupancy of a hospital").Returns(occupancyReport{})
+	occupancy.HandleFunc(http.MethodGet, "/departments/{id}/occupancy", departmentOccupancy).
+		Describe("Live bed occupancy of a department").Returns(occupancyReport{})
+}
+
+// bedRequest - тіло прийому чи переведення: конкретне ліжко
+// або відділення, де буде взято будь-яке вільне
+type bedRequest struct {
+	PatientID    primitive.ObjectID `json:"patientId"`
+	BedID        primitive.ObjectID `json:"bedId"`
+	DepartmentID primitive.ObjectID `json:"departmentId"`
+}
+
+// admissionStore - admission.Store поверх Mongo: кожна умова - у фільтрі
+// оновлення, тож паралельні запити не займуть одне ліжко двічі
+type admissionStore struct{}
+
+func (admissionStore) ClaimBed(ctx context.Context, req admission.Request, a models.Admission, exclude primitive.ObjectID) (models.Bed, error) {
+	filter := alive(bson.M{"patientId": bson.M{"$exists": false}})
+	switch {
+	case !req.BedID.IsZero():
+		filter["_id"] = req.BedID
+	case !req.DepartmentID.IsZero():
+		filter["department_id"] = req.DepartmentID
+		if !exclude.IsZero() {
+			filter["_id"] = bson.M{"$ne": exclude}
+		}
+	}
+
+	var bed models.Bed
+	err := db.Collection("beds").FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"patientId": a.PatientID, "admissionId": a.ID}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&bed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return bed, admission.ErrNoFreeBed
+	}
+	return bed, err
+}
+
+func (admissionStore) ReleaseBed(ctx context.Context, bedID, admissionID primitive.ObjectID) error {
+	_, err := db.Collection("beds").UpdateOne(ctx,
+		bson.M{"_id": bedID, "admissionId": admissionID},
+		bson.M{"$unset": bson.M{"patientId": "", "admissionId": ""}},
+	)
+	return err
+}
+
+func (admissionStore) InsertAdmission(ctx context.Context, a models.Admission) error {
+	_, err := db.Collection("admissions").InsertOne(ctx, a)
+	if mongo.IsDuplicateKeyError(err) {
+		return admission.ErrAlreadyAdmitted
+	}
+	return err
+}
+
+func (admissionStore) DeleteAdmission(ctx context.Context, id primitive.ObjectID) error {
+	_, err := db.Collection("admissions").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (admissionStore) MoveAdmission(ctx context.Context, id, from primitive.ObjectID, bed models.Bed, transfer models.BedTransfer) error {
+	res, err := db.Collection("admissions").UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.AdmissionActive, "bedId": from},
+		bson.M{
+			"$set": bson.M{
+				"bedId": bed.ID, "ward_id": bed.WardID,
+				"department_id": bed.DepartmentID, "hospital_id": bed.HospitalID,
+			},
+			"$push": bson.M{"transfers": transfer},
+		},
+	)
+	if err == nil && res.MatchedCount == 0 {
+		err = admission.ErrConflict
+	}
+	return err
+}
+
+func (admissionStore) UnmoveAdmission(ctx context.Context, previous models.Admission) error {
+	_, err := db.Collection("admissions").UpdateOne(ctx, bson.M{"_id": previous.ID}, bson.M{
+		"$set": bson.M{
+			"bedId": previous.BedID, "ward_id": previous.WardID,
+			"department_id": previous.DepartmentID, "hospital_id": previous.HospitalID,
+		},
+		"$pop": bson.M{"transfers": 1},
+	})
+	return err
+}
+
+func (admissionStore) SetStatus(ctx context.Context, id primitive.ObjectID, from, to string, dischargedAt *time.Time) error {
+	update := bson.M{"$set": bson.M{"status": to, "dischargedAt": dischargedAt}}
+	if dischargedAt == nil {
+		update = bson.M{"$set": bson.M{"status": to}, "$unset": bson.M{"dischargedAt": ""}}
+	}
+	res, err := db.Collection("admissions").UpdateOne(ctx, bson.M{"_id": id, "status": from}, update)
+	if err == nil && res.MatchedCount == 0 {
+		err = admission.ErrNotActive
+	}
+	return err
+}
+
+func (admissionStore) ReserveBed(ctx context.Context, wardID primitive.ObjectID) (models.Ward, error) {
+	var ward models.Ward
+	err := db.Collection("wards").FindOneAndUpdate(ctx,
+		alive(bson.M{"_id": wardID, "$expr": bson.M{"$lt": bson.A{"$beds", "$capacity"}}}),
+		bson.M{"$inc": bson.M{"beds": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&ward)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ward, admission.ErrWardFull
+	}
+	return ward, err
+}
+
+func (admissionStore) UnreserveBed(ctx context.Context, wardID primitive.ObjectID) error {
+	_, err := db.Collection("wards").UpdateByID(ctx, wardID, bson.M{"$inc": bson.M{"beds": -1}})
+	return err
+}
+
+func (admissionStore) InsertBed(ctx context.Context, bed models.Bed) (primitive.ObjectID, error) {
+	res, err := db.Collection("beds").InsertOne(ctx, bed)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return res.InsertedID.(primitive.ObjectID), nil
+}
+
+func (admissionStore) TrashBed(ctx context.Context, bedID primitive.ObjectID, mark models.SoftDelete) (models.Bed, error) {
+	var bed models.Bed
+	err := db.Collection("beds").FindOneAndUpdate(ctx,
+		alive(bson.M{"_id": bedID, "patientId": bson.M{"$exists": false}}),
+		bson.M{"$set": bson.M{"deletedAt": mark.DeletedAt, "deletedBy": mark.DeletedBy}},
+	).Decode(&bed)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return bed, admission.ErrBedNotFree
+	}
+	return bed, err
+}
+
+func (admissionStore) UntrashBed(ctx context.Context, bedID primitive.ObjectID) error {
+	found, err := restore(ctx, db.Collection("beds"), bson.M{"_id": bedID})
+	if err == nil && !found {
+		err = admission.ErrBedNotInTrash
+	}
+	return err
+}
+
+func decodeBedRequest(w http.ResponseWriter, r *http.Request) (bedRequest, bool) {
+	var req bedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	if req.BedID.IsZero() && req.DepartmentID.IsZero() {
+		httpError(w, r, http.StatusBadRequest, "request.bed_or_department_required")
+		return req, false
+	}
+	return req, true
+}
+
+func (req bedRequest) admission() admission.Request {
+	return admission.Request{PatientID: req.PatientID, BedID: req.BedID, DepartmentID: req.DepartmentID}
+}
+
+// admissionError відповідає на помилку з пакета admission
+func admissionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, admission.ErrNoFreeBed):
+		httpError(w, r, http.StatusConflict, "bed.none_free")
+	case errors.Is(err, admission.ErrAlreadyAdmitted):
+		httpError(w, r, http.StatusConflict, "admission.already_admitted")
+	case errors.Is(err, admission.ErrNotActive):
+		httpError(w, r, http.StatusConflict, "admission.not_active")
+	case errors.Is(err, admission.ErrSameBed):
+		httpError(w, r, http.StatusConflict, "admission.same_bed")
+	case errors.Is(err, admission.ErrConflict):
+		httpError(w, r, http.StatusConflict, "admission.conflict")
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func admitPatient(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeBedRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.PatientID.IsZero() {
+		httpError(w, r, http.StatusBadRequest, "request.patient_id_required")
+		return
+	}
+
+	var admitted models.Admission
+	err := db.RunInTransaction(context.TODO(), func(u *db.UnitOfWork) error {
+		var err error
+		admitted, err = admission.Admit(u, admissionStore{}, req.admission(), time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		admissionError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, admitted)
+}
+
+// findActiveAdmission відповідає 404/409 сам, якщо госпіталізація не активна
+func findActiveAdmission(w http.ResponseWriter, r *http.Request) (models.Admission, bool) {
+	var a models.Admission
+	objID, ok := pathID(w, r)
+	if !ok {
+		return a, false
+	}
+	if err := db.Collection("admissions").FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&a); err != nil {
+		httpError(w, r, http.StatusNotFound, "admission.not_found")
+		return a, false
+	}
+	if a.Status != models.AdmissionActive {
+		httpError(w, r, http.StatusConflict, "admission.not_active")
+		return a, false
+	}
+	return a, true
+}
+
+func transferPatient(w http.ResponseWriter, r *http.Request) {
+	current, ok := findActiveAdmission(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeBedRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var moved models.Admission
+	err := db.RunInTransaction(context.TODO(), func(u *db.UnitOfWork) error {
+		var err error
+		moved, err = admission.Transfer(u, admissionStore{}, current, req.admission(), time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		admissionError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, moved)
+}
+
+func dischargePatient(w http.ResponseWriter, r *http.Request) {
+	current, ok := findActiveAdmission(w, r)
+	if !ok {
+		return
+	}
+
+	var discharged models.Admission
+	err := db.RunInTransaction(context.TODO(), func(u *db.UnitOfWork) error {
+		var err error
+		discharged, err = admission.Discharge(u, admissionStore{}, current, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		admissionError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, discharged)
+}
+
+func listAdmissions(w http.ResponseWriter, r *http.Request) {
+	opts := options.Find().SetSort(bson.D{{Key: "admittedAt", Value: -1}})
+	cursor, err := db.Collection("admissions").Find(context.TODO(), admissionFilter(r.URL.Query()), opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	admissions := []models.Admission{}
+	if err := cursor.All(context.TODO(), &admissions); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, admissions)
+}
+
+func getAdmission(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var admission models.Admission
+	if err := db.Collection("admissions").FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&admission); err != nil {
+		httpError(w, r, http.StatusNotFound, "admission.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, admission)
+}
+
+// Query-параметри admissionFilter для документації
+var admissionParams = []Param{
+	{Name: "patientId", Description: "Patient ObjectID"},
+	{Name: "hospitalId", Description: "Hospital ObjectID"},
+	{Name: "departmentId", Description: "Department ObjectID"},
+	{Name: "status", Description: "active or discharged"},
+}
+
+func admissionFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	addIDFilter(filter, "patientId", query.Get("patientId"))
+	addIDFilter(filter, "hospital_id", query.Get("hospitalId"))
+	addIDFilter(filter, "department_id", query.Get("departmentId"))
+	if status := strings.TrimSpace(query.Get("status")); status != "" {
+		filter["status"] = status
+	}
+	return filter
+}
+
+// --- Зайнятість ліжок ---
+
+type wardOccupancy struct {
+	WardID   primitive.ObjectID `json:"wardId"`
+	Name     string             `json:"name"`
+	Capacity int                `json:"capacity"`
+	Beds     int                `json:"beds"`
+	Occupied int                `json:"occupied"`
+	Free     int                `json:"free"`
+}
+
+type occupancyReport struct {
+	HospitalID    primitive.ObjectID `json:"hospitalId"`
+	DepartmentID  primitive.ObjectID `json:"departmentId,omitempty"`
+	DeclaredBeds  int                `json:"declaredBeds,omitempty"`
+	Capacity      int                `json:"capacity"`
+	Beds          int                `json:"beds"`
+	Occupied      int                `json:"occupied"`
+	Free          int                `json:"free"`
+	OccupancyRate float64            `json:"occupancyRate"`
+	Wards         []wardOccupancy    `json:"wards"`
+}
+
+func hospitalOccupancy(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var hospital models.Hospital
+	if err := db.Collection("hospitals").FindOne(context.TODO(), alive(bson.M{"_id": objID})).Decode(&hospital); err != nil {
+		httpError(w, r, http.StatusNotFound, "hospital.not_found")
+		return
+	}
+
+	report, err := occupancy(context.TODO(), bson.M{"hospital_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report.HospitalID = objID
+	report.DeclaredBeds = hospital.Beds
+	writeResponse(w, r, http.StatusOK, report)
+}
+
+func departmentOccupancy(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var department models.Department
+	if err := db.Collection("departments").FindOne(context.TODO(), alive(bson.M{"_id": objID})).Decode(&department); err != nil {
+		httpError(w, r, http.StatusNotFound, "department.not_found")
+		return
+	}
+
+	report, err := occupancy(context.TODO(), bson.M{"department_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report.HospitalID = department.HospitalID
+	report.DepartmentID = objID
+	writeResponse(w, r, http.StatusOK, report)
+}
+
+// occupancy рахує ліжка та зайняті ліжка по живих палатах, що відповідають match
+func occupancy(ctx context.Context, match bson.M) (occupancyReport, error) {
+	report := occupancyReport{Wards: []wardOccupancy{}}
+	alive(match)
+
+	cursor, err := db.Collection("wards").Find(ctx, match, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return report, err
+	}
+	var wards []models.Ward
+	if err := cursor.All(ctx, &wards); err != nil {
+		return report, err
+	}
+
+	cursor, err = db.Collection("beds").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$ward_id",
+			"beds":     bson.M{"$sum": 1},
+			"occupied": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$ifNull": bson.A{"$patientId", false}}, 1, 0}}},
+		}}},
+	})
+	if err != nil {
+		return report, err
+	}
+	var counts []struct {
+		WardID   primitive.ObjectID `bson:"_id"`
+		Beds     int                `bson:"beds"`
+		Occupied int                `bson:"occupied"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return report, err
+	}
+	byWard := map[primitive.ObjectID]int{}
+	for i, c := range counts {
+		byWard[c.WardID] = i
+	}
+
+	for _, ward := range wards {
+		wo := wardOccupancy{WardID: ward.ID, Name: ward.Name, Capacity: ward.Capacity}
+		if i, ok := byWard[ward.ID]; ok {
+			wo.Beds = counts[i].Beds
+			wo.Occupied = counts[i].Occupied
+		}
+		wo.Free = wo.Beds - wo.Occupied
+		report.Wards = append(report.Wards, wo)
+
+		report.Capacity += wo.Capacity
+		report.Beds += wo.Beds
+		report.Occupied += wo.Occupied
+		report.Free += wo.Free
+	}
+	if report.Beds > 0 {
+		report.OccupancyRate = float64(report.Occupied) / float64(report.Beds)
+	}
+	return report, nil
+}
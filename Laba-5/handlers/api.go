@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"hospital-api/idempotency"
+)
+
+// Idempotency зберігає відповіді POST-запитів з Idempotency-Key;
+// тести підміняють його на idempotency.NewMemoryStore() до виклику NewAPI
+var Idempotency idempotency.Store = idempotency.MongoStore{}
+
+// IdempotencyTTL - скільки діє ключ: повтори в межах доби отримують ту саму відповідь
+var IdempotencyTTL = 24 * time.Hour
+
+// NewAPI реєструє всі маршрути hospital-api на новому роутері
+func NewAPI() *Router {
+	router := NewRouter()
+	router.Use(LanguageMiddleware, NegotiateMiddleware)
+	// Після авторизації групи: збережену відповідь отримує лише той, хто
+	// пройшов ту саму перевірку, а ключі розділені за перевіреним actor
+	router.UseInner(idempotency.Middleware(Idempotency, IdempotencyTTL, actor))
+	AuthRoutes(router)
+	AppointmentRoutes(router)
+	SeriesRoutes(router)
+	CalendarRoutes(router)
+	StaffRoutes(router)
+	MedicineRoutes(router)
+	DoctorRoutes(router)
+	HospitalRoutes(router)
+	DepartmentRoutes(router)
+	ReportRoutes(router)
+	WardRoutes(router)
+	AdmissionRoutes(router)
+	ShiftRoutes(router)
+	EventRoutes(router)
+	WebhookRoutes(router)
+	SearchRoutes(router)
+	GraphQLRoutes(router)
+	MetricsRoutes(router)
+
+	// "/{$}" - лише корінь, а не catch-all для всіх шляхів
+	router.HandleFunc(http.MethodGet, "/{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, tr(r, "api.root"))
+	})
+
+	// Документація в кінці, коли всі маршрути вже відомі
+	DocsRoutes(router)
+	return router
+}
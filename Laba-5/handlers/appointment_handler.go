@@ -3,10 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 	"time"
 
@@ -15,170 +15,514 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Middleware для логування
-func LoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f, err := os.OpenFile("access.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Println("Cannot open log file:", err)
-		} else {
-			defer f.Close()
-			log.SetOutput(f)
-			log.Printf("%s %s\n", r.Method, r.URL.Path)
+// Реєстрація маршрутів: GET дозволений reader і admin, зміни - лише admin
+func AppointmentRoutes(router *Router) {
+	appointments := router.Group("/appointments", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	admin := RequireRole("admin")
+
+	appointments.HandleFunc(http.MethodGet, "", listAppointments).
+		Describe("List appointments").Query(appointmentParams...).Query(includeDeletedParam).Query(expandParam("appointments")).Returns([]models.Appointment{})
+	appointments.HandleFunc(http.MethodPost, "", createAppointment, admin).
+		Describe("Create a appointment").Accepts(models.Appointment{}).Returns(models.Appointment{})
+	appointments.HandleFunc(http.MethodGet, "/{id}", getAppointment).
+		Describe("Get a appointment").Query(includeDeletedParam).Query(expandParam("appointments")).Returns(models.Appointment{})
+	appointments.HandleFunc(http.MethodPut, "/{id}", updateAppointment, admin).
+		Describe("Update a appointment").Accepts(models.Appointment{})
+	appointments.HandleFunc(http.MethodDelete, "/{id}", deleteAppointment, admin).
+		Describe("Soft-delete a appointment")
+
+	// Життєвий цикл: POST /appointments/{id}/{action} для кожного переходу
+	for _, t := range models.AppointmentTransitions {
+		route := appointments.HandleFunc(http.MethodPost, "/{id}/"+t.Action, transitionAppointment(t), admin).
+			Describe(fmt.Sprintf("Move an appointment from %s to %s", strings.Join(t.From, " or "), t.To)).
+			Returns(models.Appointment{})
+		if t.To == models.AppointmentCancelled {
+			route.Accepts(models.AppointmentCancellation{})
 		}
-		next.ServeHTTP(w, r)
+	}
+
+	restoreRoute(router, "/appointments", "Appointment", restoreAppointment)
+
+	appointments.Handle(http.MethodPost, "/import", importRows(appointmentImport), admin).
+		Describe("Import appointments from CSV or NDJSON").Query(importParams...).
+		Accepts([]models.Appointment{}, "text/csv", "application/x-ndjson").Returns(importResult{})
+	appointments.Handle(http.MethodGet, "/export", exportHandler[models.Appointment]("appointments", appointmentFilter)).
+		Describe("Export appointments as CSV or NDJSON").Query(appointmentParams...).Query(exportParams...).
+		Returns([]models.Appointment{}, "text/csv", "application/x-ndjson")
+
+	// Записи до лікаря - з тією ж JWT-авторизацією, що й /appointments
+	byDoctor := router.Group("/doctors/{id}/appointments", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	byDoctor.HandleFunc(http.MethodGet, "", nestedList("doctorId", listAppointments)).
+		Describe("List appointments of a doctor").Query(appointmentParams...).Query(includeDeletedParam).Query(expandParam("appointments")).
+		Returns([]models.Appointment{})
+}
+
+// Список зустрічей
+func listAppointments(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, appointmentFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Appointment](w, r, "appointments", filter, expand, false)
+		return
+	}
+
+	cursor, err := db.Collection("appointments").Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	var appointments []models.Appointment
+	if err := cursor.All(context.TODO(), &appointments); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, appointments)
+}
+
+func createAppointment(w http.ResponseWriter, r *http.Request) {
+	var appointment models.Appointment
+	if err := json.NewDecoder(r.Body).Decode(&appointment); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	appointment.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
+	appointment.SeriesID = nil                   // серії створюються через /appointment-series
+	if appointment.Date.IsZero() {
+		appointment.Date = time.Now()
+	}
+	if err := appointment.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+
+	err := bookAppointment(context.TODO(), &appointment)
+	if key, ok := bookingConflict(err); ok {
+		httpError(w, r, http.StatusConflict, key)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, appointment)
+}
+
+// appointmentSlot - тривалість прийому: записи до одного лікаря
+// не можуть починатися ближче одне до одного
+const appointmentSlot = 30 * time.Minute
+
+var (
+	errSlotTaken         = errors.New("doctor already has an appointment at this time")
+	errDoctorUnavailable = errors.New("doctor is unavailable at this time")
+)
+
+// bookingConflict - ключ повідомлення, якщо bookIn відмовив через зайнятий час
+func bookingConflict(err error) (string, bool) {
+	switch {
+	case errors.Is(err, errSlotTaken):
+		return "appointment.slot_taken", true
+	case errors.Is(err, errDoctorUnavailable):
+		return "appointment.doctor_unavailable", true
+	}
+	return "", false
+}
+
+// releasedStatuses - записи в цих станах не займають час лікаря
+var releasedStatuses = bson.A{models.AppointmentCancelled, models.AppointmentNoShow}
+
+// bookAppointment вставляє запис і перевіряє, що в лікаря немає іншого
+// живого запису ближче за appointmentSlot.
+//
+// Перевірка йде після вставки: без транзакції два паралельні записи на
+// той самий час побачать один одного й обидва відкотяться, а не
+// стануть поруч. У транзакції снапшот не бачить чужих вставок, тому
+// спершу пишемо в спільний документ лікаря в booking_locks - паралельні
+// транзакції конфліктують на ньому, і драйвер повторює пізнішу.
+func bookAppointment(ctx context.Context, appointment *models.Appointment) error {
+	return db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		return bookIn(u, appointment)
 	})
 }
 
-// Реєстрація маршрутів
-func AppointmentRoutes() {
-	http.Handle("/login", LoggingMiddleware(http.HandlerFunc(LoginHandler)))
+// bookIn - кроки bookAppointment у вже відкритій одиниці роботи; серія
+// бронює так усі свої записи разом
+func bookIn(u *db.UnitOfWork, appointment *models.Appointment) error {
+	if err := lockDoctor(u, appointment.DoctorID); err != nil {
+		return err
+	}
 
-	// GET дозволений reader і admin
-	http.Handle("/appointments", LoggingMiddleware(
-		JWTAuthMiddleware(http.HandlerFunc(appointmentsHandler), "reader", "admin"),
-	))
-	http.Handle("/appointments/", LoggingMiddleware(
-		JWTAuthMiddleware(http.HandlerFunc(appointmentHandler), "reader", "admin"),
-	))
+	appointments := db.Collection("appointments")
+	appointment.ID = primitive.NewObjectID()
+	appointment.AppointmentLifecycle = models.AppointmentLifecycle{Status: models.AppointmentBooked}
+	err := u.Step(func(ctx context.Context) error {
+		_, err := appointments.InsertOne(ctx, appointment)
+		return err
+	}, func(ctx context.Context) error {
+		_, err := appointments.DeleteOne(ctx, bson.M{"_id": appointment.ID})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return checkSlot(u, *appointment)
 }
 
-// Обробник для списку зустрічей
-func appointmentsHandler(w http.ResponseWriter, r *http.Request) {
-	claims := GetClaims(r)
-	col := db.Client.Database("hospital_db").Collection("appointments")
+// lockDoctor - запис у спільний документ лікаря; без транзакції не потрібен
+func lockDoctor(u *db.UnitOfWork, doctorID primitive.ObjectID) error {
+	if !u.Transactional() {
+		return nil
+	}
+	return u.Step(func(ctx context.Context) error {
+		_, err := db.Collection("booking_locks").UpdateByID(ctx, doctorID,
+			bson.M{"$inc": bson.M{"seq": 1}}, options.Update().SetUpsert(true))
+		return err
+	}, nil)
+}
 
-	switch r.Method {
-	case http.MethodGet:
-		filter := bson.M{}
-		query := r.URL.Query()
-		if patient := query.Get("patientId"); patient != "" {
-			if objID, err := primitive.ObjectIDFromHex(patient); err == nil {
-				filter["patientId"] = objID
-			}
+// checkSlot - errSlotTaken, якщо в лікаря є інший живий запис ближче за
+// appointmentSlot, і errDoctorUnavailable, якщо прийом зачіпає блок недоступності
+func checkSlot(u *db.UnitOfWork, appointment models.Appointment) error {
+	return u.Step(func(ctx context.Context) error {
+		n, err := db.Collection("appointments").CountDocuments(ctx, alive(bson.M{
+			"_id":      bson.M{"$ne": appointment.ID},
+			"doctorId": appointment.DoctorID,
+			"status":   bson.M{"$nin": releasedStatuses},
+			"date": bson.M{
+				"$gt": appointment.Date.Add(-appointmentSlot),
+				"$lt": appointment.Date.Add(appointmentSlot),
+			},
+		}), options.Count().SetLimit(1))
+		if err != nil {
+			return err
 		}
-		if doctor := query.Get("doctorId"); doctor != "" {
-			if objID, err := primitive.ObjectIDFromHex(doctor); err == nil {
-				filter["doctorId"] = objID
-			}
+		if n > 0 {
+			return errSlotTaken
 		}
-		if dateStr := query.Get("date"); dateStr != "" {
-			if date, err := time.Parse("2006-01-02", dateStr); err == nil {
-				start := date
-				end := date.Add(24 * time.Hour)
-				filter["date"] = bson.M{"$gte": start, "$lt": end}
-			}
+		n, err = db.Collection("unavailability").CountDocuments(ctx, bson.M{
+			"doctorId": appointment.DoctorID,
+			"start":    bson.M{"$lt": appointment.Date.Add(appointmentSlot)},
+			"end":      bson.M{"$gt": appointment.Date},
+		}, options.Count().SetLimit(1))
+		if err == nil && n > 0 {
+			err = errDoctorUnavailable
 		}
+		return err
+	}, nil)
+}
 
-		cursor, err := col.Find(context.TODO(), filter)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(context.TODO())
+// Конкретна зустріч
+func getAppointment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Appointment](w, r, "appointments", filter, expand, true)
+		return
+	}
 
-		var appointments []models.Appointment
-		if err := cursor.All(context.TODO(), &appointments); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writeJSONApointemnt(w, appointments)
+	var appointment models.Appointment
+	err := db.Collection("appointments").FindOne(context.TODO(), filter).Decode(&appointment)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "appointment.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, appointment)
+}
 
-	case http.MethodPost:
-		if claims.Role != "admin" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
+func updateAppointment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var update models.Appointment
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	update.AppointmentLifecycle = models.AppointmentLifecycle{} // статус змінюють лише переходи
+	if err := update.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+
+	ctx := context.TODO()
+	var current models.Appointment
+	if err := db.Collection("appointments").FindOne(ctx, alive(bson.M{"_id": objID})).Decode(&current); err != nil {
+		httpError(w, r, http.StatusNotFound, "appointment.not_found")
+		return
+	}
+	moved := current
+	moved.PatientID, moved.DoctorID, moved.Date = update.PatientID, update.DoctorID, update.Date
+
+	err := rescheduleAppointment(ctx, current, moved)
+	if key, ok := bookingConflict(err); ok {
+		httpError(w, r, http.StatusConflict, key)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, tr(r, "appointment.updated"))
+}
+
+// rescheduleAppointment записує в current пацієнта, лікаря й час з moved
+// тими ж кроками, що й bookIn: блокування лікаря, запис, checkSlot. На
+// конфлікті запис повертається до current. Скасовані записи час не
+// займають, тож їх не перевіряємо.
+func rescheduleAppointment(ctx context.Context, current, moved models.Appointment) error {
+	appointments := db.Collection("appointments")
+	set := func(a models.Appointment) func(context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := appointments.UpdateByID(ctx, a.ID, bson.M{"$set": bson.M{
+				"patientId": a.PatientID, "doctorId": a.DoctorID, "date": a.Date,
+			}})
+			return err
 		}
+	}
+	return db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		if err := lockDoctor(u, moved.DoctorID); err != nil {
+			return err
+		}
+		if err := u.Step(set(moved), set(current)); err != nil {
+			return err
+		}
+		if moved.Released() {
+			return nil
+		}
+		return checkSlot(u, moved)
+	})
+}
 
-		var appointment models.Appointment
-		if err := json.NewDecoder(r.Body).Decode(&appointment); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+// restoreAppointment повертає запис з кошика тими ж кроками, що й
+// бронювання: поки він лежав у кошику, його час міг зайняти інший запис
+func restoreAppointment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := context.TODO()
+	appointments := db.Collection("appointments")
+	var appointment models.Appointment
+	if err := appointments.FindOne(ctx, bson.M{"_id": objID, "deletedAt": bson.M{"$exists": true}}).Decode(&appointment); err != nil {
+		httpError(w, r, http.StatusNotFound, "appointment.not_in_trash")
+		return
+	}
+
+	err := db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		if err := lockDoctor(u, appointment.DoctorID); err != nil {
+			return err
 		}
-		if appointment.Date.IsZero() {
-			appointment.Date = time.Now()
+		err := u.Step(func(ctx context.Context) error {
+			found, err := restore(ctx, appointments, bson.M{"_id": objID})
+			if err == nil && !found {
+				err = mongo.ErrNoDocuments
+			}
+			return err
+		}, func(ctx context.Context) error {
+			_, err := appointments.UpdateByID(ctx, objID, bson.M{"$set": bson.M{
+				"deletedAt": appointment.DeletedAt, "deletedBy": appointment.DeletedBy,
+			}})
+			return err
+		})
+		if err != nil || appointment.Released() {
+			return err
 		}
+		return checkSlot(u, appointment)
+	})
+	if key, ok := bookingConflict(err); ok {
+		httpError(w, r, http.StatusConflict, key)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		httpError(w, r, http.StatusNotFound, "appointment.not_in_trash")
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-		res, err := col.InsertOne(context.TODO(), appointment)
+// appointmentImport - рядки імпорту записів пишуться по одному, тими ж
+// кроками, що й bookIn: блокування лікаря, запис, checkSlot. Рядок, що
+// налазить на інший запис чи недоступність лікаря, падає з 409-повідомленням.
+func appointmentImport(r *http.Request, result *importResult) importSink[models.Appointment] {
+	add := func(line int, appointment models.Appointment) error {
+		err := importAppointment(r.Context(), appointment)
+		if key, ok := bookingConflict(err); ok {
+			err = errors.New(tr(r, key))
+		}
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			result.fail(r, line, err)
+			return nil
 		}
-		appointment.ID = res.InsertedID.(primitive.ObjectID)
-		writeJSONApointemnt(w, appointment)
+		result.Imported++
+		return nil
+	}
+	return importSink[models.Appointment]{add: add, flush: func() error { return nil }}
+}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// importAppointment вставляє запис або замінює живий з тим самим _id;
+// на конфлікті попередній документ повертається
+func importAppointment(ctx context.Context, appointment models.Appointment) error {
+	appointments := db.Collection("appointments")
+	if appointment.ID.IsZero() {
+		appointment.ID = primitive.NewObjectID()
 	}
+	return db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		if err := lockDoctor(u, appointment.DoctorID); err != nil {
+			return err
+		}
+		var previous bson.M
+		err := u.Step(func(ctx context.Context) error {
+			err := appointments.FindOne(ctx, bson.M{"_id": appointment.ID}).Decode(&previous)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return err
+			}
+			// Видалений документ не замінюється: upsert упирається в його _id
+			_, err = appointments.ReplaceOne(ctx, alive(bson.M{"_id": appointment.ID}), appointment, options.Replace().SetUpsert(true))
+			return err
+		}, func(ctx context.Context) error {
+			if previous == nil {
+				_, err := appointments.DeleteOne(ctx, bson.M{"_id": appointment.ID})
+				return err
+			}
+			_, err := appointments.ReplaceOne(ctx, bson.M{"_id": appointment.ID}, previous)
+			return err
+		})
+		if err != nil || appointment.Released() {
+			return err
+		}
+		return checkSlot(u, appointment)
+	})
 }
 
-// Обробник для конкретної зустрічі
-func appointmentHandler(w http.ResponseWriter, r *http.Request) {
-	claims := GetClaims(r)
-	col := db.Client.Database("hospital_db").Collection("appointments")
-	id := strings.TrimPrefix(r.URL.Path, "/appointments/")
-	objID, err := primitive.ObjectIDFromHex(id)
+func deleteAppointment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := softDelete(context.TODO(), r, "appointments", bson.M{"_id": objID})
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if !found {
+		httpError(w, r, http.StatusNotFound, "appointment.not_found")
+		return
+	}
+	fmt.Fprint(w, tr(r, "appointment.deleted"))
+}
 
-	switch r.Method {
-	case http.MethodGet:
-		var appointment models.Appointment
-		err := col.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&appointment)
-		if err != nil {
-			http.Error(w, "Appointment not found", http.StatusNotFound)
-			return
-		}
-		writeJSONApointemnt(w, appointment)
+// Query-параметри appointmentFilter для документації
+var appointmentParams = []Param{
+	{Name: "patientId", Description: "Patient ObjectID"},
+	{Name: "doctorId", Description: "Doctor ObjectID"},
+	{Name: "date", Format: "date", Description: "Appointments on this day (YYYY-MM-DD)"},
+	{Name: "status", Description: "Lifecycle status: booked, checked_in, in_progress, completed, cancelled or no_show"},
+}
 
-	case http.MethodPut:
-		if claims.Role != "admin" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
+// Фільтр списку записів через query params
+func appointmentFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	if patient := query.Get("patientId"); patient != "" {
+		if objID, err := primitive.ObjectIDFromHex(patient); err == nil {
+			filter["patientId"] = objID
 		}
-
-		var update models.Appointment
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+	}
+	if doctor := query.Get("doctorId"); doctor != "" {
+		if objID, err := primitive.ObjectIDFromHex(doctor); err == nil {
+			filter["doctorId"] = objID
 		}
+	}
+	if dateStr := query.Get("date"); dateStr != "" {
+		if date, err := time.Parse("2006-01-02", dateStr); err == nil {
+			start := date
+			end := date.Add(24 * time.Hour)
+			filter["date"] = bson.M{"$gte": start, "$lt": end}
+		}
+	}
+	if status := query.Get("status"); status != "" {
+		filter["status"] = statusFilter(status)
+	}
+
+	return filter
+}
 
-		updateMap := bson.M{
-			"patientId": update.PatientID,
-			"doctorId":  update.DoctorID,
-			"date":      update.Date,
+// statusFilter - умова на status; записи без статусу (до міграції 10) - booked
+func statusFilter(statuses ...string) bson.M {
+	in := bson.A{}
+	for _, status := range statuses {
+		in = append(in, status)
+		if status == models.AppointmentBooked {
+			in = append(in, nil)
 		}
+	}
+	return bson.M{"$in": in}
+}
 
-		_, err := col.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": updateMap})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+// transitionAppointment - POST /appointments/{id}/{action}. Стан
+// перевіряється в самому оновленні, тож два одночасні переходи не
+// проскочать обидва.
+func transitionAppointment(t models.AppointmentTransition) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		objID, ok := pathID(w, r)
+		if !ok {
 			return
 		}
-		fmt.Fprintf(w, "Appointment updated successfully")
 
-	case http.MethodDelete:
-		if claims.Role != "admin" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
+		set := bson.M{"status": t.To, t.Field: time.Now().UTC()}
+		if t.To == models.AppointmentCancelled {
+			var cancel models.AppointmentCancellation
+			if err := json.NewDecoder(r.Body).Decode(&cancel); err != nil {
+				httpError(w, r, http.StatusBadRequest, "request.invalid")
+				return
+			}
+			if err := cancel.Validate(); err != nil {
+				validationError(w, r, err)
+				return
+			}
+			set["cancelReason"] = strings.TrimSpace(cancel.Reason)
 		}
 
-		_, err := col.DeleteOne(context.TODO(), bson.M{"_id": objID})
+		var appointment models.Appointment
+		err := db.Collection("appointments").FindOneAndUpdate(context.TODO(),
+			alive(bson.M{"_id": objID, "status": statusFilter(t.From...)}),
+			bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&appointment)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Або запису немає, або він не в тому стані
+			var current models.Appointment
+			if err := db.Collection("appointments").FindOne(context.TODO(), alive(bson.M{"_id": objID})).Decode(&current); err != nil {
+				httpError(w, r, http.StatusNotFound, "appointment.not_found")
+				return
+			}
+			httpError(w, r, http.StatusConflict, "appointment.invalid_transition", t.Action, current.State())
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		fmt.Fprintf(w, "Appointment deleted successfully")
-
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeResponse(w, r, http.StatusOK, appointment)
 	}
 }
-
-func writeJSONApointemnt(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
-}
@@ -32,17 +32,22 @@ var users = map[string]struct {
 	"reader": {"reader123", "reader"},
 }
 
+func AuthRoutes(router *Router) {
+	router.HandleFunc(http.MethodPost, "/login", LoginHandler, LoggingMiddleware).
+		Describe("Log in and receive a JWT").Accepts(Credentials{}).Returns(map[string]string{})
+}
+
 // /login endpoint
 func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	var creds Credentials
 	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		httpError(w, r, http.StatusBadRequest, "request.invalid")
 		return
 	}
 
 	user, ok := users[creds.Username]
 	if !ok || user.Password != creds.Password {
-		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+		httpError(w, r, http.StatusUnauthorized, "auth.invalid_credentials")
 		return
 	}
 
@@ -58,11 +63,11 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(jwtKey)
 	if err != nil {
-		http.Error(w, "Could not create token", http.StatusInternalServerError)
+		httpError(w, r, http.StatusInternalServerError, "auth.token_failed")
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{
+	writeResponse(w, r, http.StatusOK, map[string]string{
 		"token": tokenString,
 	})
 }
@@ -72,18 +77,15 @@ func JWTAuthMiddleware(next http.Handler, allowedRoles ...string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			http.Error(w, "Missing or invalid token", http.StatusUnauthorized)
+			httpError(w, r, http.StatusUnauthorized, "auth.invalid_token")
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return jwtKey, nil
-		})
-		if err != nil || !token.Valid {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		claims, ok := parseToken(tokenString)
+		if !ok {
+			httpError(w, r, http.StatusUnauthorized, "auth.unauthorized")
 			return
 		}
 
@@ -96,7 +98,7 @@ func JWTAuthMiddleware(next http.Handler, allowedRoles ...string) http.Handler {
 			}
 		}
 		if !allowed {
-			http.Error(w, "Forbidden", http.StatusForbidden)
+			httpError(w, r, http.StatusForbidden, "auth.forbidden")
 			return
 		}
 
@@ -105,9 +107,36 @@ func JWTAuthMiddleware(next http.Handler, allowedRoles ...string) http.Handler {
 	})
 }
 
+func parseToken(tokenString string) (*Claims, bool) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtKey, nil
+	})
+	return claims, err == nil && token.Valid
+}
+
+// requestClaims - claims з контексту або, на маршрутах без JWT-middleware
+// (API-ключ, відкриті), з необов'язкового заголовка Authorization
+func requestClaims(r *http.Request) *Claims {
+	if claims := GetClaims(r); claims != nil {
+		return claims
+	}
+	if tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if claims, ok := parseToken(tokenString); ok {
+			return claims
+		}
+	}
+	return nil
+}
+
 // Отримати claims із контексту
 func GetClaims(r *http.Request) *Claims {
-	if val, ok := r.Context().Value("claims").(*Claims); ok {
+	return claimsFrom(r.Context())
+}
+
+// claimsFrom - claims, які поклали JWT-middleware або перевірка gRPC
+func claimsFrom(ctx context.Context) *Claims {
+	if val, ok := ctx.Value("claims").(*Claims); ok {
 		return val
 	}
 	return nil
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"hospital-api/cache"
+	"hospital-api/events"
+	"hospital-api/models"
+	"hospital-api/repository"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// cacheTTL - скільки живе закешоване читання; стільки ж і max-age клієнта
+const cacheTTL = 30 * time.Second
+
+// Cache - кеш читань лікарень, відділень і лікарів. main підписує його
+// Invalidate на db.OnWrite; спільний кеш підключається заміною цієї змінної.
+var Cache cache.Cache = cache.NewMemory(cache.Options{
+	TTL:        cacheTTL,
+	MaxEntries: 1000,
+	MaxBytes:   16 << 20,
+})
+
+var (
+	hospitalsRepo   = repository.New[models.Hospital]("hospitals", Cache)
+	departmentsRepo = repository.New[models.Department]("departments", Cache)
+	doctorsRepo     = repository.New[models.Doctor]("doctors", Cache)
+)
+
+// InvalidateCache скидає кеш колекції; підходить для db.OnWrite
+func InvalidateCache(collection string) {
+	Cache.Invalidate(context.Background(), collection)
+}
+
+// cachedResources - колекції кешу; їхні зміни в базі теж скидають кеш
+var cachedResources = []events.Resource{
+	events.Model[models.Hospital]("hospitals"),
+	events.Model[models.Department]("departments"),
+	events.Model[models.Doctor]("doctors"),
+}
+
+// WatchCache скидає кеш за змінами в самій базі (change stream або
+// опитування, як для /events). db.OnWrite бачить лише записи цього
+// процесу, а так Last-Modified оновлюють і seed, purge, інші інстанси
+// та будь-які інші клієнти Mongo.
+func WatchCache(ctx context.Context, database *mongo.Database, interval time.Duration) error {
+	bus := events.NewBus()
+	go InvalidateOnChanges(ctx, bus)
+	return events.Watch(ctx, database, bus, interval, cachedResources...)
+}
+
+// InvalidateOnChanges скидає кеш колекції кожної події з bus, доки ctx не
+// скасовано. Якщо підписку закрито через переповнення, зміни могли
+// загубитися - тоді скидається кеш усіх колекцій.
+func InvalidateOnChanges(ctx context.Context, bus *events.Bus) {
+	for {
+		sub := bus.Subscribe(events.Filter{}, eventBuffer)
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case e, ok := <-sub.C:
+				if ok {
+					InvalidateCache(e.Resource)
+				}
+				open = ok
+			}
+		}
+		for _, resource := range cachedResources {
+			InvalidateCache(resource.Name)
+		}
+	}
+}
+
+// cacheHeaders ставить Cache-Control, Last-Modified і X-Cache. Якщо колекція
+// не змінювалася після If-Modified-Since, відповідає 304 і повертає true.
+func cacheHeaders(w http.ResponseWriter, r *http.Request, meta repository.Meta) bool {
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(cacheTTL.Seconds())))
+	w.Header().Set("Last-Modified", meta.Modified.UTC().Format(http.TimeFormat))
+	if meta.Hit {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		if !meta.Modified.Truncate(time.Second).After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// MetricsRoutes - лічильники кешу у текстовому форматі Prometheus
+func MetricsRoutes(router *Router) {
+	metrics := router.Group("/metrics", LoggingMiddleware, JWT("admin")).Secured(SecurityBearer)
+	metrics.HandleFunc(http.MethodGet, "", metricsHandler).
+		Describe("Cache hit and miss counters in Prometheus text format").Returns(nil, "text/plain")
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	stats := Cache.Stats()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics := []struct {
+		name, kind, help string
+		value            func(cache.Stats) interface{}
+	}{
+		{"hospital_cache_hits_total", "counter", "Reads served from the cache", func(s cache.Stats) interface{} { return s.Hits }},
+		{"hospital_cache_misses_total", "counter", "Reads that went to MongoDB", func(s cache.Stats) interface{} { return s.Misses }},
+		{"hospital_cache_evictions_total", "counter", "Entries evicted by size bounds", func(s cache.Stats) interface{} { return s.Evictions }},
+		{"hospital_cache_invalidations_total", "counter", "Writes that cleared the collection cache", func(s cache.Stats) interface{} { return s.Invalidations }},
+		{"hospital_cache_entries", "gauge", "Entries currently cached", func(s cache.Stats) interface{} { return s.Entries }},
+		{"hospital_cache_bytes", "gauge", "Bytes currently cached", func(s cache.Stats) interface{} { return s.Bytes }},
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s{collection=%q} %v\n", m.name, name, m.value(stats[name]))
+		}
+	}
+}
@@ -0,0 +1,546 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/ical"
+	"hospital-api/models"
+	"hospital-api/recurrence"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// calendarHistory - за скільки минулих днів стрічка ще показує записи
+const calendarHistory = 30 * 24 * time.Hour
+
+// Безкінечні повтори з імпортованого календаря блокуємо лише на рік уперед
+const importHorizon = 365 * 24 * time.Hour
+
+// Стрічки .ics, токени до них і блоки недоступності лікарів
+func CalendarRoutes(router *Router) {
+	admin := RequireRole("admin")
+	token := Param{Name: "token", Description: "Calendar feed token from POST /calendar/token (instead of a Bearer JWT)"}
+
+	feeds := router.Group("", LoggingMiddleware, FeedAuth("reader", "admin")).Secured(SecurityFeedToken)
+	feeds.HandleFunc(http.MethodGet, "/doctors/{id}/calendar.ics", doctorCalendar).
+		Describe("Doctor's appointments and unavailable time as an iCalendar feed").Query(token).Returns(nil, "text/calendar")
+	feeds.HandleFunc(http.MethodGet, "/patients/{id}/calendar.ics", patientCalendar).
+		Describe("Patient's appointments as an iCalendar feed").Query(token).Returns(nil, "text/calendar")
+
+	tokens := router.Group("/calendar/token", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	tokens.HandleFunc(http.MethodPost, "", issueCalendarToken).
+		Describe("Issue a token for one calendar feed; the previous token for that feed stops working").
+		Query(feedParams...).Returns(calendarToken{})
+	tokens.HandleFunc(http.MethodDelete, "", revokeCalendarToken).
+		Describe("Revoke the token for one calendar feed, or all of them without a feed").
+		Query(feedParams...)
+
+	blocks := router.Group("/doctors/{id}/unavailability", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	blocks.HandleFunc(http.MethodGet, "", listUnavailability).
+		Describe("List unavailable time of a doctor").Returns([]models.Unavailability{})
+	blocks.HandleFunc(http.MethodPost, "/import", importUnavailability, admin).
+		Describe("Block out unavailable time from an .ics file").
+		Query(Param{Name: "dryRun", Type: "boolean", Description: "Only parse the file and report what would be blocked"}).
+		Accepts("", "text/calendar").Returns(calendarImport{})
+	blocks.HandleFunc(http.MethodDelete, "/{blockId}", deleteUnavailability, admin).
+		Describe("Remove a block of unavailable time")
+}
+
+// Query-параметри, що обирають стрічку для токена
+var feedParams = []Param{
+	{Name: "doctorId", Description: "Doctor ObjectID: token for /doctors/{id}/calendar.ics"},
+	{Name: "patientId", Description: "Patient ObjectID: token for /patients/{id}/calendar.ics"},
+}
+
+// FeedAuth - як JWT, але приймає й ?token= календарної стрічки: календарні
+// програми підписуються на URL і заголовків не надсилають. Токен відкриває
+// лише ту стрічку, для якої його видано.
+func FeedAuth(allowedRoles ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		bearer := JWTAuthMiddleware(next, allowedRoles...)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get("token")
+			if token == "" {
+				bearer.ServeHTTP(w, r)
+				return
+			}
+
+			var saved models.CalendarToken
+			err := db.Collection("calendar_tokens").FindOne(r.Context(), bson.M{"tokenHash": hashToken(token)}).Decode(&saved)
+			user, known := users[saved.Username]
+			if err != nil || !known {
+				httpError(w, r, http.StatusUnauthorized, "calendar.invalid_token")
+				return
+			}
+			if !slices.Contains(allowedRoles, user.Role) {
+				httpError(w, r, http.StatusForbidden, "auth.forbidden")
+				return
+			}
+			if r.URL.Path != saved.Path() {
+				httpError(w, r, http.StatusForbidden, "calendar.token_other_feed")
+				return
+			}
+			ctx := context.WithValue(r.Context(), "claims", &Claims{Username: saved.Username, Role: user.Role})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// calendarToken - відповідь POST /calendar/token; токен більше не показується
+type calendarToken struct {
+	Token     string    `json:"token"`
+	Feed      string    `json:"feed"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// tokenFeed - стрічка з ?doctorId= або ?patientId= (рівно один з них)
+func tokenFeed(r *http.Request) (string, primitive.ObjectID, bool) {
+	query := r.URL.Query()
+	doctorID, patientID := query.Get("doctorId"), query.Get("patientId")
+	feed, value := "doctors", doctorID
+	if patientID != "" {
+		feed, value = "patients", patientID
+	}
+	id, err := primitive.ObjectIDFromHex(value)
+	if err != nil || doctorID != "" && patientID != "" {
+		return "", primitive.NilObjectID, false
+	}
+	return feed, id, true
+}
+
+func issueCalendarToken(w http.ResponseWriter, r *http.Request) {
+	feed, feedID, ok := tokenFeed(r)
+	if !ok {
+		httpError(w, r, http.StatusBadRequest, "calendar.feed_required")
+		return
+	}
+	b := make([]byte, 24)
+	rand.Read(b)
+	token := "cal_" + hex.EncodeToString(b)
+	saved := models.CalendarToken{
+		Username:  GetClaims(r).Username,
+		Feed:      feed,
+		FeedID:    feedID,
+		TokenHash: hashToken(token),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Один токен на користувача і стрічку: новий замінює попередній
+	filter := bson.M{"username": saved.Username, "feed": feed, "feedId": feedID}
+	_, err := db.Collection("calendar_tokens").ReplaceOne(context.TODO(), filter, saved, options.Replace().SetUpsert(true))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, calendarToken{Token: token, Feed: saved.Path(), CreatedAt: saved.CreatedAt})
+}
+
+func revokeCalendarToken(w http.ResponseWriter, r *http.Request) {
+	filter := bson.M{"username": GetClaims(r).Username}
+	if query := r.URL.Query(); query.Get("doctorId") != "" || query.Get("patientId") != "" {
+		feed, feedID, ok := tokenFeed(r)
+		if !ok {
+			httpError(w, r, http.StatusBadRequest, "calendar.feed_required")
+			return
+		}
+		filter["feed"], filter["feedId"] = feed, feedID
+	}
+	if _, err := db.Collection("calendar_tokens").DeleteMany(context.TODO(), filter); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, tr(r, "calendar.token_revoked"))
+}
+
+func doctorCalendar(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	ctx := context.TODO()
+	doctor, _, err := doctorsRepo.FindOne(ctx, alive(bson.M{"_id": objID}))
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "doctor.not_found")
+		return
+	}
+
+	since := time.Now().Add(-calendarHistory)
+	appointments, err := findSeriesAppointments(ctx, alive(bson.M{"doctorId": objID, "date": bson.M{"$gte": since}}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	blocks, err := findUnavailability(ctx, bson.M{"doctorId": objID, "end": bson.M{"$gte": since}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	calendar := ical.Calendar{Name: doctor.Name, Location: seriesZone}
+	for _, a := range appointments {
+		calendar.Events = append(calendar.Events, appointmentEvent(a, tr(r, "calendar.doctor_event", a.PatientID.Hex())))
+	}
+	for _, b := range blocks {
+		summary := b.Summary
+		if summary == "" {
+			summary = tr(r, "calendar.unavailable")
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID: b.ID.Hex() + "@hospital-api", Start: b.Start, End: b.End,
+			Summary: summary, Status: ical.StatusConfirmed,
+		})
+	}
+	writeCalendar(w, r, calendar)
+}
+
+func patientCalendar(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	ctx := context.TODO()
+	appointments, err := findSeriesAppointments(ctx, alive(bson.M{
+		"patientId": objID,
+		"date":      bson.M{"$gte": time.Now().Add(-calendarHistory)},
+	}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Імена лікарів - одним запитом на всю стрічку
+	var doctorIDs []primitive.ObjectID
+	for _, a := range appointments {
+		if !slices.Contains(doctorIDs, a.DoctorID) {
+			doctorIDs = append(doctorIDs, a.DoctorID)
+		}
+	}
+	names := map[primitive.ObjectID]string{}
+	if len(doctorIDs) > 0 {
+		doctors, _, err := doctorsRepo.Find(ctx, bson.M{"_id": bson.M{"$in": doctorIDs}})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, d := range doctors {
+			names[d.ID] = d.Name
+		}
+	}
+
+	calendar := ical.Calendar{Name: tr(r, "calendar.patient_feed", objID.Hex()), Location: seriesZone}
+	for _, a := range appointments {
+		doctor := names[a.DoctorID]
+		if doctor == "" {
+			doctor = a.DoctorID.Hex()
+		}
+		calendar.Events = append(calendar.Events, appointmentEvent(a, tr(r, "calendar.patient_event", doctor)))
+	}
+	writeCalendar(w, r, calendar)
+}
+
+// appointmentEvent - VEVENT запису; скасовані лишаються в стрічці як
+// CANCELLED, щоб календарні програми прибрали їх у себе
+func appointmentEvent(a models.Appointment, summary string) ical.Event {
+	status := ical.StatusConfirmed
+	if a.Released() {
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:         a.ID.Hex() + "@hospital-api",
+		Start:       a.Date,
+		End:         a.Date.Add(appointmentSlot),
+		Summary:     summary,
+		Description: "Status: " + a.State(),
+		Status:      status,
+	}
+}
+
+func writeCalendar(w http.ResponseWriter, r *http.Request, calendar ical.Calendar) {
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	w.Header().Add("Vary", "Accept-Encoding")
+	body := compressBody(w, r, buf.Bytes())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
+
+func findUnavailability(ctx context.Context, filter bson.M) ([]models.Unavailability, error) {
+	cursor, err := db.Collection("unavailability").Find(ctx, filter, options.Find().SetSort(bson.M{"start": 1}))
+	if err != nil {
+		return nil, err
+	}
+	blocks := []models.Unavailability{}
+	err = cursor.All(ctx, &blocks)
+	return blocks, err
+}
+
+func listUnavailability(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	blocks, err := findUnavailability(context.TODO(), bson.M{"doctorId": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, blocks)
+}
+
+func deleteUnavailability(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	blockID, err := primitive.ObjectIDFromHex(r.PathValue("blockId"))
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.invalid_id")
+		return
+	}
+	res, err := db.Collection("unavailability").DeleteOne(context.TODO(), bson.M{"_id": blockID, "doctorId": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.DeletedCount == 0 {
+		httpError(w, r, http.StatusNotFound, "unavailability.not_found")
+		return
+	}
+	fmt.Fprint(w, tr(r, "unavailability.deleted"))
+}
+
+// calendarImport - звіт імпорту .ics: Skipped - скасовані й вільні
+// (TRANSP:TRANSPARENT) події, Blocked - скільки блоків дали решта
+type calendarImport struct {
+	DryRun   bool             `json:"dryRun"`
+	Total    int              `json:"total"`
+	Skipped  int              `json:"skipped"`
+	Failed   int              `json:"failed"`
+	Blocked  int              `json:"blocked"`
+	Imported int              `json:"imported"`
+	Errors   []importRowError `json:"errors,omitempty"`
+}
+
+// importUnavailability - POST /doctors/{id}/unavailability/import. Кожна
+// зайнята подія (і кожен її повтор) стає блоком; блоки з тим самим UID
+// замінюються, тож файл можна імпортувати повторно. Зміна повтору
+// (RECURRENCE-ID) замінює його блок, а скасована - видаляє.
+func importUnavailability(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if mediaType, _, _ := mime.ParseMediaType(ct); mediaType != "text/calendar" {
+			httpError(w, r, http.StatusUnsupportedMediaType, "import.unsupported_calendar")
+			return
+		}
+	}
+
+	result := calendarImport{DryRun: r.URL.Query().Get("dryRun") == "true"}
+	var blocks []models.Unavailability
+	var overrides []calendarOverride
+	err := ical.Read(r.Body, seriesZone, func(line int, e ical.Event, err error) error {
+		result.Total++
+		// Зміни окремих повторів застосовуються, коли всі повтори розгорнуто
+		if err == nil && !e.RecurrenceID.IsZero() {
+			overrides = append(overrides, calendarOverride{line: line, event: e})
+			return nil
+		}
+		if err == nil && (e.Status == ical.StatusCancelled || e.Transparent) {
+			result.Skipped++
+			return nil
+		}
+		var found []models.Unavailability
+		if err == nil {
+			found, err = eventBlocks(objID, e)
+		}
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, importRowError{Line: line, Error: err.Error()})
+			return nil
+		}
+		blocks = append(blocks, found...)
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	blocks, removed := applyOverrides(objID, blocks, overrides, &result)
+	result.Blocked = len(blocks)
+
+	if !result.DryRun && len(blocks)+len(removed) > 0 {
+		if !exists(db.Collection("doctors"), objID) {
+			httpError(w, r, http.StatusNotFound, "doctor.not_found")
+			return
+		}
+		writes := make([]mongo.WriteModel, 0, len(blocks)+len(removed))
+		for _, b := range blocks {
+			writes = append(writes, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"doctorId": b.DoctorID, "uid": b.UID}).
+				SetReplacement(b).
+				SetUpsert(true))
+		}
+		// Скасований повтор міг бути імпортований раніше
+		for _, uid := range removed {
+			writes = append(writes, mongo.NewDeleteOneModel().SetFilter(bson.M{"doctorId": objID, "uid": uid}))
+		}
+		if _, err := db.Collection("unavailability").BulkWrite(context.TODO(), writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result.Imported = len(blocks)
+	}
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// calendarOverride - подія з RECURRENCE-ID і рядок її BEGIN
+type calendarOverride struct {
+	line  int
+	event ical.Event
+}
+
+// occurrenceUID - UID блоку одного повтору події uid
+func occurrenceUID(uid string, date time.Time) string {
+	return uid + "/" + date.UTC().Format("20060102T150405Z")
+}
+
+// applyOverrides замінює повтори їхніми змінами (RECURRENCE-ID) під тим
+// самим UID блоку; скасовані чи прозорі зміни прибирають повтор і
+// повертаються в removed. Основної події у файлі може й не бути -
+// тоді зміна замінює блок, імпортований раніше.
+func applyOverrides(doctorID primitive.ObjectID, blocks []models.Unavailability, overrides []calendarOverride, result *calendarImport) ([]models.Unavailability, []string) {
+	index := make(map[string]int, len(blocks))
+	for i, b := range blocks {
+		index[b.UID] = i
+	}
+	drop := map[string]bool{}
+	var removed []string
+	for _, o := range overrides {
+		e := o.event
+		if e.UID == "" {
+			result.Failed++
+			result.Errors = append(result.Errors, importRowError{Line: o.line, Error: "RECURRENCE-ID without UID"})
+			continue
+		}
+		uid := occurrenceUID(e.UID, e.RecurrenceID)
+		if e.Status == ical.StatusCancelled || e.Transparent {
+			result.Skipped++
+			drop[uid] = true
+			removed = append(removed, uid)
+			continue
+		}
+		block := models.Unavailability{DoctorID: doctorID, Start: e.Start.UTC(), End: e.End.UTC(), Summary: e.Summary, UID: uid}
+		if err := block.Validate(); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, importRowError{Line: o.line, Error: err.Error()})
+			continue
+		}
+		delete(drop, uid)
+		if i, ok := index[uid]; ok {
+			blocks[i] = block
+		} else {
+			index[uid] = len(blocks)
+			blocks = append(blocks, block)
+		}
+	}
+
+	kept := blocks[:0]
+	for _, b := range blocks {
+		if !drop[b.UID] {
+			kept = append(kept, b)
+		}
+	}
+	// Повтор, скасований і знову змінений у тому самому файлі, лишається
+	gone := removed[:0]
+	for _, uid := range removed {
+		if drop[uid] {
+			gone = append(gone, uid)
+		}
+	}
+	return kept, gone
+}
+
+// eventBlocks - блоки однієї події; повтори розгортаються тим самим
+// recurrence, що й серії записів, без дат з EXDATE, і кожен має власний UID
+func eventBlocks(doctorID primitive.ObjectID, e ical.Event) ([]models.Unavailability, error) {
+	uid := e.UID
+	if uid == "" {
+		uid = e.Start.UTC().Format(time.RFC3339) + "/" + e.End.UTC().Format(time.RFC3339)
+	}
+	block := models.Unavailability{DoctorID: doctorID, Start: e.Start.UTC(), End: e.End.UTC(), Summary: e.Summary, UID: uid}
+	if e.RRule == "" {
+		return []models.Unavailability{block}, block.Validate()
+	}
+
+	// Безкінечне правило розгортаємо лише на importHorizon уперед і без
+	// повторів, що вже закінчилися: давня щотижнева подія інакше дала б
+	// більше recurrence.MaxOccurrences повторів
+	rrule := e.RRule
+	var from time.Time
+	if upper := strings.ToUpper(rrule); !strings.Contains(upper, "COUNT=") && !strings.Contains(upper, "UNTIL=") {
+		now := time.Now()
+		rrule += ";UNTIL=" + now.Add(importHorizon).UTC().Format("20060102T150405Z")
+		from = now.Add(-e.End.Sub(e.Start))
+	}
+	rule, err := recurrence.Parse(rrule)
+	if err != nil {
+		return nil, fmt.Errorf("RRULE: %w", err)
+	}
+	dates, err := rule.ExpandFrom(e.Start, from, e.Start.Location())
+	if err != nil {
+		return nil, fmt.Errorf("RRULE: %w", err)
+	}
+
+	length := e.End.Sub(e.Start)
+	blocks := make([]models.Unavailability, 0, len(dates))
+	for _, date := range dates {
+		if excluded(e.ExDates, date) {
+			continue
+		}
+		b := block
+		b.Start = date.UTC()
+		b.End = date.Add(length).UTC()
+		b.UID = occurrenceUID(uid, date)
+		if err := b.Validate(); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+func excluded(exDates []time.Time, date time.Time) bool {
+	for _, ex := range exDates {
+		if ex.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Каскадне видалення лікарні: разом з нею м'яко видаляються її відділення,
+// лікарі цих відділень, палати, ліжка, персонал і зміни - однією одиницею
+// роботи. Усі документи отримують ту саму позначку deletedAt, тож
+// відновлення лікарні повертає саме їх, а не видалене окремо раніше.
+
+var (
+	errHospitalNotFound = errors.New("hospital not found")
+	errHospitalOccupied = errors.New("hospital has occupied beds")
+)
+
+// cascadeTarget - колекція і фільтр документів, що належать лікарні
+type cascadeTarget struct {
+	collection string
+	filter     bson.M
+}
+
+func hospitalCascade(ctx context.Context, hospitalID primitive.ObjectID) ([]cascadeTarget, error) {
+	departmentIDs, err := db.Collection("departments").Distinct(ctx, "_id", bson.M{"hospital_id": hospitalID})
+	if err != nil {
+		return nil, err
+	}
+	return []cascadeTarget{
+		{"beds", bson.M{"hospital_id": hospitalID}},
+		{"wards", bson.M{"hospital_id": hospitalID}},
+		{"departments", bson.M{"hospital_id": hospitalID}},
+		{"doctors", bson.M{"department": bson.M{"$in": departmentIDs}}},
+		{"staff", bson.M{"hospital_id": hospitalID}},
+		{"shifts", bson.M{"hospital_id": hospitalID}},
+	}, nil
+}
+
+// deleteHospitalCascade видаляє лікарню з усім, що їй належить. Лікарню з
+// зайнятими ліжками не видаляємо: спершу треба виписати пацієнтів.
+func deleteHospitalCascade(ctx context.Context, hospitalID primitive.ObjectID, by string) error {
+	mark := bson.M{"deletedAt": time.Now().UTC().Truncate(time.Millisecond), "deletedBy": by}
+	return db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		marked, err := markDeleted(u, "hospitals", bson.M{"_id": hospitalID}, mark)
+		if err != nil {
+			return err
+		}
+		if marked == 0 {
+			return errHospitalNotFound
+		}
+
+		targets, err := hospitalCascade(u.Context(), hospitalID)
+		if err != nil {
+			return err
+		}
+		for _, t := range targets {
+			if _, err := markDeleted(u, t.collection, t.filter, mark); err != nil {
+				return err
+			}
+			if t.collection != "beds" {
+				continue
+			}
+			// Перевіряємо вже позначені ліжка: так і паралельна госпіталізація,
+			// що встигла зайняти ліжко до позначки, скасує видалення
+			err := u.Step(func(ctx context.Context) error {
+				n, err := db.Collection("beds").CountDocuments(ctx,
+					withFields(t.filter, mark, bson.M{"patientId": bson.M{"$exists": true}}))
+				if err == nil && n > 0 {
+					err = errHospitalOccupied
+				}
+				return err
+			}, nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// restoreHospitalCascade повертає лікарню і документи, видалені разом з нею
+func restoreHospitalCascade(ctx context.Context, hospitalID primitive.ObjectID, mark bson.M) error {
+	return db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		restored, err := unmarkDeleted(u, "hospitals", bson.M{"_id": hospitalID}, mark)
+		if err != nil {
+			return err
+		}
+		if restored == 0 {
+			return errHospitalNotFound
+		}
+
+		targets, err := hospitalCascade(u.Context(), hospitalID)
+		if err != nil {
+			return err
+		}
+		for _, t := range targets {
+			if _, err := unmarkDeleted(u, t.collection, t.filter, mark); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// markDeleted позначає живі документи за filter позначкою mark; undo
+// знімає позначку лише з них. Повертає кількість позначених документів.
+func markDeleted(u *db.UnitOfWork, collection string, filter, mark bson.M) (int64, error) {
+	col := db.Collection(collection)
+	var marked int64
+	err := u.Step(func(ctx context.Context) error {
+		res, err := col.UpdateMany(ctx, alive(withFields(filter)), bson.M{"$set": mark})
+		if err == nil {
+			marked = res.ModifiedCount
+		}
+		return err
+	}, func(ctx context.Context) error {
+		_, err := col.UpdateMany(ctx, withFields(filter, mark), bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}})
+		return err
+	})
+	return marked, err
+}
+
+// unmarkDeleted знімає позначку mark з документів за filter; undo
+// повертає її саме тим документам, які було відновлено
+func unmarkDeleted(u *db.UnitOfWork, collection string, filter, mark bson.M) (int64, error) {
+	col := db.Collection(collection)
+	var ids []interface{}
+	err := u.Step(func(ctx context.Context) error {
+		var err error
+		ids, err = col.Distinct(ctx, "_id", withFields(filter, mark))
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		_, err = col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}})
+		return err
+	}, func(ctx context.Context) error {
+		_, err := col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": mark})
+		return err
+	})
+	return int64(len(ids)), err
+}
+
+// withFields - новий фільтр з полями всіх переданих; вихідні не змінюються
+func withFields(filters ...bson.M) bson.M {
+	out := bson.M{}
+	for _, f := range filters {
+		for k, v := range f {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// restoreHospital відновлює лікарню разом з усім, що видалили каскадом
+func restoreHospital(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var hospital models.Hospital
+	err := db.Collection("hospitals").FindOne(context.TODO(), bson.M{"_id": objID, "deletedAt": bson.M{"$exists": true}}).Decode(&hospital)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "hospital.not_in_trash")
+		return
+	}
+
+	mark := bson.M{"deletedAt": *hospital.DeletedAt, "deletedBy": hospital.DeletedBy}
+	err = restoreHospitalCascade(context.TODO(), objID, mark)
+	if errors.Is(err, errHospitalNotFound) {
+		httpError(w, r, http.StatusNotFound, "hospital.not_in_trash")
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
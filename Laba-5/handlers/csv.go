@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CSV-колонки моделей - це їхні json-імена, тому CSV і JSON
+// використовують однакові назви полів.
+
+var (
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+// csvFields повертає колонки для структури t у порядку оголошення полів
+func csvFields(t reflect.Type) []csvField {
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		ft := f.Type
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for _, inner := range csvFields(ft) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, csvField{name: name, index: []int{i}})
+	}
+	return fields
+}
+
+func csvHeader(t reflect.Type) []string {
+	fields := csvFields(t)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	return header
+}
+
+// csvRecord форматує одну структуру в рядок CSV
+func csvRecord(v reflect.Value) []string {
+	fields := csvFields(v.Type())
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		record[i] = formatCSVValue(v.FieldByIndex(f.index))
+	}
+	return record
+}
+
+func formatCSVValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == objectIDType:
+		id := v.Interface().(primitive.ObjectID)
+		if id.IsZero() {
+			return ""
+		}
+		return id.Hex()
+	case v.Type() == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// decodeCSVRecord заповнює структуру dst (вказівник) значеннями рядка CSV.
+// Порожні клітинки залишають нульове значення поля.
+func decodeCSVRecord(header, record []string, dst interface{}) error {
+	v := reflect.ValueOf(dst).Elem()
+	byName := map[string][]int{}
+	for _, f := range csvFields(v.Type()) {
+		byName[f.name] = f.index
+	}
+
+	for i, column := range header {
+		if i >= len(record) {
+			break
+		}
+		cell := strings.TrimSpace(record[i])
+		if cell == "" {
+			continue
+		}
+		index, ok := byName[strings.TrimSpace(column)]
+		if !ok {
+			return fmt.Errorf("unknown column %q", column)
+		}
+		if err := setCSVValue(v.FieldByIndex(index), cell); err != nil {
+			return fmt.Errorf("%s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+func setCSVValue(v reflect.Value, cell string) error {
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == objectIDType:
+		id, err := primitive.ObjectIDFromHex(cell)
+		if err != nil {
+			return fmt.Errorf("invalid ObjectID %q", cell)
+		}
+		v.Set(reflect.ValueOf(id))
+		return nil
+	case v.Type() == timeType:
+		t, err := time.Parse(time.RFC3339, cell)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", cell); err != nil {
+				return fmt.Errorf("invalid date %q", cell)
+			}
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", cell)
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", cell)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", cell)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported column type %s", v.Type())
+	}
+	return nil
+}
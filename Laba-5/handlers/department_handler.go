@@ -4,9 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -17,178 +16,197 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Middleware для логування
-func LoggingMiddlewareDepartments(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f, err := os.OpenFile("access.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Println("Cannot open log file:", err)
-		} else {
-			defer f.Close()
-			log.SetOutput(f)
-			log.Printf("%s %s\n", r.Method, r.URL.Path)
-		}
-		next.ServeHTTP(w, r)
-	})
-}
-
-// Middleware для простого ключа авторизації
-func AuthMiddlewareDepartments(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		const apiKey = "my-secret-key"
-		key := r.Header.Get("X-API-KEY")
-		if key != apiKey {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
+func DepartmentRoutes(router *Router) {
+	departments := router.Group("/departments", LoggingMiddleware, APIKeyMiddleware).Secured(SecurityAPIKey)
+
+	departments.HandleFunc(http.MethodGet, "", listDepartments).
+		Describe("List departments").Query(departmentParams...).Query(includeDeletedParam).Query(expandParam("departments")).Returns([]models.Department{})
+	departments.HandleFunc(http.MethodPost, "", createDepartment).
+		Describe("Create a department").Accepts(models.Department{}).Returns(models.Department{})
+	departments.HandleFunc(http.MethodGet, "/{id}", getDepartment).
+		Describe("Get a department").Query(includeDeletedParam).Query(expandParam("departments")).Returns(models.Department{})
+	departments.HandleFunc(http.MethodPut, "/{id}", updateDepartment).
+		Describe("Update a department").Accepts(models.Department{})
+	departments.HandleFunc(http.MethodDelete, "/{id}", deleteDepartment).
+		Describe("Soft-delete a department")
+
+	restoreRoute(router, "/departments", "Department", restoreHandler("departments", "department.not_in_trash"))
+
+	departments.Handle(http.MethodPost, "/import", importHandler[models.Department]("departments")).
+		Describe("Import departments from CSV or NDJSON").Query(importParams...).
+		Accepts([]models.Department{}, "text/csv", "application/x-ndjson").Returns(importResult{})
+	departments.Handle(http.MethodGet, "/export", exportHandler[models.Department]("departments", departmentFilter)).
+		Describe("Export departments as CSV or NDJSON").Query(departmentParams...).Query(exportParams...).
+		Returns([]models.Department{}, "text/csv", "application/x-ndjson")
+
+	// Відділення лікарні
+	byHospital := router.Group("/hospitals/{id}/departments", LoggingMiddleware, APIKeyMiddleware).Secured(SecurityAPIKey)
+	byHospital.HandleFunc(http.MethodGet, "", nestedList("hospitalId", listDepartments)).
+		Describe("List departments of a hospital").Query(departmentParams...).Query(includeDeletedParam).Query(expandParam("departments")).
+		Returns([]models.Department{})
 }
 
-func DepartmentRoutes() {
-	http.Handle("/departments", LoggingMiddlewareDepartments(AuthMiddlewareDepartments(http.HandlerFunc(departmentsHandler))))
-	http.Handle("/departments/", LoggingMiddlewareDepartments(AuthMiddlewareDepartments(http.HandlerFunc(departmentHandler))))
-}
-
-func departmentsHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospital_db").Collection("departments")
+func listDepartments(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, departmentFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Department](w, r, "departments", filter, expand, false)
+		return
+	}
 
-	switch r.Method {
-	case http.MethodGet:
-		// Фільтр через query params
-		filter := bson.M{}
-		query := r.URL.Query()
+	// Запит до MongoDB або кешу
+	departments, meta, err := departmentsRepo.Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if cacheHeaders(w, r, meta) {
+		return
+	}
 
-		// Пошук по імені (частковий, нечутливий до регістру)
-		if name := strings.TrimSpace(query.Get("name")); name != "" {
-			filter["name"] = bson.M{"$regex": name, "$options": "i"}
-		}
+	writeResponse(w, r, http.StatusOK, departments)
+}
 
-		// Пошук по hospitalId
-		if hospital := strings.TrimSpace(query.Get("hospitalId")); hospital != "" {
-			if objID, err := primitive.ObjectIDFromHex(hospital); err == nil {
-				filter["hospital_id"] = objID
-			}
-		}
+func createDepartment(w http.ResponseWriter, r *http.Request) {
+	var department models.Department
+	if err := json.NewDecoder(r.Body).Decode(&department); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	department.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
 
-		// Пошук по floor (точне значення або діапазон)
-		if floorStr := strings.TrimSpace(query.Get("floor")); floorStr != "" {
-			if floorInt, err := strconv.Atoi(floorStr); err == nil {
-				filter["floor"] = floorInt
-			} else {
-				filter["floor"] = floorStr
-			}
-		} else {
-			// Підтримка minFloor / maxFloor
-			minFloorStr := strings.TrimSpace(query.Get("minFloor"))
-			maxFloorStr := strings.TrimSpace(query.Get("maxFloor"))
-			rangeFilter := bson.M{}
-
-			if minFloorStr != "" {
-				if minFloor, err := strconv.Atoi(minFloorStr); err == nil {
-					rangeFilter["$gte"] = minFloor
-				}
-			}
-			if maxFloorStr != "" {
-				if maxFloor, err := strconv.Atoi(maxFloorStr); err == nil {
-					rangeFilter["$lte"] = maxFloor
-				}
-			}
-			if len(rangeFilter) > 0 {
-				filter["floor"] = rangeFilter
-			}
-		}
+	res, err := db.Collection("departments").InsertOne(context.TODO(), department)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	department.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusOK, department)
+}
 
-		// Виконуємо запит до MongoDB
-		cursor, err := col.Find(context.TODO(), filter)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(context.TODO())
+func getDepartment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Department](w, r, "departments", filter, expand, true)
+		return
+	}
 
-		var departments []models.Department
-		if err := cursor.All(context.TODO(), &departments); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	department, meta, err := departmentsRepo.FindOne(context.TODO(), filter)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "department.not_found")
+		return
+	}
+	if cacheHeaders(w, r, meta) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, department)
+}
 
-		writeJSONDepartments(w, departments)
+func updateDepartment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-	case http.MethodPost:
-		var department models.Department
-		if err := json.NewDecoder(r.Body).Decode(&department); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	var update models.Department
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		res, err := col.InsertOne(context.TODO(), department)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		department.ID = res.InsertedID.(primitive.ObjectID)
-		writeJSONDepartments(w, department)
+	updateMap := bson.M{
+		"name":        update.Name,
+		"hospital_id": update.HospitalID,
+		"floor":       update.Floor,
+	}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	_, err := db.Collection("departments").UpdateOne(context.TODO(), alive(bson.M{"_id": objID}), bson.M{"$set": updateMap})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	fmt.Fprint(w, tr(r, "department.updated"))
 }
 
-func departmentHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospitaldb").Collection("departments")
-	id := strings.TrimPrefix(r.URL.Path, "/departments/")
-	objID, err := primitive.ObjectIDFromHex(id)
+func deleteDepartment(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := softDelete(context.TODO(), r, "departments", bson.M{"_id": objID})
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		httpError(w, r, http.StatusNotFound, "department.not_found")
 		return
 	}
+	fmt.Fprint(w, tr(r, "department.deleted"))
+}
 
-	switch r.Method {
-	case http.MethodGet:
-		var department models.Department
-		err := col.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&department)
-		if err != nil {
-			http.Error(w, "Department not found", http.StatusNotFound)
-			return
-		}
-		writeJSONDepartments(w, department)
+// Query-параметри departmentFilter для документації
+var departmentParams = []Param{
+	{Name: "name", Description: "Case-insensitive substring of the name"},
+	{Name: "hospitalId", Description: "Hospital ObjectID"},
+	{Name: "floor", Type: "integer", Description: "Exact floor"},
+	{Name: "minFloor", Type: "integer", Description: "Lowest floor"},
+	{Name: "maxFloor", Type: "integer", Description: "Highest floor"},
+}
 
-	case http.MethodPut:
-		var update models.Department
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+// Фільтр списку відділень через query params
+func departmentFilter(query url.Values) bson.M {
+	filter := bson.M{}
 
-		updateMap := bson.M{
-			"name":        update.Name,
-			"hospital_id": update.HospitalID,
-			"floor":       update.Floor,
-		}
+	// Пошук по імені (частковий, нечутливий до регістру)
+	if name := strings.TrimSpace(query.Get("name")); name != "" {
+		filter["name"] = containsFilter(name)
+	}
 
-		_, err := col.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": updateMap})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+	// Пошук по hospitalId
+	if hospital := strings.TrimSpace(query.Get("hospitalId")); hospital != "" {
+		if objID, err := primitive.ObjectIDFromHex(hospital); err == nil {
+			filter["hospital_id"] = objID
 		}
-		fmt.Fprintf(w, "Department updated successfully")
+	}
 
-	case http.MethodDelete:
-		_, err := col.DeleteOne(context.TODO(), bson.M{"_id": objID})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+	// Пошук по floor (точне значення або діапазон)
+	if floorStr := strings.TrimSpace(query.Get("floor")); floorStr != "" {
+		if floorInt, err := strconv.Atoi(floorStr); err == nil {
+			filter["floor"] = floorInt
+		} else {
+			filter["floor"] = floorStr
+		}
+	} else {
+		// Підтримка minFloor / maxFloor
+		minFloorStr := strings.TrimSpace(query.Get("minFloor"))
+		maxFloorStr := strings.TrimSpace(query.Get("maxFloor"))
+		rangeFilter := bson.M{}
+
+		if minFloorStr != "" {
+			if minFloor, err := strconv.Atoi(minFloorStr); err == nil {
+				rangeFilter["$gte"] = minFloor
+			}
+		}
+		if maxFloorStr != "" {
+			if maxFloor, err := strconv.Atoi(maxFloorStr); err == nil {
+				rangeFilter["$lte"] = maxFloor
+			}
+		}
+		if len(rangeFilter) > 0 {
+			filter["floor"] = rangeFilter
 		}
-		fmt.Fprintf(w, "Department deleted successfully")
-
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
-}
 
-// Допоміжна функція для JSON відповіді
-func writeJSONDepartments(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	return filter
 }
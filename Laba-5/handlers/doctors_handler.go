@@ -4,9 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -17,180 +16,202 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func LoggingMiddlewareDoctors(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f, err := os.OpenFile("access.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Println("Cannot open log file:", err)
-		} else {
-			defer f.Close()
-			log.SetOutput(f)
-			log.Printf("%s %s\n", r.Method, r.URL.Path)
-		}
-		next.ServeHTTP(w, r)
-	})
+func DoctorRoutes(router *Router) {
+	doctors := router.Group("/doctors", LoggingMiddleware, APIKeyMiddleware).Secured(SecurityAPIKey)
+
+	doctors.HandleFunc(http.MethodGet, "", listDoctors).
+		Describe("List doctors").Query(doctorParams...).Query(includeDeletedParam).Query(expandParam("doctors")).Returns([]models.Doctor{})
+	doctors.HandleFunc(http.MethodPost, "", createDoctor).
+		Describe("Create a doctor").Accepts(models.Doctor{}).Returns(models.Doctor{})
+	doctors.HandleFunc(http.MethodGet, "/{id}", getDoctor).
+		Describe("Get a doctor").Query(includeDeletedParam).Query(expandParam("doctors")).Returns(models.Doctor{})
+	doctors.HandleFunc(http.MethodPut, "/{id}", updateDoctor).
+		Describe("Update a doctor").Accepts(models.Doctor{})
+	doctors.HandleFunc(http.MethodDelete, "/{id}", deleteDoctor).
+		Describe("Soft-delete a doctor")
+
+	restoreRoute(router, "/doctors", "Doctor", restoreHandler("doctors", "doctor.not_in_trash"))
+
+	doctors.Handle(http.MethodPost, "/import", importHandler[models.Doctor]("doctors")).
+		Describe("Import doctors from CSV or NDJSON").Query(importParams...).
+		Accepts([]models.Doctor{}, "text/csv", "application/x-ndjson").Returns(importResult{})
+	doctors.Handle(http.MethodGet, "/export", exportHandler[models.Doctor]("doctors", doctorFilter)).
+		Describe("Export doctors as CSV or NDJSON").Query(doctorParams...).Query(exportParams...).
+		Returns([]models.Doctor{}, "text/csv", "application/x-ndjson")
+
+	// Лікарі відділення
+	byDepartment := router.Group("/departments/{id}/doctors", LoggingMiddleware, APIKeyMiddleware).Secured(SecurityAPIKey)
+	byDepartment.HandleFunc(http.MethodGet, "", nestedList("department", listDoctors)).
+		Describe("List doctors of a department").Query(doctorParams...).Query(includeDeletedParam).Query(expandParam("doctors")).
+		Returns([]models.Doctor{})
 }
 
-func AuthMiddlewareDoctors(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		const apiKey = "my-secret-key"
-		key := r.Header.Get("X-API-KEY")
-		if key != apiKey {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
+func listDoctors(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, doctorFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Doctor](w, r, "doctors", filter, expand, false)
+		return
+	}
+
+	doctors, meta, err := doctorsRepo.Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if cacheHeaders(w, r, meta) {
+		return
+	}
 
-func DoctorRoutes() {
-	http.Handle("/doctors", LoggingMiddlewareDoctors(AuthMiddlewareDoctors(http.HandlerFunc(doctorsHandler))))
-	http.Handle("/doctors/", LoggingMiddlewareDoctors(AuthMiddlewareDoctors(http.HandlerFunc(doctorHandler))))
+	writeResponse(w, r, http.StatusOK, doctors)
 }
 
-func doctorsHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospital_db").Collection("doctors")
+func createDoctor(w http.ResponseWriter, r *http.Request) {
+	var doctor models.Doctor
+	if err := json.NewDecoder(r.Body).Decode(&doctor); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	doctor.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
 
-	switch r.Method {
-	case http.MethodGet:
-		// --- Фільтрація ---
-		filter := bson.M{}
-		query := r.URL.Query()
+	res, err := db.Collection("doctors").InsertOne(context.TODO(), doctor)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	doctor.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusOK, doctor)
+}
 
-		// Фільтр по імені (частковий, нечутливий до регістру)
-		if name := strings.TrimSpace(query.Get("name")); name != "" {
-			filter["name"] = bson.M{"$regex": name, "$options": "i"}
-		}
+func getDoctor(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Doctor](w, r, "doctors", filter, expand, true)
+		return
+	}
 
-		// Фільтр по спеціалізації
-		if specialty := strings.TrimSpace(query.Get("specialty")); specialty != "" {
-			filter["specialty"] = bson.M{"$regex": specialty, "$options": "i"}
-		}
+	doctor, meta, err := doctorsRepo.FindOne(context.TODO(), filter)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "doctor.not_found")
+		return
+	}
+	if cacheHeaders(w, r, meta) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, doctor)
+}
 
-		// Фільтр по департаменту (ObjectID)
-		if dep := strings.TrimSpace(query.Get("department")); dep != "" {
-			if objID, err := primitive.ObjectIDFromHex(dep); err == nil {
-				filter["department"] = objID
-			}
-		}
+func updateDoctor(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-		// Фільтр по досвіду (точне або діапазон)
-		if expStr := strings.TrimSpace(query.Get("experience_years")); expStr != "" {
-			if expInt, err := strconv.Atoi(expStr); err == nil {
-				filter["experience_years"] = expInt
-			} else {
-				filter["experience_years"] = expStr
-			}
-		} else {
-			minExpStr := strings.TrimSpace(query.Get("minExperience"))
-			maxExpStr := strings.TrimSpace(query.Get("maxExperience"))
-			rangeFilter := bson.M{}
-
-			if minExpStr != "" {
-				if minExp, err := strconv.Atoi(minExpStr); err == nil {
-					rangeFilter["$gte"] = minExp
-				}
-			}
-			if maxExpStr != "" {
-				if maxExp, err := strconv.Atoi(maxExpStr); err == nil {
-					rangeFilter["$lte"] = maxExp
-				}
-			}
-			if len(rangeFilter) > 0 {
-				filter["experience_years"] = rangeFilter
-			}
-		}
+	var update models.Doctor
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		cursor, err := col.Find(context.TODO(), filter)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(context.TODO())
+	updateMap := bson.M{
+		"name":             update.Name,
+		"specialty":        update.Specialty,
+		"department":       update.Department,
+		"experience_years": update.ExperienceYears,
+	}
 
-		var doctors []models.Doctor
-		if err := cursor.All(context.TODO(), &doctors); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+	_, err := db.Collection("doctors").UpdateOne(context.TODO(), alive(bson.M{"_id": objID}), bson.M{"$set": updateMap})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, tr(r, "doctor.updated"))
+}
 
-		writeJSONDoctor(w, doctors)
+func deleteDoctor(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-	case http.MethodPost:
-		var doctor models.Doctor
-		if err := json.NewDecoder(r.Body).Decode(&doctor); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	found, err := softDelete(context.TODO(), r, "doctors", bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		httpError(w, r, http.StatusNotFound, "doctor.not_found")
+		return
+	}
+	fmt.Fprint(w, tr(r, "doctor.deleted"))
+}
 
-		res, err := col.InsertOne(context.TODO(), doctor)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		doctor.ID = res.InsertedID.(primitive.ObjectID)
-		writeJSONDoctor(w, doctor)
+// Query-параметри doctorFilter для документації
+var doctorParams = []Param{
+	{Name: "name", Description: "Case-insensitive substring of the name"},
+	{Name: "specialty", Description: "Case-insensitive substring of the specialty"},
+	{Name: "department", Description: "Department ObjectID"},
+	{Name: "experience_years", Type: "integer", Description: "Exact years of experience"},
+	{Name: "minExperience", Type: "integer", Description: "Minimum years of experience"},
+	{Name: "maxExperience", Type: "integer", Description: "Maximum years of experience"},
+}
+
+// Фільтр списку лікарів через query params
+func doctorFilter(query url.Values) bson.M {
+	filter := bson.M{}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Фільтр по імені (частковий, нечутливий до регістру)
+	if name := strings.TrimSpace(query.Get("name")); name != "" {
+		filter["name"] = containsFilter(name)
 	}
-}
 
-func doctorHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospital_db").Collection("doctors")
-	id := strings.TrimPrefix(r.URL.Path, "/doctors/")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
-		return
+	// Фільтр по спеціалізації
+	if specialty := strings.TrimSpace(query.Get("specialty")); specialty != "" {
+		filter["specialty"] = containsFilter(specialty)
 	}
 
-	switch r.Method {
-	case http.MethodGet:
-		var doctor models.Doctor
-		err := col.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&doctor)
-		if err != nil {
-			http.Error(w, "Doctor not found", http.StatusNotFound)
-			return
+	// Фільтр по департаменту (ObjectID)
+	if dep := strings.TrimSpace(query.Get("department")); dep != "" {
+		if objID, err := primitive.ObjectIDFromHex(dep); err == nil {
+			filter["department"] = objID
 		}
-		writeJSON(w, doctor)
+	}
 
-	case http.MethodPut:
-		var update models.Doctor
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+	// Фільтр по досвіду (точне або діапазон)
+	if expStr := strings.TrimSpace(query.Get("experience_years")); expStr != "" {
+		if expInt, err := strconv.Atoi(expStr); err == nil {
+			filter["experience_years"] = expInt
+		} else {
+			filter["experience_years"] = expStr
 		}
-
-		updateMap := bson.M{
-			"name":             update.Name,
-			"specialty":        update.Specialty,
-			"department":       update.Department,
-			"experience_years": update.ExperienceYears,
+	} else {
+		minExpStr := strings.TrimSpace(query.Get("minExperience"))
+		maxExpStr := strings.TrimSpace(query.Get("maxExperience"))
+		rangeFilter := bson.M{}
+
+		if minExpStr != "" {
+			if minExp, err := strconv.Atoi(minExpStr); err == nil {
+				rangeFilter["$gte"] = minExp
+			}
 		}
-
-		_, err := col.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": updateMap})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if maxExpStr != "" {
+			if maxExp, err := strconv.Atoi(maxExpStr); err == nil {
+				rangeFilter["$lte"] = maxExp
+			}
 		}
-		fmt.Fprintf(w, "Doctor updated successfully")
-
-	case http.MethodDelete:
-		_, err := col.DeleteOne(context.TODO(), bson.M{"_id": objID})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if len(rangeFilter) > 0 {
+			filter["experience_years"] = rangeFilter
 		}
-		fmt.Fprintf(w, "Doctor deleted successfully")
-
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
-}
 
-// Допоміжна функція для JSON-відповіді
-func writeJSONDoctor(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	return filter
 }
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hospital-api/events"
+	"hospital-api/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// Events - шина змін, яку наповнює events.Watch у main
+var Events = events.NewBus()
+
+// EventResources - колекції, зміни яких ідуть у /events
+var EventResources = []events.Resource{
+	events.Model[models.Appointment]("appointments"),
+	events.Model[models.Medicine]("medications"),
+	events.Model[models.Admission]("admissions"),
+}
+
+const (
+	eventBuffer    = 64
+	eventHeartbeat = 25 * time.Second
+)
+
+var eventUpgrader = websocket.Upgrader{
+	// Доступ перевіряє JWT, тож Origin не обмежуємо
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Потік змін: SSE за замовчуванням, WebSocket - якщо клієнт просить Upgrade.
+// Браузерні EventSource і WebSocket не вміють задавати заголовки,
+// тому токен можна передати й у ?access_token=.
+func EventRoutes(router *Router) {
+	stream := router.Group("/events", LoggingMiddleware, TokenFromQuery, JWT("reader", "admin")).Secured(SecurityBearer)
+	stream.HandleFunc(http.MethodGet, "", streamEvents).
+		Describe("Stream created, updated and deleted events over SSE or WebSocket").
+		Query(eventParams...).Returns(events.Event{}, "text/event-stream")
+}
+
+var eventParams = []Param{
+	{Name: "resource", Description: "Comma-separated resources: appointments, medications, admissions"},
+	{Name: "id", Description: "Comma-separated document ObjectIDs"},
+	{Name: "access_token", Description: "JWT for clients that cannot send the Authorization header"},
+}
+
+// TokenFromQuery переносить ?access_token= у заголовок Authorization,
+// якщо його ще немає. Ставиться перед JWT.
+func TokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func streamEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := events.ParseFilter(query.Get("resource"), query.Get("id"))
+	for _, resource := range filter.Resources {
+		if !knownEventResource(resource) {
+			httpError(w, r, http.StatusBadRequest, "events.unknown_resource", resource)
+			return
+		}
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		streamWebSocket(w, r, filter)
+		return
+	}
+	streamSSE(w, r, filter)
+}
+
+func knownEventResource(name string) bool {
+	for _, r := range EventResources {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func streamSSE(w http.ResponseWriter, r *http.Request, filter events.Filter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httpError(w, r, http.StatusInternalServerError, "events.streaming_unsupported")
+		return
+	}
+	sub := Events.Subscribe(filter, eventBuffer)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event, ok := <-sub.C:
+			if !ok {
+				// Клієнт не встигав читати - EventSource перепідключиться сам
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+func streamWebSocket(w http.ResponseWriter, r *http.Request, filter events.Filter) {
+	conn, err := eventUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrader уже відповів клієнту
+		return
+	}
+	defer conn.Close()
+
+	sub := Events.Subscribe(filter, eventBuffer)
+	defer sub.Close()
+
+	// Клієнт нічого не надсилає; читаємо лише, щоб помітити закриття
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				return
+			}
+		case event, ok := <-sub.C:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber too slow"),
+					time.Now().Add(time.Second))
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// expansion описує пов'язаний документ, який можна вбудувати через ?expand=
+type expansion struct {
+	from         string
+	localField   string
+	foreignField string
+	decode       func(bson.RawValue) (interface{}, error)
+}
+
+// Дозволені розгортання для кожної колекції. Розгортання в колекції з
+// apiKeyCollections вимагає X-API-KEY, як і їхні власні маршрути, тож
+// JWT на /staff чи /appointments не відкриває лікарень і лікарів.
+var expansions = map[string]map[string]expansion{
+	"hospitals": {
+		"departments": {from: "departments", localField: "_id", foreignField: "hospital_id", decode: decodeMany[models.Department]},
+	},
+	"departments": {
+		"hospital": {from: "hospitals", localField: "hospital_id", foreignField: "_id", decode: decodeOne[models.Hospital]},
+		"doctors":  {from: "doctors", localField: "_id", foreignField: "department", decode: decodeMany[models.Doctor]},
+	},
+	"doctors": {
+		"department": {from: "departments", localField: "department", foreignField: "_id", decode: decodeOne[models.Department]},
+	},
+	"staff": {
+		"hospital":   {from: "hospitals", localField: "hospital_id", foreignField: "_id", decode: decodeOne[models.Hospital]},
+		"department": {from: "departments", localField: "department_id", foreignField: "_id", decode: decodeOne[models.Department]},
+	},
+	"appointments": {
+		"doctor": {from: "doctors", localField: "doctorId", foreignField: "_id", decode: decodeOne[models.Doctor]},
+	},
+}
+
+// apiKeyCollections - колекції, які REST віддає лише з X-API-KEY
+var apiKeyCollections = map[string]bool{"hospitals": true, "departments": true, "doctors": true}
+
+func decodeOne[T any](v bson.RawValue) (interface{}, error) {
+	var items []T
+	if err := v.Unmarshal(&items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return items[0], nil
+}
+
+func decodeMany[T any](v bson.RawValue) (interface{}, error) {
+	items := []T{}
+	if err := v.Unmarshal(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// parseExpand перевіряє список "a,b" для колекції
+func parseExpand(collection, expand string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(expand, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := expansions[collection][name]; !ok {
+			return nil, fmt.Errorf("cannot expand %q on %s", name, collection)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// findExpanded виконує $match + $lookup і повертає документи у json-формі
+// моделі з полем "expanded", де лежать вбудовані документи
+func findExpanded[T any](ctx context.Context, collection string, filter bson.M, names []string) ([]map[string]interface{}, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	for _, name := range names {
+		e := expansions[collection][name]
+		pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: e.from},
+			{Key: "localField", Value: e.localField},
+			{Key: "foreignField", Value: e.foreignField},
+			{Key: "pipeline", Value: bson.A{bson.M{"$match": notDeleted()}}},
+			{Key: "as", Value: "_expand_" + name},
+		}}})
+	}
+
+	cursor, err := db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []map[string]interface{}{}
+	for cursor.Next(ctx) {
+		var item T
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+
+		expanded := map[string]interface{}{}
+		for _, name := range names {
+			value, err := expansions[collection][name].decode(cursor.Current.Lookup("_expand_" + name))
+			if err != nil {
+				return nil, err
+			}
+			expanded[name] = value
+		}
+		doc["expanded"] = expanded
+		docs = append(docs, doc)
+	}
+	return docs, cursor.Err()
+}
+
+// respondExpanded відповідає на GET з ?expand=: списком або, якщо single,
+// одним документом (404, якщо його немає)
+func respondExpanded[T any](w http.ResponseWriter, r *http.Request, collection string, filter bson.M, expand string, single bool) {
+	names, err := parseExpand(collection, expand)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, name := range names {
+		if apiKeyCollections[expansions[collection][name].from] && !hasAPIKey(r) {
+			httpError(w, r, http.StatusUnauthorized, "expand.api_key_required", name)
+			return
+		}
+	}
+
+	docs, err := findExpanded[T](context.TODO(), collection, filter, names)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !single {
+		writeResponse(w, r, http.StatusOK, docs)
+		return
+	}
+	if len(docs) == 0 {
+		httpError(w, r, http.StatusNotFound, "request.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, docs[0])
+}
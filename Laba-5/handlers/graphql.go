@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"hospital-api/i18n"
+	"hospital-api/models"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GraphQL: граф лікарня → відділення → лікарі → записи одним запитом.
+// Типи будуються з моделей рефлексією (як схеми OpenAPI), аргументи
+// списків - з тих самих Param, що й query-параметри REST, а зв'язки
+// догружаються пакетами через loader.
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+var graphqlParams = []Param{
+	{Name: "query", Description: "GraphQL query document (mutations require POST)"},
+	{Name: "operationName", Description: "Operation to run if the document has several"},
+	{Name: "variables", Description: "JSON object with variable values"},
+}
+
+// Доступ до /graphql - JWT reader або admin; окремі поля (позначки
+// видалення, includeDeleted, бронювання) - лише admin, а лікарні,
+// відділення й лікарі - ще й з X-API-KEY, як у REST і gRPC
+func GraphQLRoutes(router *Router) {
+	schema := newGraphQLSchema()
+	serve := func(w http.ResponseWriter, r *http.Request) { serveGraphQL(w, r, schema) }
+
+	gql := router.Group("/graphql", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	gql.HandleFunc(http.MethodPost, "", serve).
+		Describe("Run a GraphQL query or mutation").Accepts(graphqlRequest{}).Returns(graphql.Result{})
+	gql.HandleFunc(http.MethodGet, "", serve).
+		Describe("Run a GraphQL query").Query(graphqlParams...).Returns(graphql.Result{})
+}
+
+func serveGraphQL(w http.ResponseWriter, r *http.Request, schema graphql.Schema) {
+	var req graphqlRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				httpError(w, r, http.StatusBadRequest, "request.invalid")
+				return
+			}
+		}
+		// GET не змінює даних, тож мутації лише через POST
+		if isMutation(req.Query, req.OperationName) {
+			w.Header().Set("Allow", http.MethodPost)
+			httpError(w, r, http.StatusMethodNotAllowed, "graphql.mutation_via_get")
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.invalid")
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		httpError(w, r, http.StatusBadRequest, "graphql.query_required")
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        withGraphQLLoaders(withGraphQLAPIKey(r.Context(), hasAPIKey(r))),
+	})
+	// Помилки полів і запиту - у result.Errors, статус лишається 200
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+// isMutation - чи вибрана операція документа є мутацією; невалідний
+// документ пропускаємо, його помилки поверне graphql.Do
+func isMutation(query, operationName string) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return op.Operation == ast.OperationTypeMutation
+		}
+	}
+	return false
+}
+
+// gqlError - помилка поля мовою запиту
+func gqlError(ctx context.Context, key string, args ...interface{}) error {
+	return errors.New(i18n.T(i18n.Lang(ctx), key, args...))
+}
+
+// authorize пропускає до resolve лише користувачів з однією з ролей.
+// Іншим поле повертає null з помилкою, решта відповіді лишається.
+func authorize(resolve graphql.FieldResolveFn, roles ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if claims := claimsFrom(p.Context); claims != nil {
+			for _, role := range roles {
+				if claims.Role == role {
+					return resolve(p)
+				}
+			}
+		}
+		return nil, gqlError(p.Context, "auth.forbidden")
+	}
+}
+
+type graphqlAPIKeyKey struct{}
+
+func withGraphQLAPIKey(ctx context.Context, ok bool) context.Context {
+	return context.WithValue(ctx, graphqlAPIKeyKey{}, ok)
+}
+
+// requireAPIKey пропускає до resolve лише запити з дійсним X-API-KEY:
+// REST і gRPC віддають лікарні, відділення й лікарів тільки з ним
+func requireAPIKey(field *graphql.Field) *graphql.Field {
+	resolve := field.Resolve
+	field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		if ok, _ := p.Context.Value(graphqlAPIKeyKey{}).(bool); !ok {
+			return nil, gqlError(p.Context, "graphql.api_key_required", p.Info.FieldName)
+		}
+		return resolve(p)
+	}
+	return field
+}
+
+// adminFields - поля моделей, які бачить лише admin
+var adminFields = map[string]bool{"deletedAt": true, "deletedBy": true}
+
+var (
+	gqlObjectIDType = reflect.TypeOf(primitive.ObjectID{})
+	gqlTimeType     = reflect.TypeOf(time.Time{})
+)
+
+// modelFields - поля GraphQL-типу з полів моделі за json-тегами.
+// ObjectID стає ID, час - DateTime; поля з omitempty можуть бути null.
+func modelFields(t reflect.Type, index []int, fields graphql.Fields) graphql.Fields {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		path := append(append([]int{}, index...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			modelFields(f.Type, path, fields)
+			continue
+		}
+		tag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		var typ graphql.Output
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft == gqlObjectIDType:
+			typ = graphql.ID
+		case ft == gqlTimeType:
+			typ = graphql.DateTime
+		case ft.Kind() == reflect.String:
+			typ = graphql.String
+		case ft.Kind() == reflect.Int:
+			typ = graphql.Int
+		case ft.Kind() == reflect.Float64:
+			typ = graphql.Float
+		case ft.Kind() == reflect.Bool:
+			typ = graphql.Boolean
+		default:
+			continue
+		}
+		nullable := name != "id" && (ft == gqlObjectIDType || f.Type.Kind() == reflect.Pointer || strings.Contains(opts, "omitempty"))
+		if !nullable {
+			typ = graphql.NewNonNull(typ)
+		}
+
+		resolve := modelValue(path, nullable)
+		if adminFields[name] {
+			resolve = authorize(resolve, "admin")
+		}
+		fields[name] = &graphql.Field{Type: typ, Resolve: resolve}
+	}
+	return fields
+}
+
+// modelValue читає поле моделі за шляхом індексів; у полях, що можуть
+// бути null, нульові значення (ObjectID, nil, порожній рядок) стають null
+func modelValue(path []int, nullable bool) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		v := reflect.ValueOf(p.Source).FieldByIndex(path)
+		if nullable && v.IsZero() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		if id, ok := v.Interface().(primitive.ObjectID); ok {
+			return id.Hex(), nil
+		}
+		return v.Interface(), nil
+	}
+}
+
+// modelObject - GraphQL-тип моделі; relations додають зв'язки між типами
+// (і замінюють однойменні поля-ObjectID) й викликаються ліниво, бо типи
+// посилаються один на одного
+func modelObject(name string, model interface{}, relations func() graphql.Fields) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := modelFields(reflect.TypeOf(model), nil, graphql.Fields{})
+			for key, field := range relations() {
+				fields[key] = field
+			}
+			return fields
+		}),
+	})
+}
+
+// paramArgs - аргументи GraphQL з опису query-параметрів REST
+func paramArgs(params ...Param) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{}
+	for _, p := range params {
+		var typ graphql.Input = graphql.String
+		switch p.Type {
+		case "integer":
+			typ = graphql.Int
+		case "boolean":
+			typ = graphql.Boolean
+		}
+		args[p.Name] = &graphql.ArgumentConfig{Type: typ, Description: p.Description}
+	}
+	return args
+}
+
+// argValues - аргументи як query-параметри для hospitalFilter і подібних
+func argValues(args map[string]interface{}) url.Values {
+	values := url.Values{}
+	for name, value := range args {
+		values.Set(name, fmt.Sprint(value))
+	}
+	return values
+}
+
+// gqlVisible - visible для GraphQL: includeDeleted лише для admin
+func gqlVisible(ctx context.Context, filter bson.M, args map[string]interface{}) (bson.M, error) {
+	if include, _ := args["includeDeleted"].(bool); !include {
+		return alive(filter), nil
+	}
+	if claims := claimsFrom(ctx); claims == nil || claims.Role != "admin" {
+		return nil, gqlError(ctx, "request.include_deleted_admin")
+	}
+	return filter, nil
+}
+
+// gqlByID - фільтр за аргументом id
+func gqlByID(p graphql.ResolveParams) (bson.M, error) {
+	id, _ := p.Args["id"].(string)
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, gqlError(p.Context, "request.invalid_id")
+	}
+	return gqlVisible(p.Context, bson.M{"_id": objID}, p.Args)
+}
+
+// listField - кореневий список моделі з фільтрами REST
+func listField[T any](typ *graphql.Object, params []Param, filter func(url.Values) bson.M, find func(context.Context, bson.M) ([]T, error)) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(typ))),
+		Args: paramArgs(append(append([]Param{}, params...), includeDeletedParam)...),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			query, err := gqlVisible(p.Context, filter(argValues(p.Args)), p.Args)
+			if err != nil {
+				return nil, err
+			}
+			return find(p.Context, query)
+		},
+	}
+}
+
+// oneField - документ моделі за id; неіснуючий - null
+func oneField[T any](typ *graphql.Object, find func(context.Context, bson.M) ([]T, error)) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Args: graphql.FieldConfigArgument{
+			"id":             {Type: graphql.NewNonNull(graphql.ID)},
+			"includeDeleted": {Type: graphql.Boolean, Description: includeDeletedParam.Description},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			filter, err := gqlByID(p)
+			if err != nil {
+				return nil, err
+			}
+			docs, err := find(p.Context, filter)
+			if err != nil || len(docs) == 0 {
+				return nil, err
+			}
+			return docs[0], nil
+		},
+	}
+}
+
+// related - зв'язок через loader: key(джерело) → документи
+func related[S, T any](typ graphql.Output, pick func(*graphqlLoaders) *loader[T], key func(S) primitive.ObjectID, single bool) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id := key(p.Source.(S))
+			if id.IsZero() {
+				return nil, nil
+			}
+			thunk := pick(loadersFrom(p.Context)).load(p.Context, id)
+			return func() (interface{}, error) {
+				docs, err := thunk()
+				if err != nil {
+					return nil, err
+				}
+				if !single {
+					return docs, nil
+				}
+				if len(docs) == 0 {
+					return nil, nil
+				}
+				return docs[0], nil
+			}, nil
+		},
+	}
+}
+
+func newGraphQLSchema() graphql.Schema {
+	var hospital, department, doctor, appointment *graphql.Object
+	listOf := func(t *graphql.Object) graphql.Output {
+		return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+	}
+
+	hospital = modelObject("Hospital", models.Hospital{}, func() graphql.Fields {
+		return graphql.Fields{
+			"departments": requireAPIKey(related(listOf(department), func(l *graphqlLoaders) *loader[models.Department] { return l.hospitalDepartments },
+				func(h models.Hospital) primitive.ObjectID { return h.ID }, false)),
+		}
+	})
+	department = modelObject("Department", models.Department{}, func() graphql.Fields {
+		return graphql.Fields{
+			"hospital": requireAPIKey(related(hospital, func(l *graphqlLoaders) *loader[models.Hospital] { return l.hospital },
+				func(d models.Department) primitive.ObjectID { return d.HospitalID }, true)),
+			"doctors": requireAPIKey(related(listOf(doctor), func(l *graphqlLoaders) *loader[models.Doctor] { return l.departmentDoctors },
+				func(d models.Department) primitive.ObjectID { return d.ID }, false)),
+		}
+	})
+	doctor = modelObject("Doctor", models.Doctor{}, func() graphql.Fields {
+		return graphql.Fields{
+			"department": requireAPIKey(related(department, func(l *graphqlLoaders) *loader[models.Department] { return l.department },
+				func(d models.Doctor) primitive.ObjectID { return d.Department }, true)),
+			"appointments": related(listOf(appointment), func(l *graphqlLoaders) *loader[models.Appointment] { return l.doctorAppointments },
+				func(d models.Doctor) primitive.ObjectID { return d.ID }, false),
+		}
+	})
+	appointment = modelObject("Appointment", models.Appointment{}, func() graphql.Fields {
+		return graphql.Fields{
+			"doctor": requireAPIKey(related(doctor, func(l *graphqlLoaders) *loader[models.Doctor] { return l.doctor },
+				func(a models.Appointment) primitive.ObjectID { return a.DoctorID }, true)),
+		}
+	})
+
+	src := GraphQLData
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"hospitals":    requireAPIKey(listField(hospital, hospitalParams, hospitalFilter, src.Hospitals)),
+			"hospital":     requireAPIKey(oneField(hospital, src.Hospitals)),
+			"departments":  requireAPIKey(listField(department, departmentParams, departmentFilter, src.Departments)),
+			"department":   requireAPIKey(oneField(department, src.Departments)),
+			"doctors":      requireAPIKey(listField(doctor, doctorParams, doctorFilter, src.Doctors)),
+			"doctor":       requireAPIKey(oneField(doctor, src.Doctors)),
+			"appointments": listField(appointment, appointmentParams, appointmentFilter, src.Appointments),
+			"appointment":  oneField(appointment, src.Appointments),
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"bookAppointment": &graphql.Field{
+				Type:        graphql.NewNonNull(appointment),
+				Description: "Book an appointment; fails if the doctor already has one within the slot",
+				Args: graphql.FieldConfigArgument{
+					"patientId": {Type: graphql.NewNonNull(graphql.ID)},
+					"doctorId":  {Type: graphql.NewNonNull(graphql.ID)},
+					"date":      {Type: graphql.DateTime, Description: "Defaults to now"},
+				},
+				Resolve: authorize(resolveBookAppointment, "admin"),
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+	if err != nil {
+		// Схема будується з моделей у коді - помилка тут означає баг
+		panic(err)
+	}
+	return schema
+}
+
+// resolveBookAppointment - як POST /appointments: та сама валідація і перевірка слота
+func resolveBookAppointment(p graphql.ResolveParams) (interface{}, error) {
+	var appointment models.Appointment
+	// Невалідний id лишає нульовий ObjectID - його відхилить Validate
+	patient, _ := p.Args["patientId"].(string)
+	doctor, _ := p.Args["doctorId"].(string)
+	appointment.PatientID, _ = primitive.ObjectIDFromHex(patient)
+	appointment.DoctorID, _ = primitive.ObjectIDFromHex(doctor)
+	if date, ok := p.Args["date"].(time.Time); ok {
+		appointment.Date = date
+	} else {
+		appointment.Date = time.Now()
+	}
+	if err := appointment.Validate(); err != nil {
+		var verrs models.ValidationErrors
+		if errors.As(err, &verrs) {
+			err = verrs.Localize(i18n.Lang(p.Context))
+		}
+		return nil, err
+	}
+
+	err := bookAppointment(p.Context, &appointment)
+	if key, ok := bookingConflict(err); ok {
+		return nil, gqlError(p.Context, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
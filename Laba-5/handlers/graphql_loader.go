@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"hospital-api/db"
+	"hospital-api/models"
+	"hospital-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// loader збирає ключі, які просять resolvers одного рівня запиту, і
+// дістає їх одним запитом до Mongo, коли graphql-go викликає перший thunk.
+// graphql-go розгортає thunks у ширину, тож для hospitals { departments }
+// усі лікарні встигають попросити свої відділення до першого запиту.
+type loader[T any] struct {
+	fetch func(ctx context.Context, keys []primitive.ObjectID) (map[primitive.ObjectID][]T, error)
+
+	mu      sync.Mutex
+	pending []primitive.ObjectID
+	queued  map[primitive.ObjectID]bool
+	done    map[primitive.ObjectID][]T
+	errs    map[primitive.ObjectID]error
+}
+
+func newLoader[T any](fetch func(context.Context, []primitive.ObjectID) (map[primitive.ObjectID][]T, error)) *loader[T] {
+	return &loader[T]{
+		fetch:  fetch,
+		queued: map[primitive.ObjectID]bool{},
+		done:   map[primitive.ObjectID][]T{},
+		errs:   map[primitive.ObjectID]error{},
+	}
+}
+
+// load ставить key у чергу й повертає thunk з документами для нього
+func (l *loader[T]) load(ctx context.Context, key primitive.ObjectID) func() ([]T, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() ([]T, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.done[key]; !ok && len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+			found, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				l.done[k] = found[k]
+				if err != nil {
+					l.errs[k] = err
+				}
+			}
+		}
+		return l.done[key], l.errs[key]
+	}
+}
+
+// batchBy - fetch для loader: живі документи, у яких field - один із ключів,
+// згруповані за key(документ)
+func batchBy[T any](find func(context.Context, bson.M) ([]T, error), field string, key func(T) primitive.ObjectID) func(context.Context, []primitive.ObjectID) (map[primitive.ObjectID][]T, error) {
+	return func(ctx context.Context, keys []primitive.ObjectID) (map[primitive.ObjectID][]T, error) {
+		docs, err := find(ctx, alive(bson.M{field: bson.M{"$in": keys}}))
+		if err != nil {
+			return nil, err
+		}
+		grouped := map[primitive.ObjectID][]T{}
+		for _, doc := range docs {
+			grouped[key(doc)] = append(grouped[key(doc)], doc)
+		}
+		return grouped, nil
+	}
+}
+
+// findIn - пошук у колекції без кешу (для моделей без репозиторію)
+func findIn[T any](collection string) func(context.Context, bson.M) ([]T, error) {
+	return func(ctx context.Context, filter bson.M) ([]T, error) {
+		cursor, err := db.Collection(collection).Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		var docs []T
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	}
+}
+
+// cached - пошук через репозиторій з кешем читань
+func cached[T any](repo *repository.Repository[T]) func(context.Context, bson.M) ([]T, error) {
+	return func(ctx context.Context, filter bson.M) ([]T, error) {
+		docs, _, err := repo.Find(ctx, filter)
+		return docs, err
+	}
+}
+
+// GraphQLSource - звідки GraphQL бере документи: кореневі поля читають
+// GraphQLData під час побудови схеми (NewAPI), loaders - на кожен запит.
+// Тести підставляють свої функції, щоб рахувати запити до бази.
+type GraphQLSource struct {
+	Hospitals    func(context.Context, bson.M) ([]models.Hospital, error)
+	Departments  func(context.Context, bson.M) ([]models.Department, error)
+	Doctors      func(context.Context, bson.M) ([]models.Doctor, error)
+	Appointments func(context.Context, bson.M) ([]models.Appointment, error)
+}
+
+var GraphQLData = GraphQLSource{
+	Hospitals:    cached[models.Hospital](hospitalsRepo),
+	Departments:  cached[models.Department](departmentsRepo),
+	Doctors:      cached[models.Doctor](doctorsRepo),
+	Appointments: findIn[models.Appointment]("appointments"),
+}
+
+// graphqlLoaders - loaders одного GraphQL-запиту
+type graphqlLoaders struct {
+	hospital            *loader[models.Hospital]
+	department          *loader[models.Department]
+	doctor              *loader[models.Doctor]
+	hospitalDepartments *loader[models.Department]
+	departmentDoctors   *loader[models.Doctor]
+	doctorAppointments  *loader[models.Appointment]
+}
+
+func newGraphQLLoaders() *graphqlLoaders {
+	hospitals, departments, doctors, appointments := GraphQLData.Hospitals, GraphQLData.Departments, GraphQLData.Doctors, GraphQLData.Appointments
+
+	return &graphqlLoaders{
+		hospital:   newLoader(batchBy(hospitals, "_id", func(h models.Hospital) primitive.ObjectID { return h.ID })),
+		department: newLoader(batchBy(departments, "_id", func(d models.Department) primitive.ObjectID { return d.ID })),
+		doctor:     newLoader(batchBy(doctors, "_id", func(d models.Doctor) primitive.ObjectID { return d.ID })),
+		hospitalDepartments: newLoader(batchBy(departments, "hospital_id",
+			func(d models.Department) primitive.ObjectID { return d.HospitalID })),
+		departmentDoctors: newLoader(batchBy(doctors, "department",
+			func(d models.Doctor) primitive.ObjectID { return d.Department })),
+		doctorAppointments: newLoader(batchBy(appointments, "doctorId",
+			func(a models.Appointment) primitive.ObjectID { return a.DoctorID })),
+	}
+}
+
+type graphqlLoadersKey struct{}
+
+func withGraphQLLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, graphqlLoadersKey{}, newGraphQLLoaders())
+}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+}
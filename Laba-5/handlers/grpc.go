@@ -0,0 +1,570 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/events"
+	"hospital-api/i18n"
+	"hospital-api/models"
+	pb "hospital-api/proto/hospitalpb"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// gRPC API (proto/hospital.proto) поверх тих самих репозиторіїв, фільтрів
+// і м'якого видалення, що й REST. Доступ - як у відповідних груп маршрутів:
+// ключ x-api-key, JWT у authorization: Bearer ... або без авторизації.
+
+// grpcAccess - правило доступу до методу або цілого сервісу
+type grpcAccess struct {
+	apiKey bool
+	roles  []string
+}
+
+// grpcRules - за повною назвою методу або префіксом сервісу "/пакет.Сервіс/"
+var grpcRules = map[string]grpcAccess{
+	"/hospital.v1.HospitalService/":                     {apiKey: true},
+	"/hospital.v1.DepartmentService/":                   {apiKey: true},
+	"/hospital.v1.DoctorService/":                       {apiKey: true},
+	"/hospital.v1.StaffService/":                        {roles: []string{"reader", "admin"}},
+	"/hospital.v1.MedicationService/":                   {},
+	"/hospital.v1.AppointmentService/":                  {roles: []string{"reader", "admin"}},
+	"/hospital.v1.AppointmentService/CreateAppointment": {roles: []string{"admin"}},
+	"/hospital.v1.AppointmentService/DeleteAppointment": {roles: []string{"admin"}},
+}
+
+func grpcRule(method string) (grpcAccess, bool) {
+	if rule, ok := grpcRules[method]; ok {
+		return rule, true
+	}
+	service := method[:strings.LastIndex(method, "/")+1]
+	rule, ok := grpcRules[service]
+	return rule, ok
+}
+
+// NewGRPCServer - gRPC-сервер з усіма сервісами hospital.v1
+func NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(grpcUnaryAuth),
+		grpc.ChainStreamInterceptor(grpcStreamAuth),
+	)
+	server := grpc.NewServer(opts...)
+	api := &grpcAPI{}
+	pb.RegisterHospitalServiceServer(server, api)
+	pb.RegisterDepartmentServiceServer(server, api)
+	pb.RegisterDoctorServiceServer(server, api)
+	pb.RegisterStaffServiceServer(server, api)
+	pb.RegisterMedicationServiceServer(server, api)
+	pb.RegisterAppointmentServiceServer(server, api)
+	return server
+}
+
+func grpcUnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := grpcAuthorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func grpcStreamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := grpcAuthorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorizedStream підміняє контекст потоку на контекст з мовою й claims
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context { return s.ctx }
+
+// grpcAuthorize - LanguageMiddleware, APIKeyMiddleware і JWT для gRPC:
+// мова з accept-language, claims з необов'язкового Bearer-токена (для
+// include_deleted) і перевірка правила методу
+func grpcAuthorize(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = i18n.WithLang(ctx, i18n.Match(firstMD(md, "accept-language")))
+
+	bearer, hasBearer := strings.CutPrefix(firstMD(md, "authorization"), "Bearer ")
+	var claims *Claims
+	if hasBearer {
+		if c, ok := parseToken(bearer); ok {
+			claims = c
+			ctx = context.WithValue(ctx, "claims", claims)
+		}
+	}
+
+	rule, ok := grpcRule(method)
+	if !ok {
+		return nil, grpcError(ctx, codes.Unimplemented, "request.not_found")
+	}
+	if rule.apiKey && firstMD(md, "x-api-key") != apiKey {
+		return nil, grpcError(ctx, codes.Unauthenticated, "auth.unauthorized")
+	}
+	if len(rule.roles) == 0 {
+		return ctx, nil
+	}
+	if !hasBearer {
+		return nil, grpcError(ctx, codes.Unauthenticated, "auth.invalid_token")
+	}
+	if claims == nil {
+		return nil, grpcError(ctx, codes.Unauthenticated, "auth.unauthorized")
+	}
+	for _, role := range rule.roles {
+		if claims.Role == role {
+			return ctx, nil
+		}
+	}
+	return nil, grpcError(ctx, codes.PermissionDenied, "auth.forbidden")
+}
+
+func firstMD(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// grpcError - status з повідомленням мовою запиту
+func grpcError(ctx context.Context, code codes.Code, key string, args ...interface{}) error {
+	return status.Error(code, i18n.T(i18n.Lang(ctx), key, args...))
+}
+
+// grpcValidationError - InvalidArgument з помилками валідації мовою запиту
+func grpcValidationError(ctx context.Context, err error) error {
+	var verrs models.ValidationErrors
+	if errors.As(err, &verrs) {
+		err = verrs.Localize(i18n.Lang(ctx))
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// grpcVisible - visible для gRPC: include_deleted лише для admin
+func grpcVisible(ctx context.Context, filter bson.M, includeDeleted bool) (bson.M, error) {
+	if !includeDeleted {
+		return alive(filter), nil
+	}
+	if claims := claimsFrom(ctx); claims == nil || claims.Role != "admin" {
+		return nil, grpcError(ctx, codes.PermissionDenied, "request.include_deleted_admin")
+	}
+	return filter, nil
+}
+
+// grpcByID - фільтр документа за id з GetRequest
+func grpcByID(ctx context.Context, req *pb.GetRequest) (bson.M, error) {
+	objID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, grpcError(ctx, codes.InvalidArgument, "request.invalid_id")
+	}
+	return grpcVisible(ctx, bson.M{"_id": objID}, req.GetIncludeDeleted())
+}
+
+// grpcFind - документи колекції без кешу, по одному в send
+func grpcFind[T, P any](ctx context.Context, collection string, filter bson.M, convert func(T) *P, send func(*P) error) error {
+	cursor, err := db.Collection(collection).Find(ctx, filter)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc T
+		if err := cursor.Decode(&doc); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := send(convert(doc)); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// grpcSendAll - список з репозиторію (кеш) у потік
+func grpcSendAll[T, P any](docs []T, convert func(T) *P, send func(*P) error) error {
+	for _, doc := range docs {
+		if err := send(convert(doc)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grpcQuery - фільтри запиту як query-параметри для hospitalFilter і подібних
+type grpcQuery url.Values
+
+func (q grpcQuery) str(name, value string) {
+	if value != "" {
+		url.Values(q).Set(name, value)
+	}
+}
+
+func (q grpcQuery) int(name string, value *int32) {
+	if value != nil {
+		url.Values(q).Set(name, strconv.Itoa(int(*value)))
+	}
+}
+
+type grpcAPI struct {
+	pb.UnimplementedHospitalServiceServer
+	pb.UnimplementedDepartmentServiceServer
+	pb.UnimplementedDoctorServiceServer
+	pb.UnimplementedStaffServiceServer
+	pb.UnimplementedMedicationServiceServer
+	pb.UnimplementedAppointmentServiceServer
+}
+
+// ------------------ Лікарні ------------------
+func (grpcAPI) GetHospital(ctx context.Context, req *pb.GetRequest) (*pb.Hospital, error) {
+	filter, err := grpcByID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	hospital, _, err := hospitalsRepo.FindOne(ctx, filter)
+	if err != nil {
+		return nil, grpcError(ctx, codes.NotFound, "hospital.not_found")
+	}
+	return hospitalProto(hospital), nil
+}
+
+func (grpcAPI) ListHospitals(req *pb.ListHospitalsRequest, stream pb.HospitalService_ListHospitalsServer) error {
+	ctx := stream.Context()
+	query := grpcQuery{}
+	query.str("name", req.GetName())
+	query.str("city", req.GetCity())
+	query.int("beds", req.Beds)
+	query.int("minBeds", req.MinBeds)
+	query.int("maxBeds", req.MaxBeds)
+	filter, err := grpcVisible(ctx, hospitalFilter(url.Values(query)), req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	hospitals, _, err := hospitalsRepo.Find(ctx, filter)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return grpcSendAll(hospitals, hospitalProto, stream.Send)
+}
+
+// ------------------ Відділення ------------------
+func (grpcAPI) GetDepartment(ctx context.Context, req *pb.GetRequest) (*pb.Department, error) {
+	filter, err := grpcByID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	department, _, err := departmentsRepo.FindOne(ctx, filter)
+	if err != nil {
+		return nil, grpcError(ctx, codes.NotFound, "department.not_found")
+	}
+	return departmentProto(department), nil
+}
+
+func (grpcAPI) ListDepartments(req *pb.ListDepartmentsRequest, stream pb.DepartmentService_ListDepartmentsServer) error {
+	ctx := stream.Context()
+	query := grpcQuery{}
+	query.str("name", req.GetName())
+	query.str("hospitalId", req.GetHospitalId())
+	query.int("floor", req.Floor)
+	query.int("minFloor", req.MinFloor)
+	query.int("maxFloor", req.MaxFloor)
+	filter, err := grpcVisible(ctx, departmentFilter(url.Values(query)), req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	departments, _, err := departmentsRepo.Find(ctx, filter)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return grpcSendAll(departments, departmentProto, stream.Send)
+}
+
+// ------------------ Лікарі ------------------
+func (grpcAPI) GetDoctor(ctx context.Context, req *pb.GetRequest) (*pb.Doctor, error) {
+	filter, err := grpcByID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	doctor, _, err := doctorsRepo.FindOne(ctx, filter)
+	if err != nil {
+		return nil, grpcError(ctx, codes.NotFound, "doctor.not_found")
+	}
+	return doctorProto(doctor), nil
+}
+
+func (grpcAPI) ListDoctors(req *pb.ListDoctorsRequest, stream pb.DoctorService_ListDoctorsServer) error {
+	ctx := stream.Context()
+	query := grpcQuery{}
+	query.str("name", req.GetName())
+	query.str("specialty", req.GetSpecialty())
+	query.str("department", req.GetDepartmentId())
+	query.int("experience_years", req.ExperienceYears)
+	query.int("minExperience", req.MinExperience)
+	query.int("maxExperience", req.MaxExperience)
+	filter, err := grpcVisible(ctx, doctorFilter(url.Values(query)), req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+
+	doctors, _, err := doctorsRepo.Find(ctx, filter)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return grpcSendAll(doctors, doctorProto, stream.Send)
+}
+
+// ------------------ Працівники ------------------
+func (grpcAPI) GetStaffMember(ctx context.Context, req *pb.GetRequest) (*pb.StaffMember, error) {
+	filter, err := grpcByID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var staffMember models.Staff
+	if err := db.Collection("staff").FindOne(ctx, filter).Decode(&staffMember); err != nil {
+		return nil, grpcError(ctx, codes.NotFound, "staff.not_found")
+	}
+	return staffProto(staffMember), nil
+}
+
+func (grpcAPI) ListStaff(req *pb.ListStaffRequest, stream pb.StaffService_ListStaffServer) error {
+	ctx := stream.Context()
+	query := grpcQuery{}
+	query.str("name", req.GetName())
+	query.str("role", req.GetRole())
+	query.str("shift", req.GetShift())
+	query.str("hospitalId", req.GetHospitalId())
+	query.str("departmentId", req.GetDepartmentId())
+	filter, err := grpcVisible(ctx, staffFilter(url.Values(query)), req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+	return grpcFind(ctx, "staff", filter, staffProto, stream.Send)
+}
+
+// ------------------ Ліки ------------------
+func (grpcAPI) GetMedicine(ctx context.Context, req *pb.GetRequest) (*pb.Medicine, error) {
+	filter, err := grpcByID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var medicine models.Medicine
+	if err := db.Collection("medications").FindOne(ctx, filter).Decode(&medicine); err != nil {
+		return nil, grpcError(ctx, codes.NotFound, "medicine.not_found")
+	}
+	return medicineProto(medicine), nil
+}
+
+func (grpcAPI) ListMedications(req *pb.ListMedicationsRequest, stream pb.MedicationService_ListMedicationsServer) error {
+	ctx := stream.Context()
+	query := grpcQuery{}
+	query.str("name", req.GetName())
+	query.str("dosage", req.GetDosage())
+	query.str("manufacturer", req.GetManufacturer())
+	filter, err := grpcVisible(ctx, medicineFilter(url.Values(query)), req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+	return grpcFind(ctx, "medications", filter, medicineProto, stream.Send)
+}
+
+// ------------------ Записи ------------------
+func (grpcAPI) GetAppointment(ctx context.Context, req *pb.GetRequest) (*pb.Appointment, error) {
+	filter, err := grpcByID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var appointment models.Appointment
+	if err := db.Collection("appointments").FindOne(ctx, filter).Decode(&appointment); err != nil {
+		return nil, grpcError(ctx, codes.NotFound, "appointment.not_found")
+	}
+	return appointmentProto(appointment), nil
+}
+
+func (grpcAPI) ListAppointments(req *pb.ListAppointmentsRequest, stream pb.AppointmentService_ListAppointmentsServer) error {
+	ctx := stream.Context()
+	query := grpcQuery{}
+	query.str("patientId", req.GetPatientId())
+	query.str("doctorId", req.GetDoctorId())
+	query.str("date", req.GetDate())
+	query.str("status", req.GetStatus())
+	filter, err := grpcVisible(ctx, appointmentFilter(url.Values(query)), req.GetIncludeDeleted())
+	if err != nil {
+		return err
+	}
+	return grpcFind(ctx, "appointments", filter, appointmentProto, stream.Send)
+}
+
+// CreateAppointment - як POST /appointments: та сама валідація і перевірка слота
+func (grpcAPI) CreateAppointment(ctx context.Context, req *pb.CreateAppointmentRequest) (*pb.Appointment, error) {
+	var appointment models.Appointment
+	// Невалідний id лишає нульовий ObjectID - його відхилить Validate
+	appointment.PatientID, _ = primitive.ObjectIDFromHex(req.GetPatientId())
+	appointment.DoctorID, _ = primitive.ObjectIDFromHex(req.GetDoctorId())
+	if req.Date != nil {
+		appointment.Date = req.GetDate().AsTime()
+	} else {
+		appointment.Date = time.Now()
+	}
+	if err := appointment.Validate(); err != nil {
+		return nil, grpcValidationError(ctx, err)
+	}
+
+	err := bookAppointment(ctx, &appointment)
+	if key, ok := bookingConflict(err); ok {
+		return nil, grpcError(ctx, codes.AlreadyExists, key)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return appointmentProto(appointment), nil
+}
+
+// DeleteAppointment - м'яке видалення, як DELETE /appointments/{id}
+func (grpcAPI) DeleteAppointment(ctx context.Context, req *pb.DeleteAppointmentRequest) (*pb.DeleteAppointmentResponse, error) {
+	objID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, grpcError(ctx, codes.InvalidArgument, "request.invalid_id")
+	}
+	found, err := softDeleteBy(ctx, "appointments", bson.M{"_id": objID}, claimsFrom(ctx).Username)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !found {
+		return nil, grpcError(ctx, codes.NotFound, "appointment.not_found")
+	}
+	return &pb.DeleteAppointmentResponse{}, nil
+}
+
+// WatchAppointments - події записів із шини Events, як /events?resource=appointments
+func (grpcAPI) WatchAppointments(req *pb.WatchAppointmentsRequest, stream pb.AppointmentService_WatchAppointmentsServer) error {
+	ctx := stream.Context()
+	sub := Events.Subscribe(events.Filter{Resources: []string{"appointments"}, IDs: req.GetIds()}, eventBuffer)
+	defer sub.Close()
+
+	// Заголовки - сигнал клієнту, що підписка вже діє
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.C:
+			if !ok {
+				// Клієнт не встигав читати - хай перепідключиться
+				return status.Error(codes.ResourceExhausted, "event buffer overflow")
+			}
+			if err := stream.Send(appointmentEventProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ------------------ Перетворення моделей ------------------
+func hexID(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
+
+func deletionProto(d models.SoftDelete) *pb.Deletion {
+	if d.DeletedAt == nil {
+		return nil
+	}
+	return &pb.Deletion{DeletedAt: timestampProto(d.DeletedAt), DeletedBy: d.DeletedBy}
+}
+
+func hospitalProto(h models.Hospital) *pb.Hospital {
+	return &pb.Hospital{
+		Id: hexID(h.ID), Name: h.Name, Location: h.Location, Beds: int32(h.Beds),
+		Deletion: deletionProto(h.SoftDelete),
+	}
+}
+
+func departmentProto(d models.Department) *pb.Department {
+	return &pb.Department{
+		Id: hexID(d.ID), Name: d.Name, HospitalId: hexID(d.HospitalID), Floor: int32(d.Floor),
+		Deletion: deletionProto(d.SoftDelete),
+	}
+}
+
+func doctorProto(d models.Doctor) *pb.Doctor {
+	return &pb.Doctor{
+		Id: hexID(d.ID), Name: d.Name, Specialty: d.Specialty, DepartmentId: hexID(d.Department),
+		ExperienceYears: int32(d.ExperienceYears), Deletion: deletionProto(d.SoftDelete),
+	}
+}
+
+func staffProto(s models.Staff) *pb.StaffMember {
+	return &pb.StaffMember{
+		Id: hexID(s.ID), Name: s.Name, Role: s.Role, Shift: s.Shift,
+		HospitalId: hexID(s.HospitalID), DepartmentId: hexID(s.DepartmentID),
+		Deletion: deletionProto(s.SoftDelete),
+	}
+}
+
+func medicineProto(m models.Medicine) *pb.Medicine {
+	return &pb.Medicine{
+		Id: hexID(m.ID), Name: m.Name, Dosage: m.Dosage, Manufacturer: m.Manufacturer,
+		Stock: int32(m.Stock), Price: m.Price, Deletion: deletionProto(m.SoftDelete),
+	}
+}
+
+func appointmentProto(a models.Appointment) *pb.Appointment {
+	out := &pb.Appointment{
+		Id: hexID(a.ID), PatientId: hexID(a.PatientID), DoctorId: hexID(a.DoctorID),
+		Date: timestamppb.New(a.Date), Deletion: deletionProto(a.SoftDelete),
+		Status: a.State(), CheckedInAt: timestampProto(a.CheckedInAt), StartedAt: timestampProto(a.StartedAt),
+		CompletedAt: timestampProto(a.CompletedAt), CancelledAt: timestampProto(a.CancelledAt),
+		CancelReason: a.CancelReason, NoShowAt: timestampProto(a.NoShowAt),
+	}
+	if a.SeriesID != nil {
+		out.SeriesId = a.SeriesID.Hex()
+	}
+	return out
+}
+
+func timestampProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+var eventTypes = map[string]pb.AppointmentEvent_Type{
+	events.Created: pb.AppointmentEvent_CREATED,
+	events.Updated: pb.AppointmentEvent_UPDATED,
+	events.Deleted: pb.AppointmentEvent_DELETED,
+}
+
+func appointmentEventProto(e events.Event) *pb.AppointmentEvent {
+	out := &pb.AppointmentEvent{
+		Id: e.ID, Type: eventTypes[e.Type], AppointmentId: e.ResourceID, Time: timestamppb.New(e.Time),
+	}
+	if appointment, ok := e.Data.(models.Appointment); ok {
+		out.Appointment = appointmentProto(appointment)
+	}
+	return out
+}
@@ -3,10 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -17,164 +17,183 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// --- Middleware для логування ---
-func LoggingMiddlewareHospitals(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f, err := os.OpenFile("access.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			log.Println("Cannot open log file:", err)
-		} else {
-			defer f.Close()
-			log.SetOutput(f)
-			log.Printf("%s %s\n", r.Method, r.URL.Path)
-		}
-		next.ServeHTTP(w, r)
-	})
+// --- Реєстрація маршрутів ---
+func HospitalRoutes(router *Router) {
+	hospitals := router.Group("/hospitals", LoggingMiddleware, APIKeyMiddleware).Secured(SecurityAPIKey)
+
+	hospitals.HandleFunc(http.MethodGet, "", listHospitals).
+		Describe("List hospitals").Query(hospitalParams...).Query(includeDeletedParam).Query(expandParam("hospitals")).Returns([]models.Hospital{})
+	hospitals.HandleFunc(http.MethodPost, "", createHospital).
+		Describe("Create a hospital").Accepts(models.Hospital{}).Returns(models.Hospital{})
+	hospitals.HandleFunc(http.MethodGet, "/{id}", getHospital).
+		Describe("Get a hospital").Query(includeDeletedParam).Query(expandParam("hospitals")).Returns(models.Hospital{})
+	hospitals.HandleFunc(http.MethodPut, "/{id}", updateHospital).
+		Describe("Update a hospital").Accepts(models.Hospital{})
+	hospitals.HandleFunc(http.MethodDelete, "/{id}", deleteHospital).
+		Describe("Soft-delete a hospital with its departments, doctors, wards, beds, staff and shifts")
+
+	restoreRoute(router, "/hospitals", "Hospital", restoreHospital)
+
+	hospitals.Handle(http.MethodPost, "/import", importHandler[models.Hospital]("hospitals")).
+		Describe("Import hospitals from CSV or NDJSON").Query(importParams...).
+		Accepts([]models.Hospital{}, "text/csv", "application/x-ndjson").Returns(importResult{})
+	hospitals.Handle(http.MethodGet, "/export", exportHandler[models.Hospital]("hospitals", hospitalFilter)).
+		Describe("Export hospitals as CSV or NDJSON").Query(hospitalParams...).Query(exportParams...).
+		Returns([]models.Hospital{}, "text/csv", "application/x-ndjson")
 }
 
-// --- Middleware для простої авторизації ---
-func AuthMiddlewareHospitals(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		const apiKey = "my-secret-key"
-		key := r.Header.Get("X-API-KEY")
-		if key != apiKey {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
+func listHospitals(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, hospitalFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Hospital](w, r, "hospitals", filter, expand, false)
+		return
+	}
 
-// --- Реєстрація маршрутів ---
-func HospitalRoutes() {
-	http.Handle("/hospitals", LoggingMiddlewareHospitals(AuthMiddlewareHospitals(http.HandlerFunc(hospitalsHandler))))
-	http.Handle("/hospitals/", LoggingMiddlewareHospitals(AuthMiddlewareHospitals(http.HandlerFunc(hospitalHandler))))
+	// --- Отримання з кешу або бази ---
+	hospitals, meta, err := hospitalsRepo.Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if cacheHeaders(w, r, meta) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, hospitals)
 }
 
-func hospitalsHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospital_db").Collection("hospitals")
+func createHospital(w http.ResponseWriter, r *http.Request) {
+	var hospital models.Hospital
+	if err := json.NewDecoder(r.Body).Decode(&hospital); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hospital.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
 
-	switch r.Method {
-	case http.MethodGet:
-		// --- Фільтрація ---
-		filter := bson.M{}
-		query := r.URL.Query()
+	res, err := db.Collection("hospitals").InsertOne(context.TODO(), hospital)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hospital.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusOK, hospital)
+}
 
-		// Фільтр за назвою
-		if name := strings.TrimSpace(query.Get("name")); name != "" {
-			filter["name"] = bson.M{"$regex": name, "$options": "i"}
-		}
+func getHospital(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Hospital](w, r, "hospitals", filter, expand, true)
+		return
+	}
 
-		// Фільтр за містом
-		if city := strings.TrimSpace(query.Get("city")); city != "" {
-			filter["city"] = bson.M{"$regex": city, "$options": "i"}
-		}
+	hospital, meta, err := hospitalsRepo.FindOne(context.TODO(), filter)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "hospital.not_found")
+		return
+	}
+	if cacheHeaders(w, r, meta) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, hospital)
+}
 
-		// Фільтр за кількістю ліжок (точно або діапазон)
-		if bedsStr := strings.TrimSpace(query.Get("beds")); bedsStr != "" {
-			if bedsInt, err := strconv.Atoi(bedsStr); err == nil {
-				filter["beds"] = bedsInt
-			}
-		} else {
-			minBedsStr := query.Get("minBeds")
-			maxBedsStr := query.Get("maxBeds")
-			rangeFilter := bson.M{}
-
-			if minBedsStr != "" {
-				if minBeds, err := strconv.Atoi(minBedsStr); err == nil {
-					rangeFilter["$gte"] = minBeds
-				}
-			}
-			if maxBedsStr != "" {
-				if maxBeds, err := strconv.Atoi(maxBedsStr); err == nil {
-					rangeFilter["$lte"] = maxBeds
-				}
-			}
-			if len(rangeFilter) > 0 {
-				filter["beds"] = rangeFilter
-			}
-		}
+func updateHospital(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-		// --- Отримання з бази ---
-		cursor, err := col.Find(context.TODO(), filter)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(context.TODO())
+	var update models.Hospital
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	update.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
+	_, err := db.Collection("hospitals").UpdateOne(context.TODO(), alive(bson.M{"_id": objID}), bson.M{"$set": update})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, tr(r, "hospital.updated"))
+}
 
-		var hospitals []models.Hospital
-		if err := cursor.All(context.TODO(), &hospitals); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writeJSONHospitals(w, hospitals)
+func deleteHospital(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-	case http.MethodPost:
-		var hospital models.Hospital
-		if err := json.NewDecoder(r.Body).Decode(&hospital); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	err := deleteHospitalCascade(context.TODO(), objID, actor(r))
+	switch {
+	case errors.Is(err, errHospitalNotFound):
+		httpError(w, r, http.StatusNotFound, "hospital.not_found")
+		return
+	case errors.Is(err, errHospitalOccupied):
+		httpError(w, r, http.StatusConflict, "hospital.occupied")
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, tr(r, "hospital.deleted"))
+}
 
-		res, err := col.InsertOne(context.TODO(), hospital)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		hospital.ID = res.InsertedID.(primitive.ObjectID)
-		writeJSONHospitals(w, hospital)
+// Query-параметри hospitalFilter для документації
+var hospitalParams = []Param{
+	{Name: "name", Description: "Case-insensitive substring of the name"},
+	{Name: "city", Description: "Case-insensitive substring of the city"},
+	{Name: "beds", Type: "integer", Description: "Exact number of beds"},
+	{Name: "minBeds", Type: "integer", Description: "Minimum number of beds"},
+	{Name: "maxBeds", Type: "integer", Description: "Maximum number of beds"},
+}
+
+// Фільтр списку лікарень через query params
+func hospitalFilter(query url.Values) bson.M {
+	filter := bson.M{}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Фільтр за назвою
+	if name := strings.TrimSpace(query.Get("name")); name != "" {
+		filter["name"] = containsFilter(name)
 	}
-}
 
-func hospitalHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospitaldb").Collection("hospitals")
-	id := strings.TrimPrefix(r.URL.Path, "/hospitals/")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
-		return
+	// Фільтр за містом
+	if city := strings.TrimSpace(query.Get("city")); city != "" {
+		filter["city"] = containsFilter(city)
 	}
 
-	switch r.Method {
-	case http.MethodGet:
-		var hospital models.Hospital
-		err := col.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&hospital)
-		if err != nil {
-			http.Error(w, "Hospital not found", http.StatusNotFound)
-			return
+	// Фільтр за кількістю ліжок (точно або діапазон)
+	if bedsStr := strings.TrimSpace(query.Get("beds")); bedsStr != "" {
+		if bedsInt, err := strconv.Atoi(bedsStr); err == nil {
+			filter["beds"] = bedsInt
 		}
-		writeJSONHospitals(w, hospital)
-
-	case http.MethodPut:
-		var update models.Hospital
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+	} else {
+		minBedsStr := query.Get("minBeds")
+		maxBedsStr := query.Get("maxBeds")
+		rangeFilter := bson.M{}
+
+		if minBedsStr != "" {
+			if minBeds, err := strconv.Atoi(minBedsStr); err == nil {
+				rangeFilter["$gte"] = minBeds
+			}
 		}
-		_, err := col.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": update})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if maxBedsStr != "" {
+			if maxBeds, err := strconv.Atoi(maxBedsStr); err == nil {
+				rangeFilter["$lte"] = maxBeds
+			}
 		}
-		fmt.Fprintf(w, "Hospital updated successfully")
-
-	case http.MethodDelete:
-		_, err := col.DeleteOne(context.TODO(), bson.M{"_id": objID})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if len(rangeFilter) > 0 {
+			filter["beds"] = rangeFilter
 		}
-		fmt.Fprintf(w, "Hospital deleted successfully")
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
-}
 
-func writeJSONHospitals(w http.ResponseWriter, v interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(v)
+	return filter
 }
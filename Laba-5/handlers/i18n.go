@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"hospital-api/i18n"
+	"hospital-api/models"
+)
+
+// LanguageMiddleware обирає мову повідомлень за Accept-Language
+func LanguageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Match(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(i18n.WithLang(r.Context(), lang)))
+	})
+}
+
+// tr - повідомлення key мовою запиту
+func tr(r *http.Request, key string, args ...interface{}) string {
+	return i18n.T(i18n.Lang(r.Context()), key, args...)
+}
+
+// httpError - http.Error з локалізованим повідомленням
+func httpError(w http.ResponseWriter, r *http.Request, status int, key string, args ...interface{}) {
+	http.Error(w, tr(r, key, args...), status)
+}
+
+// validationError - 400 з помилками валідації мовою запиту
+func validationError(w http.ResponseWriter, r *http.Request, err error) {
+	var verrs models.ValidationErrors
+	if errors.As(err, &verrs) {
+		err = verrs.Localize(i18n.Lang(r.Context()))
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"hospital-api/db"
+	"hospital-api/i18n"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Скільки рядків імпорту записуємо одним BulkWrite
+const importBatchSize = 500
+
+type validatable interface {
+	Validate() error
+}
+
+type importRowError struct {
+	Line   int                     `json:"line"`
+	Error  string                  `json:"error"`
+	Fields models.ValidationErrors `json:"fields,omitempty"`
+}
+
+// Звіт про імпорт: скільки рядків прочитано, пройшло валідацію та записано
+type importResult struct {
+	DryRun   bool             `json:"dryRun"`
+	Total    int              `json:"total"`
+	Valid    int              `json:"valid"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []importRowError `json:"errors,omitempty"`
+}
+
+func (res *importResult) fail(r *http.Request, line int, err error) {
+	res.Failed++
+	rowErr := importRowError{Line: line, Error: err.Error()}
+	var verrs models.ValidationErrors
+	if errors.As(err, &verrs) {
+		rowErr.Error = tr(r, "import.validation_failed")
+		rowErr.Fields = verrs.Localize(i18n.Lang(r.Context()))
+	}
+	res.Errors = append(res.Errors, rowErr)
+}
+
+// Формат визначається через ?format=csv|ndjson або Content-Type / Accept
+func bulkFormat(explicit, header string) (string, bool) {
+	switch strings.ToLower(explicit) {
+	case "csv":
+		return "csv", true
+	case "ndjson", "jsonl":
+		return "ndjson", true
+	case "":
+	default:
+		return "", false
+	}
+
+	for _, part := range strings.Split(header, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return "csv", true
+		case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/json", "*/*":
+			return "ndjson", true
+		}
+	}
+	return "", header == ""
+}
+
+// importSink - куди importRows пише рядки, що пройшли валідацію: add
+// отримує кожен рядок, flush викликається наприкінці
+type importSink[T any] struct {
+	add   func(line int, item T) error
+	flush func() error
+}
+
+// importHandler - POST /{resource}/import: CSV або NDJSON, валідація
+// кожного рядка, upsert пачками. ?dryRun=true лише перевіряє рядки.
+func importHandler[T validatable](collection string) http.HandlerFunc {
+	return importRows(func(r *http.Request, result *importResult) importSink[T] {
+		var batch []mongo.WriteModel
+		var batchLines []int
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			written := len(batch)
+			_, err := db.Collection(collection).BulkWrite(context.TODO(), batch, options.BulkWrite().SetOrdered(false))
+			var bulkErr mongo.BulkWriteException
+			if errors.As(err, &bulkErr) {
+				for _, we := range bulkErr.WriteErrors {
+					result.fail(r, batchLines[we.Index], errors.New(we.Message))
+					written--
+				}
+			} else if err != nil {
+				return err
+			}
+			result.Imported += written
+			batch, batchLines = batch[:0], batchLines[:0]
+			return nil
+		}
+		add := func(line int, item T) error {
+			model, err := upsertModel(item)
+			if err != nil {
+				result.fail(r, line, err)
+				return nil
+			}
+			batch = append(batch, model)
+			batchLines = append(batchLines, line)
+			if len(batch) >= importBatchSize {
+				return flush()
+			}
+			return nil
+		}
+		return importSink[T]{add: add, flush: flush}
+	})
+}
+
+// importRows - спільна частина імпорту: формат, читання рядків, валідація,
+// dry run і звіт; записує рядки sink
+func importRows[T validatable](sink func(r *http.Request, result *importResult) importSink[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, ok := bulkFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type"))
+		if !ok {
+			httpError(w, r, http.StatusUnsupportedMediaType, "import.unsupported_format")
+			return
+		}
+		if format == "" {
+			format = "ndjson"
+		}
+
+		result := importResult{DryRun: r.URL.Query().Get("dryRun") == "true"}
+		out := sink(r, &result)
+
+		err := readImportRows(r.Body, format, func(line int, item T, err error) error {
+			result.Total++
+			if err == nil {
+				err = item.Validate()
+			}
+			if err != nil {
+				result.fail(r, line, err)
+				return nil
+			}
+			result.Valid++
+			if sd, ok := any(&item).(interface{ ClearDeleted() }); ok {
+				sd.ClearDeleted()
+			}
+			if result.DryRun {
+				return nil
+			}
+			return out.add(line, item)
+		})
+		if err == nil {
+			err = out.flush()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		status := http.StatusOK
+		if result.Failed > 0 {
+			status = http.StatusUnprocessableEntity
+		}
+		writeResponse(w, r, status, result)
+	}
+}
+
+// Документ з _id замінюється (або створюється з цим _id), без _id - вставляється.
+// Видалений документ не замінюється: upsert упирається в його _id, і рядок
+// падає, а не відновлює документ з кошика.
+func upsertModel(item interface{}) (mongo.WriteModel, error) {
+	raw, err := bson.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	id, err := bson.Raw(raw).LookupErr("_id")
+	if err != nil {
+		return mongo.NewInsertOneModel().SetDocument(item), nil
+	}
+	return mongo.NewReplaceOneModel().
+		SetFilter(alive(bson.M{"_id": id})).
+		SetReplacement(item).
+		SetUpsert(true), nil
+}
+
+// readImportRows читає рядки CSV або NDJSON і передає кожен у fn.
+// Помилки розбору одного рядка не зупиняють імпорт, а передаються в fn.
+func readImportRows[T any](body io.Reader, format string, fn func(line int, item T, err error) error) error {
+	if format == "csv" {
+		reader := csv.NewReader(body)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read CSV header: %w", err)
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return nil
+			}
+			// FieldPos відомий лише для прочитаного запису; у битого рядка
+			// номер є в самій помилці розбору
+			var line int
+			var parseErr *csv.ParseError
+			if err == nil {
+				line, _ = reader.FieldPos(0)
+			} else if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			var item T
+			if err == nil {
+				err = decodeCSVRecord(header, record, &item)
+			}
+			if err := fn(line, item, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var item T
+		err := json.Unmarshal([]byte(text), &item)
+		if err := fn(line, item, err); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// exportHandler - GET /{resource}/export: потоково віддає CSV або NDJSON
+// з тими самими фільтрами, що й список ресурсу
+func exportHandler[T any](collection string, filter func(url.Values) bson.M) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, ok := bulkFormat(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+		if !ok {
+			httpError(w, r, http.StatusNotAcceptable, "export.unsupported_format")
+			return
+		}
+		if format == "" {
+			format = "ndjson"
+		}
+
+		match, ok := visible(w, r, filter(r.URL.Query()))
+		if !ok {
+			return
+		}
+		cursor, err := db.Collection(collection).Find(context.TODO(), match)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer cursor.Close(context.TODO())
+
+		rc := http.NewResponseController(w)
+		var csvWriter *csv.Writer
+		encoder := json.NewEncoder(w)
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", collection+".csv"))
+			csvWriter = csv.NewWriter(w)
+			var zero T
+			csvWriter.Write(csvHeader(reflect.TypeOf(zero)))
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", collection+".ndjson"))
+		}
+
+		// Заголовки вже надіслано, тож помилку посеред потоку не передати
+		// статусом: обриваємо з'єднання, щоб клієнт не прийняв обрізаний
+		// файл за повний
+		abort := func(err error) {
+			log.Printf("export %s: %v", collection, err)
+			panic(http.ErrAbortHandler)
+		}
+		rows := 0
+		for cursor.Next(context.TODO()) {
+			var item T
+			if err := cursor.Decode(&item); err != nil {
+				abort(err)
+			}
+			if csvWriter != nil {
+				csvWriter.Write(csvRecord(reflect.ValueOf(item)))
+			} else {
+				encoder.Encode(item)
+			}
+			rows++
+			if rows%100 == 0 {
+				if csvWriter != nil {
+					csvWriter.Flush()
+				}
+				rc.Flush()
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			abort(err)
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+	}
+}
@@ -3,121 +3,259 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"hospital-api/db"
 	"hospital-api/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func MedicineRoutes() {
-	http.HandleFunc("/medications", medicinesHandler)
-	http.HandleFunc("/medications/", medicineHandler)
+func MedicineRoutes(router *Router) {
+	medications := router.Group("/medications")
+
+	medications.HandleFunc(http.MethodGet, "", listMedicines).
+		Describe("List medications").Query(medicineParams...).Query(includeDeletedParam).Returns([]models.Medicine{})
+	medications.HandleFunc(http.MethodPost, "", createMedicine).
+		Describe("Create a medicine").Accepts(models.Medicine{}).Returns(models.Medicine{})
+	medications.HandleFunc(http.MethodGet, "/{id}", getMedicine).
+		Describe("Get a medicine").Query(includeDeletedParam).Returns(models.Medicine{})
+	medications.HandleFunc(http.MethodPut, "/{id}", updateMedicine).
+		Describe("Update a medicine").Accepts(models.Medicine{})
+	medications.HandleFunc(http.MethodDelete, "/{id}", deleteMedicine).
+		Describe("Soft-delete a medicine")
+
+	// Видача списує залишок і пише, хто видав, - лише з JWT, а видавати
+	// може тільки адміністратор
+	dispensing := router.Group("/medications/{id}", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	dispensing.HandleFunc(http.MethodPost, "/dispense", dispenseMedicine, RequireRole("admin")).
+		Describe("Dispense a medicine to a patient and decrease its stock").
+		Accepts(models.Dispensation{}).Returns(models.Dispensation{})
+	dispensing.HandleFunc(http.MethodGet, "/dispensations", listDispensations).
+		Describe("List dispensations of a medicine").Returns([]models.Dispensation{})
+
+	restoreRoute(router, "/medications", "Medicine", restoreHandler("medications", "medicine.not_in_trash"))
+
+	medications.Handle(http.MethodPost, "/import", importHandler[models.Medicine]("medications")).
+		Describe("Import medications from CSV or NDJSON").Query(importParams...).
+		Accepts([]models.Medicine{}, "text/csv", "application/x-ndjson").Returns(importResult{})
+	medications.Handle(http.MethodGet, "/export", exportHandler[models.Medicine]("medications", medicineFilter)).
+		Describe("Export medications as CSV or NDJSON").Query(medicineParams...).Query(exportParams...).
+		Returns([]models.Medicine{}, "text/csv", "application/x-ndjson")
 }
 
-func medicinesHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospital_db").Collection("medications")
+func listMedicines(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, medicineFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
 
-	switch r.Method {
-	case http.MethodGet:
-		// --- фільтрація через query parameters ---
-		filter := bson.M{}
-		query := r.URL.Query()
+	cursor, err := db.Collection("medications").Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
 
-		if name := strings.TrimSpace(query.Get("name")); name != "" {
-			filter["name"] = bson.M{"$regex": name, "$options": "i"} // пошук за частиною назви
-		}
-		if dosage := strings.TrimSpace(query.Get("dosage")); dosage != "" {
-			filter["dosage"] = bson.M{"$regex": dosage, "$options": "i"}
-		}
-		if manufacturer := strings.TrimSpace(query.Get("manufacturer")); manufacturer != "" {
-			filter["manufacturer"] = bson.M{"$regex": manufacturer, "$options": "i"}
-		}
+	var medicines []models.Medicine
+	if err := cursor.All(context.TODO(), &medicines); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, medicines)
+}
 
-		cursor, err := col.Find(context.TODO(), filter)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(context.TODO())
+func createMedicine(w http.ResponseWriter, r *http.Request) {
+	var medicine models.Medicine
+	if err := json.NewDecoder(r.Body).Decode(&medicine); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	medicine.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
 
-		var medicines []models.Medicine
-		if err := cursor.All(context.TODO(), &medicines); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writeJSONHospital(w, medicines)
+	res, err := db.Collection("medications").InsertOne(context.TODO(), medicine)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	medicine.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusOK, medicine)
+}
 
-	case http.MethodPost:
-		var medicine models.Medicine
-		if err := json.NewDecoder(r.Body).Decode(&medicine); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+func getMedicine(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
 
-		res, err := col.InsertOne(context.TODO(), medicine)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		medicine.ID = res.InsertedID.(primitive.ObjectID)
-		writeJSONHospital(w, medicine)
+	var medicine models.Medicine
+	err := db.Collection("medications").FindOne(context.TODO(), filter).Decode(&medicine)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, medicine)
+}
+
+func updateMedicine(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	var update models.Medicine
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	update.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
+	_, err := db.Collection("medications").UpdateOne(context.TODO(), alive(bson.M{"_id": objID}), bson.M{"$set": update})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	fmt.Fprint(w, tr(r, "medicine.updated"))
 }
 
-func medicineHandler(w http.ResponseWriter, r *http.Request) {
-	col := db.Client.Database("hospital_db").Collection("medications")
-	id := strings.TrimPrefix(r.URL.Path, "/medications/")
-	objID, err := primitive.ObjectIDFromHex(id)
+func deleteMedicine(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := softDelete(context.TODO(), r, "medications", bson.M{"_id": objID})
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if !found {
+		httpError(w, r, http.StatusNotFound, "medicine.not_found")
+		return
+	}
+	fmt.Fprint(w, tr(r, "medicine.deleted"))
+}
 
-	switch r.Method {
-	case http.MethodGet:
-		var medicine models.Medicine
-		err := col.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&medicine)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		writeJSONHospital(w, medicine)
+var errOutOfStock = errors.New("not enough medicine in stock")
 
-	case http.MethodPut:
-		var update models.Medicine
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		_, err := col.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": update})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		fmt.Fprintf(w, "Medicine updated successfully")
+// dispenseMedicine списує ліки зі складу і записує видачу однією
+// одиницею роботи: або обидва записи, або жодного
+func dispenseMedicine(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var dispensation models.Dispensation
+	if err := json.NewDecoder(r.Body).Decode(&dispensation); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := dispensation.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+	dispensation.ID = primitive.NewObjectID()
+	dispensation.MedicineID = objID
+	dispensation.DispensedAt = time.Now().UTC()
+	dispensation.DispensedBy = actor(r)
 
-	case http.MethodDelete:
-		_, err := col.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	medications := db.Collection("medications")
+	dispensations := db.Collection("dispensations")
+	err := db.RunInTransaction(context.TODO(), func(u *db.UnitOfWork) error {
+		err := u.Step(func(ctx context.Context) error {
+			res, err := medications.UpdateOne(ctx,
+				alive(bson.M{"_id": objID, "stock": bson.M{"$gte": dispensation.Quantity}}),
+				bson.M{"$inc": bson.M{"stock": -dispensation.Quantity}},
+			)
+			if err == nil && res.MatchedCount == 0 {
+				err = errOutOfStock
+			}
+			return err
+		}, func(ctx context.Context) error {
+			_, err := medications.UpdateByID(ctx, objID, bson.M{"$inc": bson.M{"stock": dispensation.Quantity}})
+			return err
+		})
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return err
+		}
+		return u.Step(func(ctx context.Context) error {
+			_, err := dispensations.InsertOne(ctx, dispensation)
+			return err
+		}, func(ctx context.Context) error {
+			_, err := dispensations.DeleteOne(ctx, bson.M{"_id": dispensation.ID})
+			return err
+		})
+	})
+	if errors.Is(err, errOutOfStock) {
+		if exists(medications, objID) {
+			httpError(w, r, http.StatusConflict, "medicine.out_of_stock")
+		} else {
+			httpError(w, r, http.StatusNotFound, "medicine.not_found")
 		}
-		fmt.Fprintf(w, "Medicine deleted successfully")
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, dispensation)
+}
+
+// Видачі ліків, найновіші першими
+func listDispensations(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "dispensedAt", Value: -1}})
+	cursor, err := db.Collection("dispensations").Find(context.TODO(), bson.M{"medicineId": objID}, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	var dispensations []models.Dispensation
+	if err := cursor.All(context.TODO(), &dispensations); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	writeResponse(w, r, http.StatusOK, dispensations)
+}
+
+// Query-параметри medicineFilter для документації
+var medicineParams = []Param{
+	{Name: "name", Description: "Case-insensitive substring of the name"},
+	{Name: "dosage", Description: "Case-insensitive substring of the dosage"},
+	{Name: "manufacturer", Description: "Case-insensitive substring of the manufacturer"},
 }
 
-func writeJSONHospital(w http.ResponseWriter, v interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(v)
+// Фільтр списку ліків через query params
+func medicineFilter(query url.Values) bson.M {
+	filter := bson.M{}
+
+	if name := strings.TrimSpace(query.Get("name")); name != "" {
+		filter["name"] = containsFilter(name) // пошук за частиною назви
+	}
+	if dosage := strings.TrimSpace(query.Get("dosage")); dosage != "" {
+		filter["dosage"] = containsFilter(dosage)
+	}
+	if manufacturer := strings.TrimSpace(query.Get("manufacturer")); manufacturer != "" {
+		filter["manufacturer"] = containsFilter(manufacturer)
+	}
+
+	return filter
 }
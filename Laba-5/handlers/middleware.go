@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// accessLog - журнал запитів, відкритий один раз на весь процес.
+// Глобальний log не чіпаємо: ним пишуть фонові задачі.
+var accessLog = openAccessLog("access.log")
+
+func openAccessLog(path string) *log.Logger {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Println("Cannot open log file:", err)
+		return log.New(os.Stderr, "", log.LstdFlags)
+	}
+	return log.New(f, "", log.LstdFlags)
+}
+
+// Middleware для логування
+func LoggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessLog.Printf("%s %s\n", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiKey - ключ для X-API-KEY (і x-api-key у метаданих gRPC)
+const apiKey = "my-secret-key"
+
+// Middleware для простого ключа авторизації
+func APIKeyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hasAPIKey(r) {
+			httpError(w, r, http.StatusUnauthorized, "auth.unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hasAPIKey(r *http.Request) bool {
+	return r.Header.Get("X-API-KEY") == apiKey
+}
+
+// JWT - JWTAuthMiddleware у вигляді Middleware для груп маршрутів
+func JWT(allowedRoles ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return JWTAuthMiddleware(next, allowedRoles...)
+	}
+}
+
+// RequireRole пропускає лише користувачів з однією з ролей.
+// Ставиться після JWT, бо читає claims з контексту.
+func RequireRole(roles ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetClaims(r)
+			if claims != nil {
+				for _, role := range roles {
+					if claims.Role == role {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+			httpError(w, r, http.StatusForbidden, "auth.forbidden")
+		})
+	}
+}
+
+// pathID читає {id} з шляху; при помилці відповідає 400
+func pathID(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	objID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.invalid_id")
+		return primitive.NilObjectID, false
+	}
+	return objID, true
+}
+
+// nestedList обслуговує вкладений маршрут (/hospitals/{id}/departments)
+// звичайним обробником списку з фільтром param={id}, тож решта
+// фільтрів і ?expand= працюють так само
+func nestedList(param string, list http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := pathID(w, r); !ok {
+			return
+		}
+		query := r.URL.Query()
+		query.Set(param, r.PathValue("id"))
+
+		u := *r.URL
+		u.RawQuery = query.Encode()
+		nested := r.WithContext(r.Context())
+		nested.URL = &u
+		list(w, nested)
+	}
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	_ "embed"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"hospital-api/idempotency"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:embed docs/index.html
+var docsPage []byte
+
+// DocsRoutes - специфікація OpenAPI та сторінка документації.
+// Специфікація будується з уже зареєстрованих маршрутів, тож
+// DocsRoutes варто викликати після всіх інших *Routes.
+func DocsRoutes(router *Router) {
+	router.HandleFunc(http.MethodGet, "/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, r, http.StatusOK, OpenAPI(router))
+	}).
+		Describe("OpenAPI specification").Returns(map[string]interface{}{})
+
+	router.HandleFunc(http.MethodGet, "/docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(docsPage)
+	}).
+		Describe("Interactive API documentation").Returns(nil, "text/html")
+}
+
+// Спільні query-параметри імпорту, експорту та expand
+var (
+	importParams = []Param{
+		{Name: "dryRun", Type: "boolean", Description: "Validate rows without writing them"},
+		{Name: "format", Description: "csv or ndjson; defaults to the Content-Type header"},
+	}
+	exportParams = []Param{
+		{Name: "format", Description: "csv or ndjson; defaults to the Accept header"},
+		includeDeletedParam,
+	}
+)
+
+func expandParam(collection string) Param {
+	var names []string
+	for name := range expansions[collection] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	description := "Comma-separated related documents to embed: " + strings.Join(names, ", ")
+	for _, name := range names {
+		if apiKeyCollections[expansions[collection][name].from] && !apiKeyCollections[collection] {
+			description += ". Embedding hospitals, departments or doctors also requires X-API-KEY"
+			break
+		}
+	}
+	return Param{Name: "expand", Description: description}
+}
+
+var pathParamRe = regexp.MustCompile(`\{([a-zA-Z_]+)\.{0,3}\}`)
+
+// OpenAPI будує документ OpenAPI 3.1 з маршрутів роутера і моделей
+func OpenAPI(router *Router) map[string]interface{} {
+	schemas := map[string]interface{}{}
+	paths := map[string]map[string]interface{}{}
+
+	for _, route := range router.Routes() {
+		path := strings.ReplaceAll(route.Pattern, "{$}", "")
+		if path == "" {
+			path = "/"
+		}
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		paths[path][strings.ToLower(route.Method)] = operation(route, path, schemas)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   "hospital-api",
+			"version": "1.0.0",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": "http://localhost:8080"}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				SecurityBearer: map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				SecurityAPIKey: map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-KEY"},
+				SecurityFeedToken: map[string]interface{}{"type": "apiKey", "in": "query", "name": "token",
+					"description": "Token from POST /calendar/token; opens only the feed it was issued for"},
+			},
+		},
+	}
+}
+
+func operation(route Route, path string, schemas map[string]interface{}) map[string]interface{} {
+	op := map[string]interface{}{}
+	if route.Summary != "" {
+		op["summary"] = route.Summary
+	}
+	tag := strings.Split(strings.Trim(path, "/"), "/")[0]
+	if tag != "" {
+		op["tags"] = []string{tag}
+	}
+
+	var params []interface{}
+	for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string", "pattern": "^[0-9a-fA-F]{24}$"},
+		})
+	}
+	for _, p := range route.Params {
+		schema := map[string]interface{}{"type": p.Type}
+		if p.Type == "" {
+			schema["type"] = "string"
+		}
+		if p.Format != "" {
+			schema["format"] = p.Format
+		}
+		param := map[string]interface{}{"name": p.Name, "in": "query", "schema": schema}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		params = append(params, param)
+	}
+	if route.Method == http.MethodPost {
+		params = append(params, map[string]interface{}{
+			"name":        idempotency.Header,
+			"in":          "header",
+			"description": "A retry with the same key and body returns the stored response",
+			"schema":      map[string]interface{}{"type": "string", "maxLength": 255},
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if route.Body != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  content(route.Body, route.BodyTypes, schemas),
+		}
+	}
+
+	success := map[string]interface{}{"description": "OK"}
+	if route.Response != nil || len(route.ResponseTypes) > 0 {
+		success["content"] = content(route.Response, route.ResponseTypes, schemas)
+	} else {
+		success["content"] = map[string]interface{}{"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}}
+	}
+	responses := map[string]interface{}{"200": success}
+	if route.Response != nil && len(route.ResponseTypes) == 0 {
+		// writeResponse віддає й інші формати за Accept
+		for _, ct := range []string{"text/csv", "application/xml", "application/msgpack"} {
+			success["content"].(map[string]interface{})[ct] = map[string]interface{}{}
+		}
+		responses["406"] = map[string]interface{}{"description": "None of the Accept media types can be produced"}
+	}
+	if len(params) > 0 || route.Body != nil {
+		responses["400"] = map[string]interface{}{"description": "Bad request"}
+	}
+	if route.Security != "" {
+		op["security"] = []interface{}{map[string]interface{}{route.Security: []string{}}}
+		responses["401"] = map[string]interface{}{"description": "Unauthorized"}
+		if route.Security == SecurityBearer || route.Security == SecurityFeedToken {
+			responses["403"] = map[string]interface{}{"description": "Forbidden"}
+		}
+	}
+	if route.Method == http.MethodPost {
+		responses["409"] = map[string]interface{}{"description": "Request with this Idempotency-Key is still in progress"}
+		responses["422"] = map[string]interface{}{"description": "Idempotency-Key reused with a different request"}
+	}
+	if strings.Contains(path, "{id}") {
+		responses["404"] = map[string]interface{}{"description": "Not found"}
+	}
+	op["responses"] = responses
+	return op
+}
+
+func content(v interface{}, contentTypes []string, schemas map[string]interface{}) map[string]interface{} {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"application/json"}
+	}
+	out := map[string]interface{}{}
+	for _, ct := range contentTypes {
+		entry := map[string]interface{}{}
+		switch {
+		case v == nil:
+			entry["schema"] = map[string]interface{}{"type": "string"}
+		case ct == "application/json":
+			entry["schema"] = schemaFor(reflect.TypeOf(v), schemas)
+		case ct == "application/x-ndjson":
+			// Кожен рядок - один документ; описуємо схему рядка
+			t := reflect.TypeOf(v)
+			if t.Kind() == reflect.Slice {
+				t = t.Elem()
+			}
+			entry["schema"] = schemaFor(t, schemas)
+		default:
+			entry["schema"] = map[string]interface{}{"type": "string"}
+		}
+		out[ct] = entry
+	}
+	return out
+}
+
+// schemaFor описує Go-тип як JSON Schema; іменовані структури
+// потрапляють у components/schemas і підставляються через $ref
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case objectIDType:
+		return map[string]interface{}{"type": "string", "pattern": "^[0-9a-fA-F]{24}$"}
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]interface{}{"type": "integer", "description": "nanoseconds"}
+	case reflect.TypeOf(primitive.DateTime(0)):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Interface:
+		return map[string]interface{}{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		name := t.Name()
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = map[string]interface{}{} // захист від рекурсії
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				collect(f.Type)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = schemaFor(f.Type, schemas)
+		}
+	}
+	collect(t)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
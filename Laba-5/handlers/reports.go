@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Звіти для керівництва: агрегації MongoDB, відповідь у JSON або CSV
+func ReportRoutes(router *Router) {
+	reports := router.Group("/reports", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	formatParam := Param{Name: "format", Description: "json or csv; defaults to the Accept header"}
+
+	reports.HandleFunc(http.MethodGet, "/beds", bedsReport).
+		Describe("Beds and departments per hospital").Query(formatParam).Returns([]bedsReportRow{}, "application/json", "text/csv")
+	reports.HandleFunc(http.MethodGet, "/departments-per-floor", departmentsPerFloorReport).
+		Describe("Number of departments on each floor").
+		Query(Param{Name: "hospitalId", Description: "Only this hospital"}, formatParam).
+		Returns([]floorReportRow{}, "application/json", "text/csv")
+	reports.HandleFunc(http.MethodGet, "/doctors-by-specialty", doctorsBySpecialtyReport).
+		Describe("Doctors per specialty with average experience").Query(formatParam).
+		Returns([]specialtyReportRow{}, "application/json", "text/csv")
+	reports.HandleFunc(http.MethodGet, "/appointments", appointmentsReport).
+		Describe("Appointments per doctor per day or week").
+		Query(
+			Param{Name: "from", Format: "date", Description: "First day (YYYY-MM-DD or RFC 3339)"},
+			Param{Name: "to", Format: "date", Description: "Last day, inclusive (YYYY-MM-DD or RFC 3339)"},
+			Param{Name: "interval", Description: "day (default) or week"},
+			Param{Name: "doctorId", Description: "Only this doctor"},
+			formatParam,
+		).
+		Returns([]appointmentsReportRow{}, "application/json", "text/csv")
+	reports.HandleFunc(http.MethodGet, "/medicine-stock", medicineStockReport).
+		Describe("Medicine stock and its value by manufacturer").Query(formatParam).
+		Returns([]stockReportRow{}, "application/json", "text/csv")
+}
+
+type bedsReportRow struct {
+	HospitalID  primitive.ObjectID `bson:"_id" json:"hospitalId"`
+	Name        string             `bson:"name" json:"name"`
+	Location    string             `bson:"location" json:"location"`
+	Beds        int                `bson:"beds" json:"beds"`
+	Departments int                `bson:"departments" json:"departments"`
+}
+
+type floorReportRow struct {
+	HospitalID  primitive.ObjectID `bson:"hospitalId" json:"hospitalId"`
+	Floor       int                `bson:"floor" json:"floor"`
+	Departments int                `bson:"departments" json:"departments"`
+}
+
+type specialtyReportRow struct {
+	Specialty     string  `bson:"_id" json:"specialty"`
+	Doctors       int     `bson:"doctors" json:"doctors"`
+	AvgExperience float64 `bson:"avgExperience" json:"avgExperience"`
+	MinExperience int     `bson:"minExperience" json:"minExperience"`
+	MaxExperience int     `bson:"maxExperience" json:"maxExperience"`
+}
+
+type appointmentsReportRow struct {
+	DoctorID     primitive.ObjectID `bson:"doctorId" json:"doctorId"`
+	DoctorName   string             `bson:"doctorName" json:"doctorName"`
+	Period       time.Time          `bson:"period" json:"period"`
+	Appointments int                `bson:"appointments" json:"appointments"`
+}
+
+type stockReportRow struct {
+	Manufacturer string  `bson:"_id" json:"manufacturer"`
+	Items        int     `bson:"items" json:"items"`
+	TotalStock   int     `bson:"totalStock" json:"totalStock"`
+	StockValue   float64 `bson:"stockValue" json:"stockValue"`
+}
+
+func bedsReport(w http.ResponseWriter, r *http.Request) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted()}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "departments", "localField": "_id", "foreignField": "hospital_id",
+			"pipeline": bson.A{bson.M{"$match": notDeleted()}}, "as": "deps",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"name": 1, "location": 1, "beds": 1,
+			"departments": bson.M{"$size": "$deps"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "beds", Value: -1}, {Key: "name", Value: 1}}}},
+	}
+	runReport[bedsReportRow](w, r, "hospitals", "beds", pipeline)
+}
+
+func departmentsPerFloorReport(w http.ResponseWriter, r *http.Request) {
+	match := notDeleted()
+	if hospital := r.URL.Query().Get("hospitalId"); hospital != "" {
+		objID, err := primitive.ObjectIDFromHex(hospital)
+		if err != nil {
+			httpError(w, r, http.StatusBadRequest, "request.invalid_hospital_id")
+			return
+		}
+		match["hospital_id"] = objID
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"hospitalId": "$hospital_id", "floor": "$floor"},
+			"departments": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id": 0, "hospitalId": "$_id.hospitalId", "floor": "$_id.floor", "departments": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "hospitalId", Value: 1}, {Key: "floor", Value: 1}}}},
+	}
+	runReport[floorReportRow](w, r, "departments", "departments-per-floor", pipeline)
+}
+
+func doctorsBySpecialtyReport(w http.ResponseWriter, r *http.Request) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted()}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$specialty",
+			"doctors":       bson.M{"$sum": 1},
+			"avgExperience": bson.M{"$avg": "$experience_years"},
+			"minExperience": bson.M{"$min": "$experience_years"},
+			"maxExperience": bson.M{"$max": "$experience_years"},
+		}}},
+		{{Key: "$set", Value: bson.M{"avgExperience": bson.M{"$round": bson.A{"$avgExperience", 1}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "doctors", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	runReport[specialtyReportRow](w, r, "doctors", "doctors-by-specialty", pipeline)
+}
+
+func appointmentsReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	match, err := DateRange(query.Get("from"), query.Get("to"))
+	var dateErr *ReportDateError
+	if errors.As(err, &dateErr) {
+		httpError(w, r, http.StatusBadRequest, "report.date", dateErr.Param)
+		return
+	}
+	if doctor := query.Get("doctorId"); doctor != "" {
+		objID, err := primitive.ObjectIDFromHex(doctor)
+		if err != nil {
+			httpError(w, r, http.StatusBadRequest, "request.invalid_doctor_id")
+			return
+		}
+		match["doctorId"] = objID
+	}
+	alive(match)
+
+	unit := "day"
+	switch interval := query.Get("interval"); interval {
+	case "", "day":
+	case "week":
+		unit = "week"
+	default:
+		httpError(w, r, http.StatusBadRequest, "report.interval")
+		return
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"doctorId": "$doctorId",
+				"period":   bson.M{"$dateTrunc": bson.M{"date": "$date", "unit": unit, "startOfWeek": "monday"}},
+			},
+			"appointments": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$lookup", Value: bson.M{"from": "doctors", "localField": "_id.doctorId", "foreignField": "_id", "as": "doctor"}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"doctorId":     "$_id.doctorId",
+			"period":       "$_id.period",
+			"appointments": 1,
+			"doctorName":   bson.M{"$ifNull": bson.A{bson.M{"$first": "$doctor.name"}, ""}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "period", Value: 1}, {Key: "doctorName", Value: 1}}}},
+	}
+	runReport[appointmentsReportRow](w, r, "appointments", "appointments", pipeline)
+}
+
+func medicineStockReport(w http.ResponseWriter, r *http.Request) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted()}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$manufacturer",
+			"items":      bson.M{"$sum": 1},
+			"totalStock": bson.M{"$sum": "$stock"},
+			"stockValue": bson.M{"$sum": bson.M{"$multiply": bson.A{"$stock", bson.M{"$ifNull": bson.A{"$price", 0}}}}},
+		}}},
+		{{Key: "$set", Value: bson.M{"stockValue": bson.M{"$round": bson.A{"$stockValue", 2}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "stockValue", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	runReport[stockReportRow](w, r, "medications", "medicine-stock", pipeline)
+}
+
+// ReportDateError - параметр from або to не є датою
+type ReportDateError struct {
+	Param string
+	Err   error
+}
+
+func (e *ReportDateError) Error() string { return "invalid " + e.Param + ": " + e.Err.Error() }
+
+func (e *ReportDateError) Unwrap() error { return e.Err }
+
+// DateRange будує фільтр по полю date; "to" з датою без часу включає весь день
+func DateRange(from, to string) (bson.M, error) {
+	match := bson.M{}
+	rangeFilter := bson.M{}
+	if from != "" {
+		start, _, err := parseReportDate(from)
+		if err != nil {
+			return nil, &ReportDateError{Param: "from", Err: err}
+		}
+		rangeFilter["$gte"] = start
+	}
+	if to != "" {
+		end, dateOnly, err := parseReportDate(to)
+		if err != nil {
+			return nil, &ReportDateError{Param: "to", Err: err}
+		}
+		if dateOnly {
+			rangeFilter["$lt"] = end.Add(24 * time.Hour)
+		} else {
+			rangeFilter["$lte"] = end
+		}
+	}
+	if len(rangeFilter) > 0 {
+		match["date"] = rangeFilter
+	}
+	return match, nil
+}
+
+func parseReportDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// runReport виконує агрегацію і віддає рядки через writeResponse.
+// Параметр format має перевагу над заголовком Accept.
+func runReport[T any](w http.ResponseWriter, r *http.Request, collection, name string, pipeline mongo.Pipeline) {
+	accept := ""
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "csv":
+		accept = "text/csv"
+	case "json":
+		accept = "application/json"
+	case "":
+	default:
+		httpError(w, r, http.StatusBadRequest, "report.format")
+		return
+	}
+	if accept != "" {
+		r = r.Clone(r.Context())
+		r.Header.Set("Accept", accept)
+	}
+
+	cursor, err := db.Collection(collection).Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	rows := []T{}
+	if err := cursor.All(context.TODO(), &rows); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if format, ok := negotiateFormat(r.Header.Get("Accept")); ok && format.name == "csv" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	}
+	writeResponse(w, r, http.StatusOK, rows)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Єдиний спосіб віддати дані: writeResponse обирає формат за Accept
+// (JSON, CSV, XML, MessagePack) і стиснення за Accept-Encoding (zstd, gzip).
+// Поля в усіх форматах мають json-імена моделей.
+
+// responseFormat - формат відповіді і всі його media types; перший - основний
+type responseFormat struct {
+	name       string
+	mediaTypes []string
+	encode     func(v interface{}) ([]byte, error)
+}
+
+var responseFormats = []responseFormat{
+	{"json", []string{"application/json"}, encodeJSON},
+	{"csv", []string{"text/csv"}, encodeCSV},
+	{"xml", []string{"application/xml", "text/xml"}, encodeXML},
+	{"msgpack", []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}, encodeMsgpack},
+}
+
+// errNotRepresentable - дані не лягають у формат (вкладені об'єкти в CSV)
+var errNotRepresentable = errors.New("response cannot be represented in this format")
+
+// minCompressSize - менші відповіді не стискаємо: виграшу майже немає
+const minCompressSize = 1024
+
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// writeResponse кодує v у формат з Accept і пише зі статусом status.
+// Якщо жоден формат не підходить - 406.
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	format, ok := negotiateFormat(r.Header.Get("Accept"))
+	if !ok {
+		httpError(w, r, http.StatusNotAcceptable, "response.not_acceptable")
+		return
+	}
+	body, err := format.encode(v)
+	if errors.Is(err, errNotRepresentable) {
+		httpError(w, r, http.StatusNotAcceptable, "response.not_representable", format.mediaTypes[0])
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := format.mediaTypes[0]
+	if format.name != "msgpack" {
+		contentType += "; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept, Accept-Encoding")
+	body = compressBody(w, r, body)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// NegotiateMiddleware відповідає 406 ще до обробника, якщо запит, що
+// змінює дані, не приймає ні наших форматів, ні text/plain (повідомлення
+// на кшталт "updated successfully"). Інакше зміна відбулася б, а клієнт
+// отримав би 406. GET перевіряє сам writeResponse.
+func NegotiateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			accept := r.Header.Get("Accept")
+			if _, ok := negotiateFormat(accept); !ok && acceptQuality(parseAccept(accept), "text/plain") == 0 {
+				httpError(w, r, http.StatusNotAcceptable, "response.not_acceptable")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acceptRange - один елемент Accept або Accept-Encoding з вагою q
+type acceptRange struct {
+	value string
+	q     float64
+}
+
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			if k, v, ok := strings.Cut(strings.TrimSpace(p), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{value, q})
+	}
+	return ranges
+}
+
+// acceptQuality - вага media type за найточнішим збігом:
+// "text/csv" точніший за "text/*", а той - за "*/*"
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	major, _, _ := strings.Cut(mediaType, "/")
+	best, specificity := 0.0, -1
+	for _, ar := range ranges {
+		s := -1
+		switch ar.value {
+		case mediaType:
+			s = 2
+		case major + "/*":
+			s = 1
+		case "*/*", "*":
+			s = 0
+		}
+		if s > specificity {
+			best, specificity = ar.q, s
+		}
+	}
+	return best
+}
+
+// negotiateFormat обирає формат з найбільшою вагою; за рівної ваги -
+// у порядку responseFormats. Порожній Accept означає JSON.
+func negotiateFormat(accept string) (responseFormat, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return responseFormats[0], true
+	}
+	ranges := parseAccept(accept)
+	best, bestQ := responseFormat{}, 0.0
+	for _, f := range responseFormats {
+		for _, mt := range f.mediaTypes {
+			if q := acceptQuality(ranges, mt); q > bestQ {
+				best, bestQ = f, q
+			}
+		}
+	}
+	return best, bestQ > 0
+}
+
+// compressBody стискає body за Accept-Encoding і ставить Content-Encoding
+func compressBody(w http.ResponseWriter, r *http.Request, body []byte) []byte {
+	if len(body) < minCompressSize {
+		return body
+	}
+	ranges := parseAccept(r.Header.Get("Accept-Encoding"))
+	encodings := []string{"zstd", "gzip"}
+	sort.SliceStable(encodings, func(i, j int) bool {
+		return encodingQuality(ranges, encodings[i]) > encodingQuality(ranges, encodings[j])
+	})
+
+	switch encoding := encodings[0]; {
+	case encodingQuality(ranges, encoding) == 0:
+		return body
+	case encoding == "zstd":
+		w.Header().Set("Content-Encoding", "zstd")
+		return zstdEncoder.EncodeAll(body, nil)
+	default:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(body)
+		gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		return buf.Bytes()
+	}
+}
+
+func encodingQuality(ranges []acceptRange, encoding string) float64 {
+	best, exact := 0.0, false
+	for _, ar := range ranges {
+		switch {
+		case ar.value == encoding:
+			best, exact = ar.q, true
+		case ar.value == "*" && !exact:
+			best = ar.q
+		}
+	}
+	return best
+}
+
+func encodeJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware обгортає обробник (логування, авторизація тощо)
+type Middleware func(http.Handler) http.Handler
+
+// Схеми авторизації для документації маршрутів
+const (
+	SecurityBearer = "bearerAuth"
+	SecurityAPIKey = "apiKeyAuth"
+	// Стрічки .ics: токен календаря в ?token= або Bearer JWT
+	SecurityFeedToken = "feedToken"
+)
+
+// Route - один зареєстрований маршрут разом з описом для OpenAPI
+type Route struct {
+	Method   string
+	Pattern  string
+	Security string
+
+	Summary       string
+	Params        []Param
+	Body          interface{}
+	BodyTypes     []string
+	Response      interface{}
+	ResponseTypes []string
+}
+
+// Param - query-параметр маршруту
+type Param struct {
+	Name        string
+	Type        string // string, integer, boolean
+	Format      string // date, date-time тощо
+	Description string
+}
+
+func (rt *Route) Describe(summary string) *Route {
+	rt.Summary = summary
+	return rt
+}
+
+func (rt *Route) Query(params ...Param) *Route {
+	rt.Params = append(rt.Params, params...)
+	return rt
+}
+
+// Accepts задає тіло запиту; без contentTypes - application/json
+func (rt *Route) Accepts(body interface{}, contentTypes ...string) *Route {
+	rt.Body = body
+	rt.BodyTypes = contentTypes
+	return rt
+}
+
+// Returns задає тіло успішної відповіді; без contentTypes - application/json
+func (rt *Route) Returns(response interface{}, contentTypes ...string) *Route {
+	rt.Response = response
+	rt.ResponseTypes = contentTypes
+	return rt
+}
+
+// Router - обгортка над http.ServeMux з шаблонами Go 1.22+
+// ("GET /doctors/{id}"). ServeMux сам відповідає 405 з заголовком Allow,
+// якщо шлях існує, але метод не зареєстрований.
+// Групи маршрутів мають власний ланцюжок middleware.
+type Router struct {
+	mux        *http.ServeMux
+	prefix     string
+	middleware []Middleware
+	inner      []Middleware
+	security   string
+	routes     *[]*Route
+}
+
+func NewRouter() *Router {
+	return &Router{mux: http.NewServeMux(), routes: &[]*Route{}}
+}
+
+// Use додає middleware до всіх маршрутів, зареєстрованих після виклику
+func (rt *Router) Use(mw ...Middleware) {
+	rt.middleware = append(rt.middleware, mw...)
+}
+
+// UseInner додає middleware, що виконуються найближче до обробника - після
+// middleware групи й маршруту, тобто вже після авторизації
+func (rt *Router) UseInner(mw ...Middleware) {
+	rt.inner = append(rt.inner, mw...)
+}
+
+// Group створює групу з префіксом шляху; група успадковує middleware батька
+func (rt *Router) Group(prefix string, mw ...Middleware) *Router {
+	chain := make([]Middleware, 0, len(rt.middleware)+len(mw))
+	chain = append(chain, rt.middleware...)
+	chain = append(chain, mw...)
+	return &Router{
+		mux:        rt.mux,
+		prefix:     rt.prefix + prefix,
+		middleware: chain,
+		inner:      append([]Middleware(nil), rt.inner...),
+		security:   rt.security,
+		routes:     rt.routes,
+	}
+}
+
+// Secured позначає схему авторизації групи для документації
+func (rt *Router) Secured(scheme string) *Router {
+	rt.security = scheme
+	return rt
+}
+
+// Handle реєструє обробник для методу і шляху відносно префікса групи.
+// Додаткові mw застосовуються лише до цього маршруту, всередині ланцюжка групи;
+// middleware з UseInner - ще глибше, одразу перед h.
+func (rt *Router) Handle(method, path string, h http.Handler, mw ...Middleware) *Route {
+	pattern := rt.prefix + path
+	if pattern == "" {
+		pattern = "/"
+	}
+
+	for i := len(rt.inner) - 1; i >= 0; i-- {
+		h = rt.inner[i](h)
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	for i := len(rt.middleware) - 1; i >= 0; i-- {
+		h = rt.middleware[i](h)
+	}
+
+	rt.mux.Handle(strings.TrimSpace(method+" "+pattern), h)
+	route := &Route{Method: method, Pattern: pattern, Security: rt.security}
+	*rt.routes = append(*rt.routes, route)
+	return route
+}
+
+func (rt *Router) HandleFunc(method, path string, h http.HandlerFunc, mw ...Middleware) *Route {
+	return rt.Handle(method, path, h, mw...)
+}
+
+// Routes повертає всі зареєстровані маршрути в порядку реєстрації
+func (rt *Router) Routes() []Route {
+	routes := make([]Route, len(*rt.routes))
+	for i, route := range *rt.routes {
+		routes[i] = *route
+	}
+	return routes
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
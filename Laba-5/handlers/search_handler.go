@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"hospital-api/db"
+	"hospital-api/models"
+	"hospital-api/search"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Search - вбудований індекс для GET /search. RefreshSearch перебудовує
+// його з Mongo; main робить це на старті й далі періодично.
+var Search = search.NewIndex()
+
+// searchSources - що індексується: ресурс і як перетворити його документи.
+// Пацієнтів як окремих документів у базі немає (лише patientId у записах),
+// тож шукати по них нема чого.
+var searchSources = []struct {
+	resource string
+	load     func(ctx context.Context) ([]search.Document, error)
+}{
+	{"doctors", searchDocuments("doctors", func(d models.Doctor) search.Document {
+		return search.Document{Resource: "doctors", ID: d.ID.Hex(), Title: d.Name, Fields: []search.Field{
+			{Text: d.Name, Weight: 3}, {Text: d.Specialty, Weight: 1.5},
+		}}
+	})},
+	{"staff", searchDocuments("staff", func(s models.Staff) search.Document {
+		return search.Document{Resource: "staff", ID: s.ID.Hex(), Title: s.Name, Fields: []search.Field{
+			{Text: s.Name, Weight: 3}, {Text: s.Role, Weight: 1.5}, {Text: s.Shift, Weight: 0.5},
+		}}
+	})},
+	{"medications", searchDocuments("medications", func(m models.Medicine) search.Document {
+		return search.Document{Resource: "medications", ID: m.ID.Hex(), Title: m.Name, Fields: []search.Field{
+			{Text: m.Name, Weight: 3}, {Text: m.Manufacturer, Weight: 1}, {Text: m.Dosage, Weight: 0.5},
+		}}
+	})},
+	{"departments", searchDocuments("departments", func(d models.Department) search.Document {
+		return search.Document{Resource: "departments", ID: d.ID.Hex(), Title: d.Name, Fields: []search.Field{
+			{Text: d.Name, Weight: 3},
+		}}
+	})},
+}
+
+// searchDocuments читає живі документи колекції і перетворює їх для індексу
+func searchDocuments[T any](collection string, convert func(T) search.Document) func(context.Context) ([]search.Document, error) {
+	return func(ctx context.Context) ([]search.Document, error) {
+		cursor, err := db.Collection(collection).Find(ctx, notDeleted())
+		if err != nil {
+			return nil, err
+		}
+		var items []T
+		if err := cursor.All(ctx, &items); err != nil {
+			return nil, err
+		}
+		docs := make([]search.Document, 0, len(items))
+		for _, item := range items {
+			docs = append(docs, convert(item))
+		}
+		return docs, nil
+	}
+}
+
+// RefreshSearch перебудовує індекс з усіх джерел. Якщо хоч одне не
+// прочиталося, старий індекс лишається.
+func RefreshSearch(ctx context.Context) error {
+	var docs []search.Document
+	for _, source := range searchSources {
+		loaded, err := source.load(ctx)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, loaded...)
+	}
+	Search.Load(docs)
+	return nil
+}
+
+func SearchRoutes(router *Router) {
+	group := router.Group("/search", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	group.HandleFunc(http.MethodGet, "", searchHandler).
+		Describe("Search doctors, staff, medications and departments").
+		Query(searchParams...).Returns(searchResponse{})
+}
+
+var searchParams = []Param{
+	{Name: "q", Description: "Search text; case, diacritics and small typos are ignored, Latin matches Cyrillic"},
+	{Name: "type", Description: "Comma-separated resources to search: doctors, staff, medications, departments"},
+	{Name: "limit", Type: "integer", Description: "Maximum number of hits (default 20, at most 100)"},
+}
+
+type searchResponse struct {
+	Query string       `json:"query"`
+	Hits  []search.Hit `json:"hits"`
+}
+
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		httpError(w, r, http.StatusBadRequest, "search.query_required")
+		return
+	}
+
+	opts := search.Options{Limit: 20}
+	if types := query.Get("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			t = strings.TrimSpace(t)
+			if !isSearchResource(t) {
+				httpError(w, r, http.StatusBadRequest, "search.unknown_type", t)
+				return
+			}
+			opts.Resources = append(opts.Resources, t)
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > 100 {
+			httpError(w, r, http.StatusBadRequest, "request.limit_range")
+			return
+		}
+		opts.Limit = n
+	}
+
+	if !Search.Loaded() {
+		w.Header().Set("Retry-After", "5")
+		httpError(w, r, http.StatusServiceUnavailable, "search.loading")
+		return
+	}
+	hits := Search.Search(q, opts)
+	if hits == nil {
+		hits = []search.Hit{}
+	}
+	writeResponse(w, r, http.StatusOK, searchResponse{Query: q, Hits: hits})
+}
+
+func isSearchResource(name string) bool {
+	for _, source := range searchSources {
+		if source.resource == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFilter - умова "поле містить value без урахування регістру" для
+// фільтрів списків. Введення екрануємо: інакше ".*" чи "(a+)+" з query
+// виконувалися б як регулярний вираз.
+func containsFilter(value string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(value), "$options": "i"}
+}
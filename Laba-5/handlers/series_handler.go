@@ -0,0 +1,542 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/models"
+	"hospital-api/recurrence"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// seriesZone - повтори зберігають київський час прийому й після переходу на літній час
+var seriesZone = rosterRules.Location
+
+// Серії повторюваних записів: читання - reader і admin, зміни - лише admin
+func SeriesRoutes(router *Router) {
+	admin := RequireRole("admin")
+	scope := Param{Name: "scope", Description: "this (default) changes only this occurrence, following - it and the rest of the series"}
+
+	series := router.Group("/appointment-series", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	series.HandleFunc(http.MethodGet, "", listSeries).
+		Describe("List recurring appointment series").Query(seriesParams...).Returns([]models.AppointmentSeries{})
+	series.HandleFunc(http.MethodPost, "", createSeries, admin).
+		Describe("Create a series from an RRULE and book all its appointments").
+		Query(Param{Name: "dryRun", Type: "boolean", Description: "Return the expanded appointments without booking them"}).
+		Accepts(models.AppointmentSeries{}).Returns(seriesResult{})
+	series.HandleFunc(http.MethodGet, "/{id}", getSeries).
+		Describe("Get a series with its appointments").Returns(seriesResult{})
+	series.HandleFunc(http.MethodPut, "/{id}/occurrences/{appointmentId}", updateOccurrence, admin).
+		Describe("Reschedule one occurrence or the rest of the series").Query(scope).
+		Accepts(models.OccurrenceChange{}).Returns(seriesResult{})
+	series.HandleFunc(http.MethodPost, "/{id}/occurrences/{appointmentId}/cancel", cancelOccurrence, admin).
+		Describe("Cancel one occurrence or the rest of the series").Query(scope).
+		Accepts(models.AppointmentCancellation{}).Returns(seriesResult{})
+}
+
+// seriesResult - серія разом з її живими записами за датою
+type seriesResult struct {
+	Series       models.AppointmentSeries `json:"series"`
+	Appointments []models.Appointment     `json:"appointments"`
+}
+
+// seriesRejection - відповідь 409: дати, на які лікар уже зайнятий
+type seriesRejection struct {
+	Error     string      `json:"error"`
+	Conflicts []time.Time `json:"conflicts"`
+}
+
+// Query-параметри seriesFilter для документації
+var seriesParams = []Param{
+	{Name: "patientId", Description: "Patient ObjectID"},
+	{Name: "doctorId", Description: "Doctor ObjectID"},
+}
+
+func seriesFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	addIDFilter(filter, "patientId", query.Get("patientId"))
+	addIDFilter(filter, "doctorId", query.Get("doctorId"))
+	return filter
+}
+
+func listSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := context.TODO()
+	cursor, err := db.Collection("appointment_series").Find(ctx, seriesFilter(r.URL.Query()),
+		options.Find().SetSort(bson.M{"start": 1}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	series := []models.AppointmentSeries{}
+	if err := cursor.All(ctx, &series); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, series)
+}
+
+func getSeries(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	writeSeries(w, r, http.StatusOK, objID)
+}
+
+// writeSeries відповідає серією з її записами
+func writeSeries(w http.ResponseWriter, r *http.Request, status int, id primitive.ObjectID) {
+	ctx := context.TODO()
+	var series models.AppointmentSeries
+	if err := db.Collection("appointment_series").FindOne(ctx, bson.M{"_id": id}).Decode(&series); err != nil {
+		httpError(w, r, http.StatusNotFound, "series.not_found")
+		return
+	}
+	appointments, err := findSeriesAppointments(ctx, alive(bson.M{"seriesId": id}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, status, seriesResult{Series: series, Appointments: appointments})
+}
+
+func findSeriesAppointments(ctx context.Context, filter bson.M) ([]models.Appointment, error) {
+	cursor, err := db.Collection("appointments").Find(ctx, filter, options.Find().SetSort(bson.M{"date": 1}))
+	if err != nil {
+		return nil, err
+	}
+	appointments := []models.Appointment{}
+	err = cursor.All(ctx, &appointments)
+	return appointments, err
+}
+
+func createSeries(w http.ResponseWriter, r *http.Request) {
+	var series models.AppointmentSeries
+	if err := json.NewDecoder(r.Body).Decode(&series); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := series.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+	rule, _ := recurrence.Parse(series.RRule)
+	series.RRule = rule.String()
+	dates, err := rule.Expand(series.Start, seriesZone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.TODO()
+	conflicts, err := seriesConflicts(ctx, series.DoctorID, dates, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(conflicts) > 0 {
+		writeResponse(w, r, http.StatusConflict, seriesRejection{Error: tr(r, "series.conflicts", len(conflicts)), Conflicts: conflicts})
+		return
+	}
+
+	series.ID = primitive.NewObjectID()
+	appointments := seriesAppointments(series, dates)
+	if r.URL.Query().Get("dryRun") == "true" {
+		writeResponse(w, r, http.StatusOK, seriesResult{Series: series, Appointments: appointments})
+		return
+	}
+
+	err = db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		if err := insertSeries(u, series); err != nil {
+			return err
+		}
+		for i := range appointments {
+			if err := bookIn(u, &appointments[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if key, ok := bookingConflict(err); ok {
+		httpError(w, r, http.StatusConflict, key)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, seriesResult{Series: series, Appointments: appointments})
+}
+
+func insertSeries(u *db.UnitOfWork, series models.AppointmentSeries) error {
+	col := db.Collection("appointment_series")
+	return u.Step(func(ctx context.Context) error {
+		_, err := col.InsertOne(ctx, series)
+		return err
+	}, func(ctx context.Context) error {
+		_, err := col.DeleteOne(ctx, bson.M{"_id": series.ID})
+		return err
+	})
+}
+
+// setSeriesRule змінює правило серії; undo повертає попереднє
+func setSeriesRule(u *db.UnitOfWork, series models.AppointmentSeries, rrule string) error {
+	col := db.Collection("appointment_series")
+	return u.Step(func(ctx context.Context) error {
+		_, err := col.UpdateByID(ctx, series.ID, bson.M{"$set": bson.M{"rrule": rrule}})
+		return err
+	}, func(ctx context.Context) error {
+		_, err := col.UpdateByID(ctx, series.ID, bson.M{"$set": bson.M{"rrule": series.RRule}})
+		return err
+	})
+}
+
+// seriesAppointments - записи серії на дати dates (ще без ID)
+func seriesAppointments(series models.AppointmentSeries, dates []time.Time) []models.Appointment {
+	appointments := make([]models.Appointment, len(dates))
+	for i, date := range dates {
+		appointments[i] = models.Appointment{
+			PatientID:            series.PatientID,
+			DoctorID:             series.DoctorID,
+			Date:                 date.UTC(),
+			SeriesID:             &series.ID,
+			AppointmentLifecycle: models.AppointmentLifecycle{Status: models.AppointmentBooked},
+		}
+	}
+	return appointments
+}
+
+// seriesConflicts - дати, ближче за appointmentSlot до яких у лікаря вже
+// є живий запис або блок недоступності. Двома запитами на всю серію, щоб
+// відповісти всім списком, а не першою датою; остаточно слот перевіряє
+// bookIn у транзакції. exclude - записи, які серія замінює.
+func seriesConflicts(ctx context.Context, doctorID primitive.ObjectID, dates []time.Time, exclude []primitive.ObjectID) ([]time.Time, error) {
+	if len(dates) == 0 {
+		return nil, nil
+	}
+	filter := alive(bson.M{
+		"doctorId": doctorID,
+		"status":   bson.M{"$nin": releasedStatuses},
+		"date": bson.M{
+			"$gt": dates[0].Add(-appointmentSlot),
+			"$lt": dates[len(dates)-1].Add(appointmentSlot),
+		},
+	})
+	if len(exclude) > 0 {
+		filter["_id"] = bson.M{"$nin": exclude}
+	}
+	busy, err := findSeriesAppointments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := findUnavailability(ctx, bson.M{
+		"doctorId": doctorID,
+		"start":    bson.M{"$lt": dates[len(dates)-1].Add(appointmentSlot)},
+		"end":      bson.M{"$gt": dates[0]},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []time.Time{}
+	for _, date := range dates {
+		if seriesClash(date, busy, blocks) {
+			conflicts = append(conflicts, date)
+		}
+	}
+	return conflicts, nil
+}
+
+func seriesClash(date time.Time, busy []models.Appointment, blocks []models.Unavailability) bool {
+	for _, a := range busy {
+		if a.Date.After(date.Add(-appointmentSlot)) && a.Date.Before(date.Add(appointmentSlot)) {
+			return true
+		}
+	}
+	for _, b := range blocks {
+		if b.Start.Before(date.Add(appointmentSlot)) && b.End.After(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrenceRequest - спільний початок PUT і cancel: ідентифікатори з
+// шляху та scope; сама серія й запис завантажуються після перевірки тіла
+func occurrenceRequest(w http.ResponseWriter, r *http.Request) (seriesID, appointmentID primitive.ObjectID, scope string, ok bool) {
+	if seriesID, ok = pathID(w, r); !ok {
+		return
+	}
+	appointmentID, err := primitive.ObjectIDFromHex(r.PathValue("appointmentId"))
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.invalid_id")
+		return seriesID, appointmentID, "", false
+	}
+	switch scope = r.URL.Query().Get("scope"); scope {
+	case "":
+		scope = "this"
+	case "this", "following":
+	default:
+		httpError(w, r, http.StatusBadRequest, "series.invalid_scope")
+		return seriesID, appointmentID, "", false
+	}
+	return seriesID, appointmentID, scope, true
+}
+
+// loadOccurrence - серія і її живий запис
+func loadOccurrence(w http.ResponseWriter, r *http.Request, seriesID, appointmentID primitive.ObjectID) (models.AppointmentSeries, models.Appointment, bool) {
+	ctx := context.TODO()
+	var series models.AppointmentSeries
+	var appointment models.Appointment
+	if err := db.Collection("appointment_series").FindOne(ctx, bson.M{"_id": seriesID}).Decode(&series); err != nil {
+		httpError(w, r, http.StatusNotFound, "series.not_found")
+		return series, appointment, false
+	}
+	err := db.Collection("appointments").FindOne(ctx, alive(bson.M{"_id": appointmentID, "seriesId": seriesID})).Decode(&appointment)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "appointment.not_found")
+		return series, appointment, false
+	}
+	return series, appointment, true
+}
+
+// updateOccurrence переносить один запис серії (scope=this) або
+// розділяє серію: стара закінчується перед цим записом, а решта
+// бронюється новою серією з новим лікарем, часом чи правилом
+func updateOccurrence(w http.ResponseWriter, r *http.Request) {
+	seriesID, appointmentID, scope, ok := occurrenceRequest(w, r)
+	if !ok {
+		return
+	}
+	var change models.OccurrenceChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := change.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+	series, appointment, ok := loadOccurrence(w, r, seriesID, appointmentID)
+	if !ok {
+		return
+	}
+	if appointment.State() != models.AppointmentBooked {
+		httpError(w, r, http.StatusConflict, "appointment.not_booked", appointment.State())
+		return
+	}
+
+	ctx := context.TODO()
+	var err error
+	result := series.ID
+	if scope == "this" {
+		err = moveOccurrence(ctx, appointment, change)
+	} else {
+		result, err = splitSeries(w, r, series, appointment, change)
+	}
+	if key, ok := bookingConflict(err); ok {
+		httpError(w, r, http.StatusConflict, key)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.IsZero() {
+		return
+	}
+	writeSeries(w, r, http.StatusOK, result)
+}
+
+// moveOccurrence змінює лікаря чи час одного запису з перевіркою слоту
+func moveOccurrence(ctx context.Context, appointment models.Appointment, change models.OccurrenceChange) error {
+	moved := appointment
+	if !change.DoctorID.IsZero() {
+		moved.DoctorID = change.DoctorID
+	}
+	if !change.Date.IsZero() {
+		moved.Date = change.Date
+	}
+	return rescheduleAppointment(ctx, appointment, moved)
+}
+
+// splitSeries бронює решту серії новою серією. Заброньовані записи від
+// appointment і далі видаляються (м'яко), решта станів лишається як є.
+// Нульовий ID без помилки означає, що відповідь уже записано.
+func splitSeries(w http.ResponseWriter, r *http.Request, series models.AppointmentSeries, appointment models.Appointment, change models.OccurrenceChange) (primitive.ObjectID, error) {
+	ctx := context.TODO()
+	rule, err := recurrence.Parse(series.RRule)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	dates, err := rule.Expand(series.Start, seriesZone)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	before := 0
+	for before < len(dates) && dates[before].Before(appointment.Date) {
+		before++
+	}
+
+	rest := rule.Skip(before)
+	if change.RRule != "" {
+		rest, _ = recurrence.Parse(change.RRule)
+	}
+	next := models.AppointmentSeries{
+		ID:        primitive.NewObjectID(),
+		PatientID: series.PatientID,
+		DoctorID:  series.DoctorID,
+		Start:     appointment.Date,
+		RRule:     rest.String(),
+	}
+	if !change.DoctorID.IsZero() {
+		next.DoctorID = change.DoctorID
+	}
+	if !change.Date.IsZero() {
+		next.Start = change.Date
+	}
+	if err := next.Validate(); err != nil {
+		validationError(w, r, err)
+		return primitive.NilObjectID, nil
+	}
+	nextDates, err := rest.Expand(next.Start, seriesZone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return primitive.NilObjectID, nil
+	}
+
+	replaced, err := findSeriesAppointments(ctx, alive(bson.M{
+		"seriesId": series.ID,
+		"date":     bson.M{"$gte": appointment.Date},
+		"status":   statusFilter(models.AppointmentBooked),
+	}))
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	ids := make([]primitive.ObjectID, len(replaced))
+	for i, a := range replaced {
+		ids[i] = a.ID
+	}
+
+	conflicts, err := seriesConflicts(ctx, next.DoctorID, nextDates, ids)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if len(conflicts) > 0 {
+		writeResponse(w, r, http.StatusConflict, seriesRejection{Error: tr(r, "series.conflicts", len(conflicts)), Conflicts: conflicts})
+		return primitive.NilObjectID, nil
+	}
+
+	appointments := seriesAppointments(next, nextDates)
+	by := claimsFrom(r.Context()).Username
+	err = db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		if err := setSeriesRule(u, series, rule.EndBefore(appointment.Date).String()); err != nil {
+			return err
+		}
+		col := db.Collection("appointments")
+		err := u.Step(func(ctx context.Context) error {
+			_, err := col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{
+				"deletedAt": time.Now().UTC(),
+				"deletedBy": by,
+			}})
+			return err
+		}, func(ctx context.Context) error {
+			_, err := col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err := insertSeries(u, next); err != nil {
+			return err
+		}
+		for i := range appointments {
+			if err := bookIn(u, &appointments[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return next.ID, err
+}
+
+// cancelOccurrence скасовує один запис серії (scope=this) або його й усі
+// наступні; тоді серія обрізається, щоб не відновлювати скасоване
+func cancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	seriesID, appointmentID, scope, ok := occurrenceRequest(w, r)
+	if !ok {
+		return
+	}
+	var cancel models.AppointmentCancellation
+	if err := json.NewDecoder(r.Body).Decode(&cancel); err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.invalid")
+		return
+	}
+	if err := cancel.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+	series, appointment, ok := loadOccurrence(w, r, seriesID, appointmentID)
+	if !ok {
+		return
+	}
+
+	transition := cancelTransition()
+	filter := alive(bson.M{"seriesId": series.ID, "status": statusFilter(transition.From...)})
+	set := bson.M{
+		"status":         transition.To,
+		transition.Field: time.Now().UTC(),
+		"cancelReason":   strings.TrimSpace(cancel.Reason),
+	}
+
+	ctx := context.TODO()
+	col := db.Collection("appointments")
+	var err error
+	if scope == "this" {
+		if !transition.Allows(appointment.Status) {
+			httpError(w, r, http.StatusConflict, "appointment.invalid_transition", transition.Action, appointment.State())
+			return
+		}
+		filter["_id"] = appointment.ID
+		_, err = col.UpdateOne(ctx, filter, bson.M{"$set": set})
+	} else {
+		filter["date"] = bson.M{"$gte": appointment.Date}
+		rule, parseErr := recurrence.Parse(series.RRule)
+		if parseErr != nil {
+			http.Error(w, parseErr.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Скасування - останній крок, тож відкочувати доводиться лише правило
+		err = db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+			if err := setSeriesRule(u, series, rule.EndBefore(appointment.Date).String()); err != nil {
+				return err
+			}
+			return u.Step(func(ctx context.Context) error {
+				_, err := col.UpdateMany(ctx, filter, bson.M{"$set": set})
+				return err
+			}, nil)
+		})
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeSeries(w, r, http.StatusOK, series.ID)
+}
+
+// cancelTransition - перехід у cancelled з таблиці станів
+func cancelTransition() models.AppointmentTransition {
+	for _, t := range models.AppointmentTransitions {
+		if t.To == models.AppointmentCancelled {
+			return t
+		}
+	}
+	panic("no transition to " + models.AppointmentCancelled)
+}
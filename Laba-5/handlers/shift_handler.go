@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/i18n"
+	"hospital-api/models"
+	"hospital-api/scheduling"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rosterRules - норми графіка; години змін задано за київським часом
+var rosterRules = func() scheduling.Rules {
+	rules := scheduling.DefaultRules
+	if loc, err := time.LoadLocation("Europe/Kyiv"); err == nil {
+		rules.Location = loc
+	}
+	return rules
+}()
+
+// Зміни та графік: читання - reader і admin, зміни - лише admin
+func ShiftRoutes(router *Router) {
+	admin := RequireRole("admin")
+
+	shifts := router.Group("/shifts", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	shifts.HandleFunc(http.MethodGet, "", listShifts).
+		Describe("List shift definitions").Query(shiftParams...).Query(includeDeletedParam).Returns([]models.Shift{})
+	shifts.HandleFunc(http.MethodPost, "", createShift, admin).
+		Describe("Define a department shift").Accepts(models.Shift{}).Returns(models.Shift{})
+	shifts.HandleFunc(http.MethodGet, "/{id}", getShift).
+		Describe("Get a shift definition").Query(includeDeletedParam).Returns(models.Shift{})
+	shifts.HandleFunc(http.MethodPut, "/{id}", updateShift, admin).
+		Describe("Update a shift definition").Accepts(models.Shift{})
+	shifts.HandleFunc(http.MethodDelete, "/{id}", deleteShift, admin).
+		Describe("Soft-delete a shift definition")
+	shifts.HandleFunc(http.MethodPost, "/{id}/restore", restoreHandler("shifts", "shift.not_in_trash"), admin).
+		Describe("Restore a soft-deleted shift definition")
+
+	roster := router.Group("/roster", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	roster.HandleFunc(http.MethodGet, "", listRoster).
+		Describe("List roster assignments").Query(rosterParams...).Returns([]models.RosterEntry{})
+	roster.HandleFunc(http.MethodPost, "", assignShift, admin).
+		Describe("Assign a staff member to a dated shift").
+		Query(Param{Name: "force", Type: "boolean", Description: "Save even if rest or weekly hour rules are broken"}).
+		Accepts(rosterRequest{}).Returns(models.RosterEntry{})
+	// Призначення - план, а не медичний запис, тому видаляється назовсім
+	roster.HandleFunc(http.MethodDelete, "/{id}", deleteRosterEntry, admin).
+		Describe("Remove a roster assignment")
+	roster.HandleFunc(http.MethodGet, "/check", checkRoster).
+		Describe("Check rest, weekly hours and role coverage of a department roster").
+		Query(rosterParams[1:]...).Returns([]scheduling.Violation{})
+	roster.HandleFunc(http.MethodPost, "/generate", generateRoster, admin).
+		Describe("Propose and save a fair weekly roster for a department").
+		Query(
+			Param{Name: "departmentId", Description: "Department ObjectID (required)"},
+			Param{Name: "week", Format: "date", Description: "Any day of the week to plan, default is the current week"},
+			Param{Name: "dryRun", Type: "boolean", Description: "Return the proposal without saving it"},
+		).Returns(generatedRoster{})
+}
+
+func listShifts(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, shiftFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	shifts, err := findShifts(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, shifts)
+}
+
+func findShifts(ctx context.Context, filter bson.M) ([]models.Shift, error) {
+	cursor, err := db.Collection("shifts").Find(ctx, filter, options.Find().SetSort(bson.M{"start": 1}))
+	if err != nil {
+		return nil, err
+	}
+	shifts := []models.Shift{}
+	err = cursor.All(ctx, &shifts)
+	return shifts, err
+}
+
+// decodeShift читає і перевіряє зміну, беручи лікарню з відділення
+func decodeShift(w http.ResponseWriter, r *http.Request) (models.Shift, bool) {
+	var shift models.Shift
+	if err := json.NewDecoder(r.Body).Decode(&shift); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return shift, false
+	}
+	if err := shift.Validate(); err != nil {
+		validationError(w, r, err)
+		return shift, false
+	}
+
+	var department models.Department
+	err := db.Collection("departments").FindOne(context.TODO(), alive(bson.M{"_id": shift.DepartmentID})).Decode(&department)
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "department.not_found")
+		return shift, false
+	}
+	shift.HospitalID = department.HospitalID
+	shift.SoftDelete = models.SoftDelete{}
+	return shift, true
+}
+
+func createShift(w http.ResponseWriter, r *http.Request) {
+	shift, ok := decodeShift(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := db.Collection("shifts").InsertOne(context.TODO(), shift)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	shift.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusCreated, shift)
+}
+
+func getShift(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+
+	var shift models.Shift
+	if err := db.Collection("shifts").FindOne(context.TODO(), filter).Decode(&shift); err != nil {
+		httpError(w, r, http.StatusNotFound, "shift.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, shift)
+}
+
+// Оновлення не змінює вже складений графік: призначення зберігають свій час
+func updateShift(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	shift, ok := decodeShift(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := db.Collection("shifts").UpdateOne(context.TODO(), alive(bson.M{"_id": objID}), bson.M{"$set": bson.M{
+		"name":          shift.Name,
+		"hospital_id":   shift.HospitalID,
+		"department_id": shift.DepartmentID,
+		"start":         shift.Start,
+		"end":           shift.End,
+		"coverage":      shift.Coverage,
+	}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.MatchedCount == 0 {
+		httpError(w, r, http.StatusNotFound, "shift.not_found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Видалена зміна зникає з генератора і перевірок, а вже складений
+// графік лишається як є
+func deleteShift(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := softDelete(context.TODO(), r, "shifts", bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		httpError(w, r, http.StatusNotFound, "shift.not_found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- Графік ---
+
+// rosterRequest - призначення працівника на зміну в день date (YYYY-MM-DD)
+type rosterRequest struct {
+	StaffID primitive.ObjectID `json:"staffId"`
+	ShiftID primitive.ObjectID `json:"shiftId"`
+	Date    string             `json:"date"`
+}
+
+// rosterRejection - відповідь 422, коли призначення порушує норми
+type rosterRejection struct {
+	Error      string                 `json:"error"`
+	Violations []scheduling.Violation `json:"violations"`
+}
+
+type generatedRoster struct {
+	WeekStart  string                 `json:"weekStart"`
+	DryRun     bool                   `json:"dryRun"`
+	Entries    []models.RosterEntry   `json:"entries"`
+	Violations []scheduling.Violation `json:"violations"`
+}
+
+func listRoster(w http.ResponseWriter, r *http.Request) {
+	filter, err := rosterFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, err := findRoster(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, entries)
+}
+
+func findRoster(ctx context.Context, filter bson.M) ([]models.RosterEntry, error) {
+	cursor, err := db.Collection("roster").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	entries := []models.RosterEntry{}
+	err = cursor.All(ctx, &entries)
+	return entries, err
+}
+
+func assignShift(w http.ResponseWriter, r *http.Request) {
+	var req rosterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := (models.RosterEntry{StaffID: req.StaffID, ShiftID: req.ShiftID, Date: req.Date}).Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+
+	ctx := context.TODO()
+	var shift models.Shift
+	if err := db.Collection("shifts").FindOne(ctx, alive(bson.M{"_id": req.ShiftID})).Decode(&shift); err != nil {
+		httpError(w, r, http.StatusBadRequest, "shift.not_found")
+		return
+	}
+	var staffMember models.Staff
+	if err := db.Collection("staff").FindOne(ctx, alive(bson.M{"_id": req.StaffID})).Decode(&staffMember); err != nil {
+		httpError(w, r, http.StatusBadRequest, "staff.not_found")
+		return
+	}
+	if staffMember.DepartmentID != shift.DepartmentID {
+		httpError(w, r, http.StatusBadRequest, "roster.wrong_department")
+		return
+	}
+
+	day, _ := rosterRules.Day(req.Date)
+	entry, err := rosterRules.Entry(shift, staffMember, day)
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "roster.shift_time")
+		return
+	}
+
+	err = db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		return assignIn(u, &entry, day, r.URL.Query().Get("force") == "true")
+	})
+	var rejected rosterRejected
+	switch {
+	case errors.As(err, &rejected):
+		writeResponse(w, r, http.StatusUnprocessableEntity, rosterRejection{
+			Error:      tr(r, "roster.rejected"),
+			Violations: scheduling.Localize(rejected.violations, i18n.Lang(r.Context())),
+		})
+		return
+	case mongo.IsDuplicateKeyError(err):
+		httpError(w, r, http.StatusConflict, "roster.duplicate")
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, entry)
+}
+
+// rosterRejected - призначення порушує норми і без force не зберігається
+type rosterRejected struct {
+	violations []scheduling.Violation
+}
+
+func (e rosterRejected) Error() string { return "roster assignment breaks the rules" }
+
+// assignIn вставляє призначення і вже після вставки перевіряє норми за
+// тиждень довкола, як bookIn перевіряє слот: два одночасні призначення
+// бачать одне одного, і порушення відкочує вставку
+func assignIn(u *db.UnitOfWork, entry *models.RosterEntry, day time.Time, force bool) error {
+	if err := lockStaff(u, entry.StaffID); err != nil {
+		return err
+	}
+
+	roster := db.Collection("roster")
+	entry.ID = primitive.NewObjectID()
+	err := u.Step(func(ctx context.Context) error {
+		_, err := roster.InsertOne(ctx, entry)
+		return err
+	}, func(ctx context.Context) error {
+		_, err := roster.DeleteOne(ctx, bson.M{"_id": entry.ID})
+		return err
+	})
+	if err != nil || force {
+		return err
+	}
+	return u.Step(func(ctx context.Context) error {
+		// Для норм вистачає призначень цього працівника за тиждень довкола
+		existing, err := findRoster(ctx, bson.M{"_id": bson.M{"$ne": entry.ID}, "staffId": entry.StaffID, "date": bson.M{
+			"$gte": day.AddDate(0, 0, -7).Format(time.DateOnly),
+			"$lte": day.AddDate(0, 0, 7).Format(time.DateOnly),
+		}})
+		if err != nil {
+			return err
+		}
+		if violations := scheduling.CheckAssignment(rosterRules, *entry, existing); len(violations) > 0 {
+			return rosterRejected{violations: violations}
+		}
+		return nil
+	}, nil)
+}
+
+// lockStaff - запис у спільний документ працівника, як lockDoctor
+func lockStaff(u *db.UnitOfWork, staffID primitive.ObjectID) error {
+	if !u.Transactional() {
+		return nil
+	}
+	return u.Step(func(ctx context.Context) error {
+		_, err := db.Collection("roster_locks").UpdateByID(ctx, staffID,
+			bson.M{"$inc": bson.M{"seq": 1}}, options.Update().SetUpsert(true))
+		return err
+	}, nil)
+}
+
+func deleteRosterEntry(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := db.Collection("roster").DeleteOne(context.TODO(), bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.DeletedCount == 0 {
+		httpError(w, r, http.StatusNotFound, "roster.not_found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func checkRoster(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	departmentID, err := primitive.ObjectIDFromHex(query.Get("departmentId"))
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.department_id_required")
+		return
+	}
+	from, to, err := rosterPeriod(query.Get("from"), query.Get("to"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.TODO()
+	shifts, err := findShifts(ctx, alive(bson.M{"department_id": departmentID}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries, err := findRoster(ctx, bson.M{"department_id": departmentID, "date": bson.M{
+		"$gte": from.Format(time.DateOnly),
+		"$lt":  to.Format(time.DateOnly),
+	}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	violations := scheduling.Check(rosterRules, shifts, entries, from, to)
+	writeResponse(w, r, http.StatusOK, scheduling.Localize(violations, i18n.Lang(r.Context())))
+}
+
+// rosterPeriod розбирає from/to (YYYY-MM-DD, to включно); за замовчуванням - поточний тиждень
+func rosterPeriod(fromValue, toValue string) (time.Time, time.Time, error) {
+	from := rosterRules.WeekStart(time.Now())
+	if fromValue != "" {
+		day, err := rosterRules.Day(fromValue)
+		if err != nil {
+			return from, from, err
+		}
+		from = day
+	}
+	to := from.AddDate(0, 0, 7)
+	if toValue != "" {
+		day, err := rosterRules.Day(toValue)
+		if err != nil {
+			return from, to, err
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+func generateRoster(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	departmentID, err := primitive.ObjectIDFromHex(query.Get("departmentId"))
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "request.department_id_required")
+		return
+	}
+	monday := rosterRules.WeekStart(time.Now())
+	if week := query.Get("week"); week != "" {
+		day, err := rosterRules.Day(week)
+		if err != nil {
+			httpError(w, r, http.StatusBadRequest, "roster.week_format")
+			return
+		}
+		monday = rosterRules.WeekStart(day)
+	}
+	dryRun := query.Get("dryRun") == "true"
+
+	ctx := context.TODO()
+	shifts, err := findShifts(ctx, alive(bson.M{"department_id": departmentID}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cursor, err := db.Collection("staff").Find(ctx, alive(bson.M{"department_id": departmentID}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var staff []models.Staff
+	if err := cursor.All(ctx, &staff); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// День до і після тижня потрібні, щоб не порушити відпочинок на межах
+	ids := make([]primitive.ObjectID, len(staff))
+	for i, s := range staff {
+		ids[i] = s.ID
+	}
+	existing, err := findRoster(ctx, bson.M{"staffId": bson.M{"$in": ids}, "date": bson.M{
+		"$gte": monday.AddDate(0, 0, -1).Format(time.DateOnly),
+		"$lte": monday.AddDate(0, 0, 7).Format(time.DateOnly),
+	}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	entries, violations := scheduling.Generate(rosterRules, shifts, staff, existing, monday)
+	if !dryRun && len(entries) > 0 {
+		docs := make([]interface{}, len(entries))
+		for i := range entries {
+			entries[i].ID = primitive.NewObjectID()
+			docs[i] = entries[i]
+		}
+		if _, err := db.Collection("roster").InsertMany(ctx, docs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if entries == nil {
+		entries = []models.RosterEntry{}
+	}
+	writeResponse(w, r, http.StatusOK, generatedRoster{
+		WeekStart:  monday.Format(time.DateOnly),
+		DryRun:     dryRun,
+		Entries:    entries,
+		Violations: scheduling.Localize(violations, i18n.Lang(r.Context())),
+	})
+}
+
+// Query-параметри shiftFilter для документації
+var shiftParams = []Param{
+	{Name: "hospitalId", Description: "Hospital ObjectID"},
+	{Name: "departmentId", Description: "Department ObjectID"},
+}
+
+func shiftFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	addIDFilter(filter, "hospital_id", query.Get("hospitalId"))
+	addIDFilter(filter, "department_id", query.Get("departmentId"))
+	return filter
+}
+
+// Query-параметри rosterFilter для документації; перевірка графіка
+// приймає всі, крім першого (staffId)
+var rosterParams = []Param{
+	{Name: "staffId", Description: "Staff member ObjectID"},
+	{Name: "departmentId", Description: "Department ObjectID"},
+	{Name: "from", Format: "date", Description: "First day, YYYY-MM-DD"},
+	{Name: "to", Format: "date", Description: "Last day (inclusive), YYYY-MM-DD"},
+}
+
+func rosterFilter(query url.Values) (bson.M, error) {
+	filter := bson.M{}
+	addIDFilter(filter, "staffId", query.Get("staffId"))
+	addIDFilter(filter, "department_id", query.Get("departmentId"))
+
+	dates := bson.M{}
+	for op, value := range map[string]string{"$gte": query.Get("from"), "$lte": query.Get("to")} {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, err := rosterRules.Day(value); err != nil {
+			return nil, err
+		}
+		dates[op] = value
+	}
+	if len(dates) > 0 {
+		filter["date"] = dates
+	}
+	return filter, nil
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// M'яке видалення: DELETE лише позначає документ полями deletedAt/deletedBy
+// (models.SoftDelete), читання їх пропускає, а адміністратор може
+// подивитися видалені через ?includeDeleted=true і відновити їх.
+
+var includeDeletedParam = Param{
+	Name: "includeDeleted", Type: "boolean",
+	Description: "Also return soft-deleted records (admin bearer token required)",
+}
+
+// notDeleted - умова "документ не видалено" для $match і $lookup
+func notDeleted() bson.M {
+	return bson.M{"deletedAt": bson.M{"$exists": false}}
+}
+
+// alive додає до фільтра умову "не видалено" і повертає його ж
+func alive(filter bson.M) bson.M {
+	filter["deletedAt"] = bson.M{"$exists": false}
+	return filter
+}
+
+// visible - фільтр для читання: без видалених, якщо адміністратор не
+// попросив ?includeDeleted=true. Для інших ролей відповідає 403 і повертає false.
+func visible(w http.ResponseWriter, r *http.Request, filter bson.M) (bson.M, bool) {
+	if r.URL.Query().Get("includeDeleted") != "true" {
+		return alive(filter), true
+	}
+	if claims := requestClaims(r); claims == nil || claims.Role != "admin" {
+		httpError(w, r, http.StatusForbidden, "request.include_deleted_admin")
+		return nil, false
+	}
+	return filter, true
+}
+
+// actor - хто виконує запит, для deletedBy
+func actor(r *http.Request) string {
+	if claims := requestClaims(r); claims != nil {
+		return claims.Username
+	}
+	if hasAPIKey(r) {
+		return "api-key"
+	}
+	return "anonymous"
+}
+
+// softDelete позначає видаленим документ за filter. Повертає false,
+// якщо живого документа не знайшлося.
+func softDelete(ctx context.Context, r *http.Request, collection string, filter bson.M) (bool, error) {
+	return softDeleteBy(ctx, collection, filter, actor(r))
+}
+
+// softDeleteBy - softDelete з уже відомим виконавцем (для gRPC, де немає *http.Request)
+func softDeleteBy(ctx context.Context, collection string, filter bson.M, by string) (bool, error) {
+	res, err := db.Collection(collection).UpdateOne(ctx, alive(filter), bson.M{"$set": bson.M{
+		"deletedAt": time.Now().UTC(),
+		"deletedBy": by,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// restore знімає позначку видалення; false - видаленого документа немає
+func restore(ctx context.Context, col *mongo.Collection, filter bson.M) (bool, error) {
+	filter["deletedAt"] = bson.M{"$exists": true}
+	res, err := col.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// restoreHandler - POST /{id}/restore; notFound - ключ повідомлення для 404
+func restoreHandler(collection, notFound string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		objID, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		found, err := restore(context.TODO(), db.Collection(collection), bson.M{"_id": objID})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			httpError(w, r, http.StatusNotFound, notFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// restoreRoute реєструє POST {prefix}/{id}/restore. Відновлення - лише для
+// адміністратора з JWT, навіть якщо сам ресурс захищено API-ключем.
+func restoreRoute(router *Router, prefix, name string, h http.HandlerFunc) {
+	group := router.Group(prefix, LoggingMiddleware, JWT("admin")).Secured(SecurityBearer)
+	group.HandleFunc(http.MethodPost, "/{id}/restore", h).
+		Describe("Restore a soft-deleted " + strings.ToLower(name))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"hospital-api/db"
@@ -14,138 +15,180 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// JWT + Logging Middleware додається тут
-func StaffRoutes() {
-	http.Handle("/staff", LoggingMiddleware(
-		JWTAuthMiddleware(http.HandlerFunc(staffHandler), "reader", "admin"),
-	))
-	http.Handle("/staff/", LoggingMiddleware(
-		JWTAuthMiddleware(http.HandlerFunc(staffMemberHandler), "reader", "admin"),
-	))
+// JWT + Logging Middleware для всієї групи; зміни - лише admin
+func StaffRoutes(router *Router) {
+	staff := router.Group("/staff", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	admin := RequireRole("admin")
+
+	staff.HandleFunc(http.MethodGet, "", listStaff).
+		Describe("List staff").Query(staffParams...).Query(includeDeletedParam).Query(expandParam("staff")).Returns([]models.Staff{})
+	staff.HandleFunc(http.MethodPost, "", createStaffMember, admin).
+		Describe("Create a staff member").Accepts(models.Staff{}).Returns(models.Staff{})
+	staff.HandleFunc(http.MethodGet, "/{id}", getStaffMember).
+		Describe("Get a staff member").Query(includeDeletedParam).Query(expandParam("staff")).Returns(models.Staff{})
+	staff.HandleFunc(http.MethodPut, "/{id}", updateStaffMember, admin).
+		Describe("Update a staff member").Accepts(models.Staff{})
+	staff.HandleFunc(http.MethodDelete, "/{id}", deleteStaffMember, admin).
+		Describe("Soft-delete a staff member")
+
+	restoreRoute(router, "/staff", "Staff member", restoreHandler("staff", "staff.not_in_trash"))
+
+	staff.Handle(http.MethodPost, "/import", importHandler[models.Staff]("staff"), admin).
+		Describe("Import staff from CSV or NDJSON").Query(importParams...).
+		Accepts([]models.Staff{}, "text/csv", "application/x-ndjson").Returns(importResult{})
+	staff.Handle(http.MethodGet, "/export", exportHandler[models.Staff]("staff", staffFilter)).
+		Describe("Export staff as CSV or NDJSON").Query(staffParams...).Query(exportParams...).
+		Returns([]models.Staff{}, "text/csv", "application/x-ndjson")
+
+	// Персонал лікарні - з тією ж JWT-авторизацією, що й /staff
+	byHospital := router.Group("/hospitals/{id}/staff", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	byHospital.HandleFunc(http.MethodGet, "", nestedList("hospitalId", listStaff)).
+		Describe("List staff of a hospital").Query(staffParams...).Query(includeDeletedParam).Query(expandParam("staff")).
+		Returns([]models.Staff{})
 }
 
-func staffHandler(w http.ResponseWriter, r *http.Request) {
-	claims := GetClaims(r) // для перевірки ролі при POST
-	col := db.Client.Database("hospital_db").Collection("staff")
-
-	switch r.Method {
-	case http.MethodGet:
-		filter := bson.M{}
-		query := r.URL.Query()
-		if name := strings.TrimSpace(query.Get("name")); name != "" {
-			filter["name"] = bson.M{"$regex": name, "$options": "i"}
-		}
-		if role := strings.TrimSpace(query.Get("role")); role != "" {
-			filter["role"] = bson.M{"$regex": role, "$options": "i"}
-		}
-		if shift := strings.TrimSpace(query.Get("shift")); shift != "" {
-			filter["shift"] = bson.M{"$regex": shift, "$options": "i"}
-		}
+func listStaff(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, staffFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Staff](w, r, "staff", filter, expand, false)
+		return
+	}
 
-		cursor, err := col.Find(context.TODO(), filter)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(context.TODO())
+	cursor, err := db.Collection("staff").Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
 
-		var staff []models.Staff
-		if err := cursor.All(context.TODO(), &staff); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writeJSON(w, staff)
+	var staff []models.Staff
+	if err := cursor.All(context.TODO(), &staff); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, staff)
+}
 
-	case http.MethodPost:
-		if claims.Role != "admin" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
+func createStaffMember(w http.ResponseWriter, r *http.Request) {
+	var staffMember models.Staff
+	if err := json.NewDecoder(r.Body).Decode(&staffMember); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	staffMember.SoftDelete = models.SoftDelete{} // видаляють лише через DELETE
 
-		var staffMember models.Staff
-		if err := json.NewDecoder(r.Body).Decode(&staffMember); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	res, err := db.Collection("staff").InsertOne(context.TODO(), staffMember)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	staffMember.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusOK, staffMember)
+}
 
-		res, err := col.InsertOne(context.TODO(), staffMember)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		staffMember.ID = res.InsertedID.(primitive.ObjectID)
-		writeJSON(w, staffMember)
+func getStaffMember(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+	if expand := r.URL.Query().Get("expand"); expand != "" {
+		respondExpanded[models.Staff](w, r, "staff", filter, expand, true)
+		return
+	}
 
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	var staffMember models.Staff
+	err := db.Collection("staff").FindOne(context.TODO(), filter).Decode(&staffMember)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "staff.not_found")
+		return
 	}
+	writeResponse(w, r, http.StatusOK, staffMember)
 }
 
-func staffMemberHandler(w http.ResponseWriter, r *http.Request) {
-	claims := GetClaims(r)
-	col := db.Client.Database("hospital_db").Collection("staff")
-	id := strings.TrimPrefix(r.URL.Path, "/staff/")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+func updateStaffMember(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
 		return
 	}
 
-	switch r.Method {
-	case http.MethodGet:
-		var staffMember models.Staff
-		err := col.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&staffMember)
-		if err != nil {
-			http.Error(w, "Staff member not found", http.StatusNotFound)
-			return
-		}
-		writeJSON(w, staffMember)
+	var update models.Staff
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	case http.MethodPut:
-		if claims.Role != "admin" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
+	updateMap := bson.M{
+		"name":          update.Name,
+		"role":          update.Role,
+		"shift":         update.Shift,
+		"hospital_id":   update.HospitalID,
+		"department_id": update.DepartmentID,
+	}
 
-		var update models.Staff
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	_, err := db.Collection("staff").UpdateOne(context.TODO(), alive(bson.M{"_id": objID}), bson.M{"$set": updateMap})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, tr(r, "staff.updated"))
+}
 
-		updateMap := bson.M{
-			"name":  update.Name,
-			"role":  update.Role,
-			"shift": update.Shift,
-		}
+func deleteStaffMember(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
 
-		_, err := col.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": updateMap})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		fmt.Fprintf(w, "Staff member updated successfully")
+	found, err := softDelete(context.TODO(), r, "staff", bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		httpError(w, r, http.StatusNotFound, "staff.not_found")
+		return
+	}
+	fmt.Fprint(w, tr(r, "staff.deleted"))
+}
 
-	case http.MethodDelete:
-		if claims.Role != "admin" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
+// Query-параметри staffFilter для документації
+var staffParams = []Param{
+	{Name: "name", Description: "Case-insensitive substring of the name"},
+	{Name: "role", Description: "Case-insensitive substring of the role"},
+	{Name: "shift", Description: "Case-insensitive substring of the shift"},
+	{Name: "hospitalId", Description: "Hospital ObjectID"},
+	{Name: "departmentId", Description: "Department ObjectID"},
+}
 
-		_, err := col.DeleteOne(context.TODO(), bson.M{"_id": objID})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+// Фільтр списку персоналу через query params
+func staffFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	if name := strings.TrimSpace(query.Get("name")); name != "" {
+		filter["name"] = containsFilter(name)
+	}
+	if role := strings.TrimSpace(query.Get("role")); role != "" {
+		filter["role"] = containsFilter(role)
+	}
+	if shift := strings.TrimSpace(query.Get("shift")); shift != "" {
+		filter["shift"] = containsFilter(shift)
+	}
+	if hospital := strings.TrimSpace(query.Get("hospitalId")); hospital != "" {
+		if objID, err := primitive.ObjectIDFromHex(hospital); err == nil {
+			filter["hospital_id"] = objID
+		}
+	}
+	if department := strings.TrimSpace(query.Get("departmentId")); department != "" {
+		if objID, err := primitive.ObjectIDFromHex(department); err == nil {
+			filter["department_id"] = objID
 		}
-		fmt.Fprintf(w, "Staff member deleted successfully")
-
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
-}
 
-// Допоміжна функція для JSON
-func writeJSON(w http.ResponseWriter, v interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(v)
+	return filter
 }
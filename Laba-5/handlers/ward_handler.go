@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hospital-api/admission"
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Палати та ліжка: читання - reader і admin, зміни - лише admin
+func WardRoutes(router *Router) {
+	admin := RequireRole("admin")
+
+	wards := router.Group("/wards", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	wards.HandleFunc(http.MethodGet, "", listWards).
+		Describe("List wards").Query(wardParams...).Query(includeDeletedParam).Returns([]models.Ward{})
+	wards.HandleFunc(http.MethodPost, "", createWard, admin).
+		Describe("Create a ward in a department").Accepts(models.Ward{}).Returns(models.Ward{})
+	wards.HandleFunc(http.MethodGet, "/{id}", getWard).
+		Describe("Get a ward").Query(includeDeletedParam).Returns(models.Ward{})
+	wards.HandleFunc(http.MethodDelete, "/{id}", deleteWard, admin).
+		Describe("Soft-delete a ward without beds")
+	wards.HandleFunc(http.MethodPost, "/{id}/restore", restoreHandler("wards", "ward.not_in_trash"), admin).
+		Describe("Restore a soft-deleted ward")
+
+	beds := router.Group("/beds", LoggingMiddleware, JWT("reader", "admin")).Secured(SecurityBearer)
+	beds.HandleFunc(http.MethodGet, "", listBeds).
+		Describe("List beds").Query(bedParams...).Query(includeDeletedParam).Returns([]models.Bed{})
+	beds.HandleFunc(http.MethodPost, "", createBed, admin).
+		Describe("Add a bed to a ward within its capacity").Accepts(models.Bed{}).Returns(models.Bed{})
+	beds.HandleFunc(http.MethodGet, "/{id}", getBed).
+		Describe("Get a bed").Query(includeDeletedParam).Returns(models.Bed{})
+	beds.HandleFunc(http.MethodDelete, "/{id}", deleteBed, admin).
+		Describe("Soft-delete a free bed")
+	beds.HandleFunc(http.MethodPost, "/{id}/restore", restoreBed, admin).
+		Describe("Restore a soft-deleted bed if its ward has room")
+}
+
+func listWards(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, wardFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	cursor, err := db.Collection("wards").Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	wards := []models.Ward{}
+	if err := cursor.All(context.TODO(), &wards); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, wards)
+}
+
+func createWard(w http.ResponseWriter, r *http.Request) {
+	var ward models.Ward
+	if err := json.NewDecoder(r.Body).Decode(&ward); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := ward.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+
+	// Лікарню беремо з відділення, щоб вони не розходилися
+	var department models.Department
+	err := db.Collection("departments").FindOne(context.TODO(), alive(bson.M{"_id": ward.DepartmentID})).Decode(&department)
+	if err != nil {
+		httpError(w, r, http.StatusBadRequest, "department.not_found")
+		return
+	}
+	ward.HospitalID = department.HospitalID
+	ward.Beds = 0
+	ward.SoftDelete = models.SoftDelete{}
+
+	res, err := db.Collection("wards").InsertOne(context.TODO(), ward)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ward.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusCreated, ward)
+}
+
+func getWard(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+
+	var ward models.Ward
+	if err := db.Collection("wards").FindOne(context.TODO(), filter).Decode(&ward); err != nil {
+		httpError(w, r, http.StatusNotFound, "ward.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, ward)
+}
+
+func deleteWard(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := softDelete(context.TODO(), r, "wards", bson.M{"_id": objID, "beds": 0})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		if exists(db.Collection("wards"), objID) {
+			httpError(w, r, http.StatusConflict, "ward.has_beds")
+		} else {
+			httpError(w, r, http.StatusNotFound, "ward.not_found")
+		}
+		return
+	}
+	fmt.Fprint(w, tr(r, "ward.deleted"))
+}
+
+func listBeds(w http.ResponseWriter, r *http.Request) {
+	filter, ok := visible(w, r, bedFilter(r.URL.Query()))
+	if !ok {
+		return
+	}
+	cursor, err := db.Collection("beds").Find(context.TODO(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	beds := []models.Bed{}
+	if err := cursor.All(context.TODO(), &beds); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, beds)
+}
+
+// createBed резервує місце в палаті атомарним $inc з умовою beds < capacity,
+// тому дві одночасні вставки не перевищать місткість
+func createBed(w http.ResponseWriter, r *http.Request) {
+	var bed models.Bed
+	if err := json.NewDecoder(r.Body).Decode(&bed); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := bed.Validate(); err != nil {
+		validationError(w, r, err)
+		return
+	}
+
+	err := db.RunInTransaction(context.TODO(), func(u *db.UnitOfWork) error {
+		var err error
+		bed, err = admission.AddBed(u, admissionStore{}, bed)
+		return err
+	})
+	if errors.Is(err, admission.ErrWardFull) {
+		if exists(db.Collection("wards"), bed.WardID) {
+			httpError(w, r, http.StatusConflict, "ward.full")
+		} else {
+			httpError(w, r, http.StatusBadRequest, "ward.not_found")
+		}
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, bed)
+}
+
+func getBed(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := visible(w, r, bson.M{"_id": objID})
+	if !ok {
+		return
+	}
+
+	var bed models.Bed
+	if err := db.Collection("beds").FindOne(context.TODO(), filter).Decode(&bed); err != nil {
+		httpError(w, r, http.StatusNotFound, "bed.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, bed)
+}
+
+func deleteBed(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	// Ліжко в кошик і лічильник палати - разом, інакше перевірка місткості
+	// в AddBed і restoreBed рахуватиме неіснуючі ліжка
+	err := db.RunInTransaction(context.TODO(), func(u *db.UnitOfWork) error {
+		_, err := admission.RemoveBed(u, admissionStore{}, objID, actor(r), time.Now().UTC())
+		return err
+	})
+	if errors.Is(err, admission.ErrBedNotFree) {
+		if exists(db.Collection("beds"), objID) {
+			httpError(w, r, http.StatusConflict, "bed.occupied")
+		} else {
+			httpError(w, r, http.StatusNotFound, "bed.not_found")
+		}
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, tr(r, "bed.deleted"))
+}
+
+// restoreBed повертає ліжко, лише якщо палата жива і в ній є місце -
+// так само, як createBed
+func restoreBed(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := context.TODO()
+	var bed models.Bed
+	err := db.Collection("beds").FindOne(ctx, bson.M{"_id": objID, "deletedAt": bson.M{"$exists": true}}).Decode(&bed)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "bed.not_in_trash")
+		return
+	}
+
+	err = db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		return admission.RestoreBed(u, admissionStore{}, bed)
+	})
+	switch {
+	case errors.Is(err, admission.ErrWardFull):
+		httpError(w, r, http.StatusConflict, "ward.deleted_or_full")
+		return
+	case errors.Is(err, admission.ErrBedNotInTrash):
+		httpError(w, r, http.StatusNotFound, "bed.not_in_trash")
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Query-параметри wardFilter для документації
+var wardParams = []Param{
+	{Name: "hospitalId", Description: "Hospital ObjectID"},
+	{Name: "departmentId", Description: "Department ObjectID"},
+}
+
+func wardFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	addIDFilter(filter, "hospital_id", query.Get("hospitalId"))
+	addIDFilter(filter, "department_id", query.Get("departmentId"))
+	return filter
+}
+
+// Query-параметри bedFilter для документації
+var bedParams = []Param{
+	{Name: "hospitalId", Description: "Hospital ObjectID"},
+	{Name: "departmentId", Description: "Department ObjectID"},
+	{Name: "wardId", Description: "Ward ObjectID"},
+	{Name: "free", Type: "boolean", Description: "true - only free beds, false - only occupied"},
+}
+
+func bedFilter(query url.Values) bson.M {
+	filter := bson.M{}
+	addIDFilter(filter, "hospital_id", query.Get("hospitalId"))
+	addIDFilter(filter, "department_id", query.Get("departmentId"))
+	addIDFilter(filter, "ward_id", query.Get("wardId"))
+	switch strings.TrimSpace(query.Get("free")) {
+	case "true":
+		filter["patientId"] = bson.M{"$exists": false}
+	case "false":
+		filter["patientId"] = bson.M{"$exists": true}
+	}
+	return filter
+}
+
+// addIDFilter додає умову field = ObjectID, якщо value - коректний hex
+func addIDFilter(filter bson.M, field, value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	if objID, err := primitive.ObjectIDFromHex(value); err == nil {
+		filter[field] = objID
+	}
+}
+
+func exists(col *mongo.Collection, id primitive.ObjectID) bool {
+	n, err := col.CountDocuments(context.TODO(), alive(bson.M{"_id": id}), options.Count().SetLimit(1))
+	return err == nil && n > 0
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"hospital-api/db"
+	"hospital-api/models"
+	"hospital-api/webhooks"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Webhooks - диспетчер доставок; main запускає його на шині Events
+var Webhooks = webhooks.NewDispatcher(webhooks.MongoStore{}, 4)
+
+// Підписки керуються лише адміністратором
+func WebhookRoutes(router *Router) {
+	hooks := router.Group("/webhooks", LoggingMiddleware, JWT("admin")).Secured(SecurityBearer)
+	hooks.HandleFunc(http.MethodGet, "", listWebhooks).
+		Describe("List webhook subscriptions").Returns([]models.Webhook{})
+	hooks.HandleFunc(http.MethodPost, "", createWebhook).
+		Describe("Subscribe a URL to events; the response is the only time the secret is shown").
+		Accepts(models.Webhook{}).Returns(models.Webhook{})
+	hooks.HandleFunc(http.MethodGet, "/{id}", getWebhook).
+		Describe("Get a webhook subscription").Returns(models.Webhook{})
+	hooks.HandleFunc(http.MethodPut, "/{id}", updateWebhook).
+		Describe("Update URL, events or active flag of a subscription").Accepts(models.Webhook{})
+	hooks.HandleFunc(http.MethodDelete, "/{id}", deleteWebhook).
+		Describe("Delete a webhook subscription")
+
+	hooks.HandleFunc(http.MethodGet, "/dead-letters", listDeadLetters).
+		Describe("List deliveries that failed after all retries").
+		Query(Param{Name: "webhookId", Description: "Webhook ObjectID"}).Returns([]models.DeadLetter{})
+	hooks.HandleFunc(http.MethodPost, "/dead-letters/{id}/retry", retryDeadLetter).
+		Describe("Queue a failed delivery again")
+	hooks.HandleFunc(http.MethodDelete, "/dead-letters/{id}", deleteDeadLetter).
+		Describe("Discard a failed delivery")
+}
+
+// validateWebhookEvents перевіряє, що шаблони підписки посилаються на відомі події
+func validateWebhookEvents(patterns []string) error {
+	var errs models.ValidationErrors
+	for _, p := range patterns {
+		if p == "*" {
+			continue
+		}
+		resource, action, _ := strings.Cut(p, ".")
+		if !knownEventResource(resource) {
+			errs = append(errs, models.NewFieldError("events", "validation.unknown_resource", resource))
+		}
+		switch action {
+		case "*", "created", "updated", "deleted":
+		default:
+			errs = append(errs, models.NewFieldError("events", "validation.unknown_action", action))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func decodeWebhook(w http.ResponseWriter, r *http.Request) (models.Webhook, bool) {
+	var hook models.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&hook); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return hook, false
+	}
+	if err := hook.Validate(); err != nil {
+		validationError(w, r, err)
+		return hook, false
+	}
+	if err := validateWebhookEvents(hook.Events); err != nil {
+		validationError(w, r, err)
+		return hook, false
+	}
+	return hook, true
+}
+
+func listWebhooks(w http.ResponseWriter, r *http.Request) {
+	cursor, err := db.Collection("webhooks").Find(context.TODO(), bson.M{},
+		options.Find().SetProjection(bson.M{"secret": 0}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hooks := []models.Webhook{}
+	if err := cursor.All(context.TODO(), &hooks); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, hooks)
+}
+
+func createWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+	if hook.Secret == "" {
+		hook.Secret = webhooks.NewSecret()
+	}
+	hook.Active = true
+	hook.CreatedAt = time.Now().UTC()
+
+	res, err := db.Collection("webhooks").InsertOne(context.TODO(), hook)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hook.ID = res.InsertedID.(primitive.ObjectID)
+	writeResponse(w, r, http.StatusCreated, hook)
+}
+
+func getWebhook(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var hook models.Webhook
+	err := db.Collection("webhooks").FindOne(context.TODO(), bson.M{"_id": objID},
+		options.FindOne().SetProjection(bson.M{"secret": 0})).Decode(&hook)
+	if err != nil {
+		httpError(w, r, http.StatusNotFound, "webhook.not_found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, hook)
+}
+
+// Секрет змінюється, лише якщо його передано
+func updateWebhook(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	hook, ok := decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	set := bson.M{"url": hook.URL, "events": hook.Events, "active": hook.Active}
+	if hook.Secret != "" {
+		set["secret"] = hook.Secret
+	}
+	res, err := db.Collection("webhooks").UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": set})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.MatchedCount == 0 {
+		httpError(w, r, http.StatusNotFound, "webhook.not_found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := db.Collection("webhooks").DeleteOne(context.TODO(), bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.DeletedCount == 0 {
+		httpError(w, r, http.StatusNotFound, "webhook.not_found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func listDeadLetters(w http.ResponseWriter, r *http.Request) {
+	filter := bson.M{}
+	addIDFilter(filter, "webhookId", r.URL.Query().Get("webhookId"))
+
+	cursor, err := db.Collection("webhook_dead_letters").Find(context.TODO(), filter,
+		options.Find().SetSort(bson.M{"failedAt": -1}))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	letters := []models.DeadLetter{}
+	if err := cursor.All(context.TODO(), &letters); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, letters)
+}
+
+// retryDeadLetter забирає доставку зі списку і ставить її в чергу з новим
+// лічильником спроб; якщо вона знову не вдасться, повернеться в список
+func retryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := context.TODO()
+	var letter models.DeadLetter
+	if err := db.Collection("webhook_dead_letters").FindOne(ctx, bson.M{"_id": objID}).Decode(&letter); err != nil {
+		httpError(w, r, http.StatusNotFound, "dead_letter.not_found")
+		return
+	}
+	var hook models.Webhook
+	if err := db.Collection("webhooks").FindOne(ctx, bson.M{"_id": letter.WebhookID}).Decode(&hook); err != nil {
+		httpError(w, r, http.StatusConflict, "webhook.gone")
+		return
+	}
+
+	res, err := db.Collection("webhook_dead_letters").DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.DeletedCount == 0 {
+		// Паралельний повтор уже забрав цю доставку
+		httpError(w, r, http.StatusNotFound, "dead_letter.not_found")
+		return
+	}
+	Webhooks.Enqueue(webhooks.Delivery{
+		ID:        letter.DeliveryID,
+		Webhook:   hook,
+		EventType: letter.EventType,
+		Payload:   []byte(letter.Payload),
+	})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func deleteDeadLetter(w http.ResponseWriter, r *http.Request) {
+	objID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := db.Collection("webhook_dead_letters").DeleteOne(context.TODO(), bson.M{"_id": objID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.DeletedCount == 0 {
+		httpError(w, r, http.StatusNotFound, "dead_letter.not_found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
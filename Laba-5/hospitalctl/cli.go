@@ -0,0 +1,530 @@
+// Package hospitalctl - командний клієнт hospital-api: вхід через /login
+// з кешем токена, list/get/create/update/delete для кожного ресурсу,
+// вивід таблицею, JSON або YAML і доповнення для bash, zsh та fish.
+package hospitalctl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"hospital-api/client"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultServer - адреса, яку слухає hospital-api за замовчуванням
+const DefaultServer = "http://localhost:8080"
+
+// Коди завершення
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `Usage: hospitalctl <command> [flags]
+
+Commands:
+  login                        log in and cache the token for --server
+  logout                       forget the cached token
+  list <resource> [filters]    list documents; filters are the API query parameters
+  get <resource> <id>          show one document
+  create <resource> -f FILE    create from a JSON or YAML file ("-" for stdin) or --data
+  update <resource> <id> -f FILE
+  delete <resource> <id>
+  resources                    show resources and their filters
+  completion bash|zsh|fish     print a shell completion script
+
+Common flags:
+  --server URL    API address (HOSPITALCTL_SERVER, default ` + DefaultServer + `)
+  --api-key KEY   X-API-KEY for hospitals, departments and doctors (HOSPITALCTL_API_KEY)
+  -o, --output    table, json or yaml (HOSPITALCTL_OUTPUT, default table)
+`
+
+// CLI - hospitalctl з підмінними потоками, оточенням і HTTP-клієнтом.
+// Порожні поля заповнюються стандартними значеннями процесу.
+type CLI struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Getenv func(string) string
+	Client *http.Client
+	Now    func() time.Time
+}
+
+// Main запускає CLI для os.Args і повертає код завершення
+func Main() int {
+	return (&CLI{}).Run(os.Args[1:])
+}
+
+func (c *CLI) defaults() {
+	if c.Stdin == nil {
+		c.Stdin = os.Stdin
+	}
+	if c.Stdout == nil {
+		c.Stdout = os.Stdout
+	}
+	if c.Stderr == nil {
+		c.Stderr = os.Stderr
+	}
+	if c.Getenv == nil {
+		c.Getenv = os.Getenv
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// usageError - помилка у виклику, а не на сервері: код 2
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// Run виконує одну команду; args - без імені програми
+func (c *CLI) Run(args []string) int {
+	c.defaults()
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(c.Stdout, usage)
+		return exitOK
+	}
+
+	err := c.dispatch(args[0], args[1:])
+	var uerr usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &uerr):
+		fmt.Fprintln(c.Stderr, "hospitalctl:", err)
+		fmt.Fprintln(c.Stderr, "Run 'hospitalctl help' for usage.")
+		return exitUsage
+	default:
+		fmt.Fprintln(c.Stderr, "hospitalctl:", err)
+		return exitError
+	}
+}
+
+func commandNames() []string {
+	return []string{"login", "logout", List, Get, Create, Update, Delete, "resources", "completion"}
+}
+
+func isVerb(command string) bool {
+	switch command {
+	case List, Get, Create, Update, Delete:
+		return true
+	}
+	return false
+}
+
+func (c *CLI) dispatch(command string, args []string) error {
+	switch command {
+	case "login":
+		return c.login(args)
+	case "logout":
+		return c.logout(args)
+	case List:
+		return c.list(args)
+	case Get:
+		return c.get(args)
+	case Create, Update:
+		return c.write(command, args)
+	case Delete:
+		return c.delete(args)
+	case "resources":
+		return c.resources()
+	case "completion":
+		if len(args) != 1 {
+			return usagef("completion needs a shell: %s", strings.Join(completionShells(), ", "))
+		}
+		return writeCompletion(c.Stdout, args[0])
+	case "__complete":
+		for _, candidate := range Complete(args) {
+			fmt.Fprintln(c.Stdout, candidate)
+		}
+		return nil
+	}
+	return usagef("unknown command %q", command)
+}
+
+// options - прапорці, спільні для всіх команд
+type options struct {
+	server string
+	apiKey string
+	output string
+}
+
+func (c *CLI) newFlags(name string) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	opts := &options{}
+	fs.StringVar(&opts.server, "server", c.env("HOSPITALCTL_SERVER", DefaultServer), "API address")
+	fs.StringVar(&opts.apiKey, "api-key", c.Getenv("HOSPITALCTL_API_KEY"), "X-API-KEY header value")
+	output := c.env("HOSPITALCTL_OUTPUT", OutputTable)
+	fs.StringVar(&opts.output, "output", output, "output format: table, json or yaml")
+	fs.StringVar(&opts.output, "o", output, "shorthand for --output")
+	return fs, opts
+}
+
+func (c *CLI) env(name, fallback string) string {
+	if v := c.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// parse розбирає прапорці в будь-якому місці рядка: list hospitals --city Київ
+func parse(fs *flag.FlagSet, opts *options, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageError{err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if !validOutput(opts.output) {
+		return nil, usagef("unknown output format %q, use table, json or yaml", opts.output)
+	}
+	return positional, nil
+}
+
+func (c *CLI) store() (tokenStore, error) {
+	if dir := c.Getenv("HOSPITALCTL_CONFIG"); dir != "" {
+		return tokenStore{dir: dir}, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return tokenStore{}, err
+	}
+	return tokenStore{dir: filepath.Join(dir, "hospitalctl")}, nil
+}
+
+// apiClient - клієнт SDK із кешованим токеном сервера, якщо він ще дійсний
+func (c *CLI) apiClient(opts *options) (*client.Client, error) {
+	clientOpts := client.Options{HTTPClient: c.Client, APIKey: opts.apiKey, Language: acceptLanguage(c.Getenv("LANG"))}
+	store, err := c.store()
+	if err != nil {
+		return nil, err
+	}
+	cred, ok, err := store.Get(opts.server)
+	if err != nil {
+		return nil, fmt.Errorf("read cached token: %w", err)
+	}
+	if ok && cred.Expired(c.Now()) {
+		fmt.Fprintf(c.Stderr, "hospitalctl: token for %s has expired, run 'hospitalctl login'\n", opts.server)
+	} else if ok {
+		clientOpts.Token = cred.Token
+	}
+	return client.New(opts.server, clientOpts), nil
+}
+
+// acceptLanguage - мова з LANG (uk_UA.UTF-8 → uk-UA), щоб сервер
+// відповідав повідомленнями мовою оператора
+func acceptLanguage(lang string) string {
+	lang, _, _ = strings.Cut(lang, ".")
+	if lang == "" || lang == "C" || lang == "POSIX" {
+		return ""
+	}
+	return strings.ReplaceAll(lang, "_", "-")
+}
+
+// explain додає підказку до 401: найчастіше токена немає або він застарів
+func explain(err error) error {
+	if errors.Is(err, client.ErrUnauthorized) {
+		return fmt.Errorf("%w (run 'hospitalctl login' or pass --api-key)", err)
+	}
+	return err
+}
+
+func (c *CLI) login(args []string) error {
+	fs, opts := c.newFlags("login")
+	username := fs.String("username", c.Getenv("HOSPITALCTL_USERNAME"), "user name")
+	password := fs.String("password", c.Getenv("HOSPITALCTL_PASSWORD"), "password (prefer --password-stdin)")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if _, err := parse(fs, opts, args); err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(c.Stdin)
+	if *username == "" {
+		fmt.Fprint(c.Stderr, "Username: ")
+		*username = readLine(in)
+	}
+	if *passwordStdin || *password == "" {
+		if !*passwordStdin {
+			fmt.Fprint(c.Stderr, "Password: ")
+		}
+		*password = readLine(in)
+	}
+	if *username == "" || *password == "" {
+		return usagef("username and password are required")
+	}
+
+	api := client.New(opts.server, client.Options{HTTPClient: c.Client, Language: acceptLanguage(c.Getenv("LANG"))})
+	token, err := api.Login(context.Background(), *username, *password)
+	if err != nil {
+		return err
+	}
+	store, err := c.store()
+	if err != nil {
+		return err
+	}
+	cred := Credential{Username: *username, Token: token, ExpiresAt: client.TokenExpiry(token)}
+	if err := store.Put(opts.server, cred); err != nil {
+		return fmt.Errorf("cache token: %w", err)
+	}
+	fmt.Fprintf(c.Stdout, "Logged in to %s as %s\n", serverKey(opts.server), *username)
+	return nil
+}
+
+func readLine(r *bufio.Reader) string {
+	line, _ := r.ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
+}
+
+func (c *CLI) logout(args []string) error {
+	fs, opts := c.newFlags("logout")
+	if _, err := parse(fs, opts, args); err != nil {
+		return err
+	}
+	store, err := c.store()
+	if err != nil {
+		return err
+	}
+	return store.Remove(opts.server)
+}
+
+// resourceArg - перший позиційний аргумент як ресурс, що підтримує дію
+func resourceArg(verb string, positional []string, want int) (Resource, error) {
+	if len(positional) == 0 {
+		return Resource{}, usagef("%s needs a resource", verb)
+	}
+	r, ok := FindResource(positional[0])
+	if !ok {
+		return Resource{}, usagef("unknown resource %q, see 'hospitalctl resources'", positional[0])
+	}
+	if !r.Supports(verb) {
+		return Resource{}, usagef("%s does not support %s", r.Name, verb)
+	}
+	if len(positional) != want {
+		if want == 2 {
+			return Resource{}, usagef("%s %s needs an id", verb, r.Name)
+		}
+		return Resource{}, usagef("unexpected arguments: %s", strings.Join(positional[want:], " "))
+	}
+	return r, nil
+}
+
+// filterFlags реєструє фільтри ресурсу; незадані не потрапляють у запит
+func filterFlags(fs *flag.FlagSet, filters []Filter) func() url.Values {
+	values := map[string]*optional{}
+	for _, f := range filters {
+		v := &optional{bool: f.Bool}
+		values[f.Name] = v
+		fs.Var(v, f.Name, f.Usage)
+	}
+	return func() url.Values {
+		query := url.Values{}
+		for name, v := range values {
+			if v.set {
+				query.Set(name, v.value)
+			}
+		}
+		return query
+	}
+}
+
+// optional - значення прапорця разом з ознакою, що його задали
+type optional struct {
+	value string
+	set   bool
+	bool  bool
+}
+
+func (o *optional) String() string { return o.value }
+
+func (o *optional) Set(v string) error {
+	o.value, o.set = v, true
+	return nil
+}
+
+// IsBoolFlag дозволяє писати --free замість --free=true
+func (o *optional) IsBoolFlag() bool { return o.bool }
+
+// resourceFlags - спільні прапорці і фільтри ресурсу з позиційного
+// аргументу: їх треба знати ще до розбору рядка
+func (c *CLI) resourceFlags(verb string, args []string, filters func(Resource) []Filter) (*flag.FlagSet, *options, func() url.Values) {
+	fs, opts := c.newFlags(verb)
+	query := func() url.Values { return nil }
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		// Значення прапорців (--server URL) пропускаємо
+		if r, ok := FindResource(arg); ok {
+			query = filterFlags(fs, filters(r))
+			break
+		}
+	}
+	return fs, opts, query
+}
+
+func (c *CLI) list(args []string) error {
+	fs, opts, query := c.resourceFlags(List, args, func(r Resource) []Filter { return r.Filters })
+	positional, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	r, err := resourceArg(List, positional, 1)
+	if err != nil {
+		return err
+	}
+	return c.call(opts, r, http.MethodGet, r.Path, query(), nil)
+}
+
+func (c *CLI) get(args []string) error {
+	fs, opts, query := c.resourceFlags(Get, args, Resource.GetFilters)
+	positional, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	r, err := resourceArg(Get, positional, 2)
+	if err != nil {
+		return err
+	}
+	return c.call(opts, r, http.MethodGet, r.Path+"/"+url.PathEscape(positional[1]), query(), nil)
+}
+
+func (c *CLI) write(verb string, args []string) error {
+	fs, opts := c.newFlags(verb)
+	file := fs.String("file", "", `JSON or YAML document, "-" for stdin`)
+	fs.StringVar(file, "f", "", "shorthand for --file")
+	data := fs.String("data", "", "inline JSON document")
+	positional, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	want, method := 1, http.MethodPost
+	if verb == Update {
+		want, method = 2, http.MethodPut
+	}
+	r, err := resourceArg(verb, positional, want)
+	if err != nil {
+		return err
+	}
+	body, err := c.document(*file, *data)
+	if err != nil {
+		return err
+	}
+	path := r.Path
+	if verb == Update {
+		path += "/" + url.PathEscape(positional[1])
+	}
+	return c.call(opts, r, method, path, nil, body)
+}
+
+func (c *CLI) delete(args []string) error {
+	fs, opts := c.newFlags(Delete)
+	positional, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	r, err := resourceArg(Delete, positional, 2)
+	if err != nil {
+		return err
+	}
+	return c.call(opts, r, http.MethodDelete, r.Path+"/"+url.PathEscape(positional[1]), nil, nil)
+}
+
+// document читає тіло create/update і перетворює YAML на JSON
+func (c *CLI) document(file, data string) ([]byte, error) {
+	var raw []byte
+	switch {
+	case file != "" && data != "":
+		return nil, usagef("use either --file or --data")
+	case file == "-":
+		b, err := io.ReadAll(c.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		raw = b
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		raw = b
+	case data != "":
+		raw = []byte(data)
+	default:
+		return nil, usagef("a document is required: --file FILE or --data JSON")
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if _, ok := doc.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("invalid document: expected an object")
+	}
+	return json.Marshal(doc)
+}
+
+// call виконує запит і друкує відповідь: JSON - у вибраному форматі,
+// текстові повідомлення (updated, deleted) - як є
+func (c *CLI) call(opts *options, r Resource, method, path string, query url.Values, body []byte) error {
+	api, err := c.apiClient(opts)
+	if err != nil {
+		return err
+	}
+	req := client.Request{Method: method, Path: path, Query: query, Body: body, Idempotent: method == http.MethodPost}
+	resp, err := api.Do(context.Background(), req)
+	if err != nil {
+		return explain(err)
+	}
+	if !resp.JSON() {
+		if text := strings.TrimSpace(string(resp.Body)); text != "" {
+			fmt.Fprintln(c.Stdout, text)
+		}
+		return nil
+	}
+	return render(c.Stdout, opts.output, r.Columns, resp.Body)
+}
+
+func (c *CLI) resources() error {
+	tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+	for _, r := range Resources {
+		fmt.Fprintf(tw, "%s\t%s\n", r.Name, strings.Join(r.Verbs, ", "))
+		for _, f := range r.Filters {
+			fmt.Fprintf(tw, "  --%s\t%s\n", f.Name, f.Usage)
+		}
+	}
+	return tw.Flush()
+}
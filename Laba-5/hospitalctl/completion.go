@@ -0,0 +1,152 @@
+package hospitalctl
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Скрипти лише передають слова рядка прихованій команді __complete,
+// тож доповнення завжди відповідають таблиці ресурсів цієї версії.
+var completionScripts = map[string]string{
+	"bash": `# hospitalctl bash completion
+_hospitalctl() {
+	local IFS=$'\n'
+	COMPREPLY=($(hospitalctl __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _hospitalctl hospitalctl
+`,
+	"zsh": `#compdef hospitalctl
+# hospitalctl zsh completion
+_hospitalctl() {
+	local -a candidates
+	candidates=("${(@f)$(hospitalctl __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	compadd -a candidates
+}
+compdef _hospitalctl hospitalctl
+`,
+	"fish": `# hospitalctl fish completion
+complete -c hospitalctl -f -a '(hospitalctl __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+}
+
+func completionShells() []string {
+	shells := make([]string, 0, len(completionScripts))
+	for shell := range completionScripts {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	return shells
+}
+
+func writeCompletion(w io.Writer, shell string) error {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("unknown shell %q, use one of: %s", shell, strings.Join(completionShells(), ", "))
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// Complete - варіанти для останнього слова; words - аргументи без імені програми
+func Complete(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	previous := words[:len(words)-1]
+
+	if len(previous) > 0 {
+		switch previous[len(previous)-1] {
+		case "-o", "--o", "-output", "--output":
+			return withPrefix(outputFormats, current)
+		}
+	}
+	if len(previous) == 0 {
+		return withPrefix(commandNames(), current)
+	}
+
+	command := previous[0]
+	positional := positionalArgs(previous[1:])
+	if strings.HasPrefix(current, "-") {
+		return withPrefix(flagNames(command, positional), current)
+	}
+	switch {
+	case command == "completion" && len(positional) == 0:
+		return withPrefix(completionShells(), current)
+	case isVerb(command) && len(positional) == 0:
+		var names []string
+		for _, r := range Resources {
+			if r.Supports(command) {
+				names = append(names, r.Name)
+			}
+		}
+		return withPrefix(names, current)
+	}
+	return nil
+}
+
+// positionalArgs відкидає прапорці та їхні значення
+func positionalArgs(words []string) []string {
+	var out []string
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !strings.HasPrefix(w, "-") {
+			out = append(out, w)
+			continue
+		}
+		name := strings.TrimLeft(w, "-")
+		if strings.Contains(name, "=") || boolFlag(name) {
+			continue
+		}
+		i++ // значення прапорця
+	}
+	return out
+}
+
+func boolFlag(name string) bool {
+	if name == "password-stdin" {
+		return true
+	}
+	for _, r := range Resources {
+		for _, f := range r.Filters {
+			if f.Name == name {
+				return f.Bool
+			}
+		}
+	}
+	return false
+}
+
+func flagNames(command string, positional []string) []string {
+	names := []string{"--server", "--api-key", "--output"}
+	switch command {
+	case "login":
+		names = append(names, "--username", "--password", "--password-stdin")
+	case Create, Update:
+		names = append(names, "--file", "--data")
+	}
+	if len(positional) > 0 && (command == List || command == Get) {
+		if r, ok := FindResource(positional[0]); ok {
+			filters := r.Filters
+			if command == Get {
+				filters = r.GetFilters()
+			}
+			for _, f := range filters {
+				names = append(names, "--"+f.Name)
+			}
+		}
+	}
+	return names
+}
+
+func withPrefix(options []string, prefix string) []string {
+	var out []string
+	for _, o := range options {
+		if strings.HasPrefix(o, prefix) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package hospitalctl
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const credentialsFile = "credentials.json"
+
+// Credential - збережений після login токен для одного сервера
+type Credential struct {
+	Username  string    `json:"username"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired - токен уже не прийме сервер (з запасом на розбіжність годинників)
+func (c Credential) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && now.Add(30*time.Second).After(c.ExpiresAt)
+}
+
+// tokenStore - credentials.json у теці конфігурації: сервер → токен
+type tokenStore struct {
+	dir string
+}
+
+func serverKey(server string) string {
+	return strings.TrimRight(server, "/")
+}
+
+func (s tokenStore) path() string {
+	return filepath.Join(s.dir, credentialsFile)
+}
+
+func (s tokenStore) load() (map[string]Credential, error) {
+	data, err := os.ReadFile(s.path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]Credential{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	creds := map[string]Credential{}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// Get - токен для сервера; ok = false, якщо входу не було
+func (s tokenStore) Get(server string) (Credential, bool, error) {
+	creds, err := s.load()
+	if err != nil {
+		return Credential{}, false, err
+	}
+	cred, ok := creds[serverKey(server)]
+	return cred, ok, nil
+}
+
+// Put зберігає токен; файл доступний лише власнику
+func (s tokenStore) Put(server string, cred Credential) error {
+	creds, err := s.load()
+	if err != nil {
+		return err
+	}
+	creds[serverKey(server)] = cred
+	return s.save(creds)
+}
+
+// Remove забуває токен сервера
+func (s tokenStore) Remove(server string) error {
+	creds, err := s.load()
+	if err != nil {
+		return err
+	}
+	delete(creds, serverKey(server))
+	return s.save(creds)
+}
+
+func (s tokenStore) save(creds map[string]Credential) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Пишемо поруч і перейменовуємо, щоб обірваний запис не зіпсував файл
+	tmp := s.path() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path())
+}
@@ -0,0 +1,160 @@
+package hospitalctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Формати виводу (-o)
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+var outputFormats = []string{OutputTable, OutputJSON, OutputYAML}
+
+func validOutput(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// render друкує JSON-відповідь API у вибраному форматі
+func render(w io.Writer, format string, columns []string, body []byte) error {
+	switch format {
+	case OutputJSON:
+		var out bytes.Buffer
+		if err := json.Indent(&out, body, "", "  "); err != nil {
+			return err
+		}
+		out.WriteByte('\n')
+		_, err := w.Write(out.Bytes())
+		return err
+	case OutputYAML:
+		return renderYAML(w, body)
+	default:
+		return renderTable(w, columns, body)
+	}
+}
+
+// renderYAML перекладає JSON у YAML зі збереженням порядку полів:
+// YAML-парсер читає JSON як flow-стиль, ми лише перемикаємо його на блоковий
+func renderYAML(w io.Writer, body []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
+
+// renderTable - таблиця з колонками ресурсу; без них - з полями першого рядка
+func renderTable(w io.Writer, columns []string, body []byte) error {
+	rows, err := tableRows(body)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		columns = rowKeys(rows)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = cell(row[c])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tableRows приймає і масив, і одиночний об'єкт (get, create)
+func tableRows(body []byte) ([]map[string]json.RawMessage, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var rows []map[string]json.RawMessage
+		err := json.Unmarshal(body, &rows)
+		return rows, err
+	}
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(body, &row); err != nil {
+		return nil, err
+	}
+	return []map[string]json.RawMessage{row}, nil
+}
+
+// rowKeys - поля першого рядка: спершу id, далі за абеткою
+func rowKeys(rows []map[string]json.RawMessage) []string {
+	if len(rows) == 0 {
+		return nil
+	}
+	var keys []string
+	for k := range rows[0] {
+		if k != "id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := rows[0]["id"]; ok {
+		keys = append([]string{"id"}, keys...)
+	}
+	return keys
+}
+
+// cell - значення для таблиці: рядки без лапок, решта компактним JSON
+func cell(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var list []interface{}
+	if json.Unmarshal(raw, &list) == nil {
+		parts := make([]string, len(list))
+		for i, v := range list {
+			if s, ok := v.(string); ok {
+				parts[i] = s
+			} else {
+				b, _ := json.Marshal(v)
+				parts[i] = string(b)
+			}
+		}
+		return strings.Join(parts, ",")
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, raw) != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
@@ -0,0 +1,209 @@
+package hospitalctl
+
+import "strings"
+
+// Дії над ресурсами, які підтримує CLI
+const (
+	List   = "list"
+	Get    = "get"
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// Filter - прапорець команди list; Name збігається з query-параметром API
+type Filter struct {
+	Name  string
+	Usage string
+	Bool  bool
+}
+
+// Resource - колекція API: шлях, дозволені дії, фільтри й колонки таблиці
+type Resource struct {
+	Name    string
+	Aliases []string
+	Path    string
+	Verbs   []string
+	Filters []Filter
+	Columns []string
+}
+
+// Supports повідомляє, чи має ресурс дію verb
+func (r Resource) Supports(verb string) bool {
+	for _, v := range r.Verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFilters - фільтри, які API приймає і для GET /{id}
+func (r Resource) GetFilters() []Filter {
+	var out []Filter
+	for _, f := range r.Filters {
+		if f.Name == includeDeleted.Name || f.Name == expandFilter.Name {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+var (
+	allVerbs       = []string{List, Get, Create, Update, Delete}
+	includeDeleted = Filter{Name: "includeDeleted", Usage: "also show soft-deleted records (admin token)", Bool: true}
+	expandFilter   = Filter{Name: "expand", Usage: "comma-separated related documents to embed"}
+)
+
+func idFilter(name, what string) Filter {
+	return Filter{Name: name, Usage: what + " ObjectID"}
+}
+
+// Resources - усе, чим керує hospitalctl. Фільтри повторюють
+// query-параметри списків API (тест звіряє їх з /openapi.json).
+var Resources = []Resource{
+	{
+		Name: "hospitals", Aliases: []string{"hospital"}, Path: "/hospitals", Verbs: allVerbs,
+		Filters: []Filter{
+			{Name: "name", Usage: "substring of the name"},
+			{Name: "city", Usage: "substring of the city"},
+			{Name: "beds", Usage: "exact number of beds"},
+			{Name: "minBeds", Usage: "minimum number of beds"},
+			{Name: "maxBeds", Usage: "maximum number of beds"},
+			includeDeleted,
+			expandFilter,
+		},
+		Columns: []string{"id", "name", "location", "beds"},
+	},
+	{
+		Name: "departments", Aliases: []string{"department"}, Path: "/departments", Verbs: allVerbs,
+		Filters: []Filter{
+			{Name: "name", Usage: "substring of the name"},
+			idFilter("hospitalId", "hospital"),
+			{Name: "floor", Usage: "exact floor"},
+			{Name: "minFloor", Usage: "lowest floor"},
+			{Name: "maxFloor", Usage: "highest floor"},
+			includeDeleted,
+			expandFilter,
+		},
+		Columns: []string{"id", "name", "hospitalId", "floor"},
+	},
+	{
+		Name: "doctors", Aliases: []string{"doctor"}, Path: "/doctors", Verbs: allVerbs,
+		Filters: []Filter{
+			{Name: "name", Usage: "substring of the name"},
+			{Name: "specialty", Usage: "substring of the specialty"},
+			idFilter("department", "department"),
+			{Name: "experience_years", Usage: "exact years of experience"},
+			{Name: "minExperience", Usage: "minimum years of experience"},
+			{Name: "maxExperience", Usage: "maximum years of experience"},
+			includeDeleted,
+			expandFilter,
+		},
+		Columns: []string{"id", "name", "specialty", "department", "experienceYears"},
+	},
+	{
+		Name: "staff", Path: "/staff", Verbs: allVerbs,
+		Filters: []Filter{
+			{Name: "name", Usage: "substring of the name"},
+			{Name: "role", Usage: "substring of the role"},
+			{Name: "shift", Usage: "substring of the shift"},
+			idFilter("hospitalId", "hospital"),
+			idFilter("departmentId", "department"),
+			includeDeleted,
+			expandFilter,
+		},
+		Columns: []string{"id", "name", "role", "shift", "hospitalId", "departmentId"},
+	},
+	{
+		Name: "medications", Aliases: []string{"medication", "medicines", "medicine"}, Path: "/medications", Verbs: allVerbs,
+		Filters: []Filter{
+			{Name: "name", Usage: "substring of the name"},
+			{Name: "dosage", Usage: "substring of the dosage"},
+			{Name: "manufacturer", Usage: "substring of the manufacturer"},
+			includeDeleted,
+		},
+		Columns: []string{"id", "name", "dosage", "manufacturer", "stock", "price"},
+	},
+	{
+		Name: "appointments", Aliases: []string{"appointment"}, Path: "/appointments", Verbs: allVerbs,
+		Filters: []Filter{
+			idFilter("patientId", "patient"),
+			idFilter("doctorId", "doctor"),
+			{Name: "date", Usage: "appointments on this day (YYYY-MM-DD)"},
+			{Name: "status", Usage: "booked, checked_in, in_progress, completed, cancelled or no_show"},
+			includeDeleted,
+			expandFilter,
+		},
+		Columns: []string{"id", "patientId", "doctorId", "date", "status"},
+	},
+	{
+		Name: "wards", Aliases: []string{"ward"}, Path: "/wards", Verbs: []string{List, Get, Create, Delete},
+		Filters: []Filter{
+			idFilter("hospitalId", "hospital"),
+			idFilter("departmentId", "department"),
+			includeDeleted,
+		},
+		Columns: []string{"id", "name", "hospitalId", "departmentId", "capacity", "beds"},
+	},
+	{
+		Name: "beds", Aliases: []string{"bed"}, Path: "/beds", Verbs: []string{List, Get, Create, Delete},
+		Filters: []Filter{
+			idFilter("hospitalId", "hospital"),
+			idFilter("departmentId", "department"),
+			idFilter("wardId", "ward"),
+			{Name: "free", Usage: "only free beds (--free=false: only occupied)", Bool: true},
+			includeDeleted,
+		},
+		Columns: []string{"id", "label", "wardId", "patientId", "admissionId"},
+	},
+	{
+		Name: "admissions", Aliases: []string{"admission"}, Path: "/admissions", Verbs: []string{List, Get, Create},
+		Filters: []Filter{
+			idFilter("patientId", "patient"),
+			idFilter("hospitalId", "hospital"),
+			idFilter("departmentId", "department"),
+			{Name: "status", Usage: "active or discharged"},
+		},
+		Columns: []string{"id", "patientId", "bedId", "status", "admittedAt", "dischargedAt"},
+	},
+	{
+		Name: "shifts", Aliases: []string{"shift"}, Path: "/shifts", Verbs: allVerbs,
+		Filters: []Filter{
+			idFilter("hospitalId", "hospital"),
+			idFilter("departmentId", "department"),
+			includeDeleted,
+		},
+		Columns: []string{"id", "name", "departmentId", "start", "end"},
+	},
+	{
+		Name: "roster", Path: "/roster", Verbs: []string{List, Create, Delete},
+		Filters: []Filter{
+			idFilter("staffId", "staff member"),
+			idFilter("departmentId", "department"),
+			{Name: "from", Usage: "first day, YYYY-MM-DD"},
+			{Name: "to", Usage: "last day (inclusive), YYYY-MM-DD"},
+		},
+		Columns: []string{"id", "staffId", "shiftId", "date", "start", "end"},
+	},
+	{
+		Name: "webhooks", Aliases: []string{"webhook"}, Path: "/webhooks", Verbs: allVerbs,
+		Columns: []string{"id", "url", "events", "active"},
+	},
+}
+
+// FindResource шукає ресурс за назвою або синонімом
+func FindResource(name string) (Resource, bool) {
+	name = strings.ToLower(name)
+	for _, r := range Resources {
+		if r.Name == name {
+			return r, true
+		}
+		for _, alias := range r.Aliases {
+			if alias == name {
+				return r, true
+			}
+		}
+	}
+	return Resource{}, false
+}
@@ -0,0 +1,107 @@
+// Package i18n - каталоги повідомлень API українською та англійською.
+// Повідомлення шукаються за ключем ("hospital.not_found"), мова
+// обирається за Accept-Language; без неї й для невідомих мов - англійська.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Мови каталогів; Default - та, що й до локалізації
+const (
+	English   = "en"
+	Ukrainian = "uk"
+	Default   = English
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalogs - мова → ключ → повідомлення (з дієсловами fmt для аргументів)
+var catalogs = mustLoad()
+
+func mustLoad() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]map[string]string{}
+	for _, f := range files {
+		data, err := locales.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		out[strings.TrimSuffix(f.Name(), ".json")] = catalog
+	}
+	return out
+}
+
+// Languages - мови, для яких є каталоги
+func Languages() []string {
+	return []string{English, Ukrainian}
+}
+
+// Catalog - копія каталогу мови (для перевірки повноти в тестах)
+func Catalog(lang string) map[string]string {
+	out := make(map[string]string, len(catalogs[lang]))
+	for k, v := range catalogs[lang] {
+		out[k] = v
+	}
+	return out
+}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Ukrainian})
+
+// Match обирає мову за заголовком Accept-Language з урахуванням q
+func Match(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Languages()[index]
+}
+
+// T - повідомлення key мовою lang; якщо перекладу немає - англійською,
+// а якщо немає й англійського - сам ключ
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+type langKey struct{}
+
+// WithLang зберігає мову запиту в контексті
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// Lang - мова з контексту або Default
+func Lang(ctx context.Context) string {
+	if lang, ok := ctx.Value(langKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
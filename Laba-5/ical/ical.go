@@ -0,0 +1,248 @@
+// Package ical пише й читає календарі iCalendar (RFC 5545). Запис - для
+// стрічок розкладу лікарів і пацієнтів: події з TZID і VTIMEZONE, що
+// описує переходи часового поясу на проміжку подій. Читання - лише
+// VEVENT (DTSTART, DTEND або DURATION, RRULE, EXDATE, RECURRENCE-ID,
+// STATUS, TRANSP), решта
+// компонентів пропускається. Пакет не звертається до бази.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event - одна VEVENT. Transparent - подія не займає час (TRANSP:TRANSPARENT).
+// RRule, ExDates (повтори, викреслені EXDATE) і RecurrenceID (котрий повтор
+// події з тим самим UID замінює ця) заповнюються лише під час читання.
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Summary      string
+	Description  string
+	Status       string
+	Transparent  bool
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID time.Time
+}
+
+// Calendar - VCALENDAR зі стрічки. Location - часовий пояс подій (nil - UTC).
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+const (
+	localFormat = "20060102T150405"
+	utcFormat   = "20060102T150405Z"
+	dateFormat  = "20060102"
+)
+
+// Encode пише календар із рядками CRLF, згорнутими до 75 байт
+func (c Calendar) Encode(w io.Writer) error {
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	utc := loc == time.UTC
+	out := &lineWriter{w: bufio.NewWriter(w)}
+
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:-//hospital-api//calendar//EN")
+	out.line("CALSCALE:GREGORIAN")
+	out.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		out.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	if !utc {
+		out.line("X-WR-TIMEZONE:" + loc.String())
+		from, to := c.span()
+		writeTimezone(out, loc, from, to)
+	}
+
+	stamp := time.Now().UTC().Format(utcFormat)
+	for _, e := range c.Events {
+		out.line("BEGIN:VEVENT")
+		out.line("UID:" + escape(e.UID))
+		out.line("DTSTAMP:" + stamp)
+		switch {
+		case e.AllDay:
+			out.line("DTSTART;VALUE=DATE:" + e.Start.In(loc).Format(dateFormat))
+			out.line("DTEND;VALUE=DATE:" + e.End.In(loc).Format(dateFormat))
+		case utc:
+			out.line("DTSTART:" + e.Start.UTC().Format(utcFormat))
+			out.line("DTEND:" + e.End.UTC().Format(utcFormat))
+		default:
+			out.line("DTSTART;TZID=" + loc.String() + ":" + e.Start.In(loc).Format(localFormat))
+			out.line("DTEND;TZID=" + loc.String() + ":" + e.End.In(loc).Format(localFormat))
+		}
+		if e.Summary != "" {
+			out.line("SUMMARY:" + escape(e.Summary))
+		}
+		if e.Description != "" {
+			out.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Status != "" {
+			out.line("STATUS:" + e.Status)
+		}
+		if e.Transparent {
+			out.line("TRANSP:TRANSPARENT")
+		}
+		out.line("END:VEVENT")
+	}
+	out.line("END:VCALENDAR")
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// span - проміжок подій із запасом у день; без подій - поточний день
+func (c Calendar) span() (time.Time, time.Time) {
+	if len(c.Events) == 0 {
+		now := time.Now()
+		return now.Add(-24 * time.Hour), now.Add(24 * time.Hour)
+	}
+	from, to := c.Events[0].Start, c.Events[0].End
+	for _, e := range c.Events {
+		if e.Start.Before(from) {
+			from = e.Start
+		}
+		if e.End.After(to) {
+			to = e.End
+		}
+	}
+	return from.Add(-24 * time.Hour), to.Add(24 * time.Hour)
+}
+
+// writeTimezone описує пояс loc на проміжку [from, to]: початковий зсув
+// і кожен перехід окремим STANDARD/DAYLIGHT. Go не дає правил поясу,
+// тож переходи знаходимо за зміною зсуву - це точно для будь-якого поясу
+// без вигаданих RRULE.
+func writeTimezone(out *lineWriter, loc *time.Location, from, to time.Time) {
+	out.line("BEGIN:VTIMEZONE")
+	out.line("TZID:" + loc.String())
+
+	name, offset := from.In(loc).Zone()
+	writeObservance(out, from.In(loc).IsDST(), from, name, offset, offset)
+	for at := from; ; {
+		next, ok := nextTransition(loc, at, to)
+		if !ok {
+			break
+		}
+		_, before := next.Add(-time.Second).In(loc).Zone()
+		name, after := next.In(loc).Zone()
+		writeObservance(out, next.In(loc).IsDST(), next, name, before, after)
+		at = next
+	}
+	out.line("END:VTIMEZONE")
+}
+
+// writeObservance - один STANDARD або DAYLIGHT; DTSTART - місцевий час
+// за попереднім зсувом, як вимагає RFC 5545
+func writeObservance(out *lineWriter, dst bool, at time.Time, name string, from, to int) {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	out.line("BEGIN:" + kind)
+	out.line("DTSTART:" + at.In(time.FixedZone("", from)).Format(localFormat))
+	out.line("TZOFFSETFROM:" + formatOffset(from))
+	out.line("TZOFFSETTO:" + formatOffset(to))
+	if name != "" {
+		out.line("TZNAME:" + escape(name))
+	}
+	out.line("END:" + kind)
+}
+
+// nextTransition - перша секунда після at (не пізніше to), з якої діє інший зсув
+func nextTransition(loc *time.Location, at, to time.Time) (time.Time, bool) {
+	_, offset := at.In(loc).Zone()
+	for lo := at; lo.Before(to); lo = lo.Add(24 * time.Hour) {
+		hi := lo.Add(24 * time.Hour)
+		if _, o := hi.In(loc).Zone(); o == offset {
+			continue
+		}
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+			if _, o := mid.In(loc).Zone(); o == offset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return hi, true
+	}
+	return time.Time{}, false
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
+
+// escape - TEXT за RFC 5545: \ ; , і переведення рядка
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
+
+// lineWriter згортає рядки довші за 75 байт, не розриваючи символи UTF-8
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // пробіл на початку продовження теж рахується
+	}
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(s)
+	}
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+var errNoCalendar = errors.New("not an iCalendar file: BEGIN:VCALENDAR expected")
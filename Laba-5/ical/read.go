@@ -0,0 +1,227 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// property - рядок вмісту NAME;PARAM=VALUE:VALUE після розгортання
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Read читає VEVENT з r і для кожної викликає fn з номером рядка її
+// BEGIN. Помилка однієї події передається в fn і не зупиняє читання;
+// помилка з fn зупиняє. Час без поясу ("плаваючий") і дати - у loc.
+func Read(r io.Reader, loc *time.Location, fn func(line int, e Event, err error) error) error {
+	if loc == nil {
+		loc = time.UTC
+	}
+	lines, err := unfold(r)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0].text, "BEGIN:VCALENDAR") {
+		return errNoCalendar
+	}
+
+	var props []property
+	var bad error
+	start, depth := 0, 0
+	for _, l := range lines {
+		p, err := parseProperty(l.text)
+		if err != nil {
+			if depth > 0 && bad == nil {
+				bad = fmt.Errorf("line %d: %w", l.number, err)
+			}
+			continue
+		}
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT") && depth == 0:
+			start, depth, props, bad = l.number, 1, nil, nil
+		case depth == 0:
+			continue
+		case p.name == "BEGIN":
+			depth++ // VALARM та інші вкладені компоненти пропускаємо
+		case p.name == "END":
+			depth--
+			if depth > 0 {
+				continue
+			}
+			var e Event
+			if bad == nil {
+				e, bad = buildEvent(props, loc)
+			}
+			if err := fn(start, e, bad); err != nil {
+				return err
+			}
+		case depth == 1:
+			props = append(props, p)
+		}
+	}
+	return nil
+}
+
+type rawLine struct {
+	number int
+	text   string
+}
+
+// unfold з'єднує згорнуті рядки (продовження починається з пробілу чи табуляції)
+func unfold(r io.Reader) ([]rawLine, error) {
+	var lines []rawLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if n == 1 {
+			text = strings.TrimPrefix(text, "\uFEFF") // BOM
+		}
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lines = append(lines, rawLine{number: n, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty розбирає рядок; двокрапка в лапках параметра не є роздільником
+func parseProperty(line string) (property, error) {
+	colon, quoted := -1, false
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 1 {
+		return property{}, fmt.Errorf("malformed line %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := property{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return p, nil
+}
+
+func buildEvent(props []property, loc *time.Location) (Event, error) {
+	var e Event
+	var end time.Time
+	var duration time.Duration
+	var hasDuration bool
+	for _, p := range props {
+		var err error
+		switch p.name {
+		case "UID":
+			e.UID = unescape(p.value)
+		case "SUMMARY":
+			e.Summary = unescape(p.value)
+		case "DESCRIPTION":
+			e.Description = unescape(p.value)
+		case "STATUS":
+			e.Status = strings.ToUpper(p.value)
+		case "TRANSP":
+			e.Transparent = strings.EqualFold(p.value, "TRANSPARENT")
+		case "RRULE":
+			e.RRule = p.value
+		case "EXDATE":
+			// Кілька дат через кому, і рядків EXDATE може бути кілька
+			for _, value := range strings.Split(p.value, ",") {
+				var t time.Time
+				if t, _, err = parseTime(property{name: p.name, params: p.params, value: value}, loc); err != nil {
+					break
+				}
+				e.ExDates = append(e.ExDates, t)
+			}
+		case "RECURRENCE-ID":
+			e.RecurrenceID, _, err = parseTime(p, loc)
+		case "DTSTART":
+			e.Start, e.AllDay, err = parseTime(p, loc)
+		case "DTEND":
+			end, _, err = parseTime(p, loc)
+		case "DURATION":
+			duration, err = parseDuration(p.value)
+			hasDuration = true
+		}
+		if err != nil {
+			return Event{}, fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+
+	switch {
+	case e.Start.IsZero():
+		return Event{}, fmt.Errorf("DTSTART is required")
+	case !end.IsZero():
+		e.End = end
+	case hasDuration:
+		e.End = e.Start.Add(duration)
+	case e.AllDay:
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		e.End = e.Start
+	}
+	if e.End.Before(e.Start) {
+		return Event{}, fmt.Errorf("event ends before it starts")
+	}
+	return e, nil
+}
+
+// parseTime - DATE-TIME у UTC, з TZID, "плаваючий", або DATE (початок дня)
+func parseTime(p property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcFormat, value)
+		return t, false, err
+	}
+	if tzid := p.params["TZID"]; tzid != "" {
+		zone, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+		loc = zone
+	}
+	t, err := time.ParseInLocation(localFormat, value, loc)
+	return t, false, err
+}
+
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration - DURATION за RFC 5545 (P1D, PT1H30M, P2W)
+func parseDuration(value string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
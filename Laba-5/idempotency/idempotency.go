@@ -0,0 +1,193 @@
+// Package idempotency робить POST-запити з заголовком Idempotency-Key
+// безпечними для повторів: перша відповідь зберігається і віддається
+// знову на кожен повтор з тим самим ключем і тілом.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"hospital-api/i18n"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// maxKeyLength і maxBody обмежують, що ми готові зберігати
+	maxKeyLength = 255
+	maxBody      = 10 << 20
+)
+
+// Record - стан ключа: поки запит виконується, Done = false
+type Record struct {
+	Key         string      `bson:"_id"`
+	RequestHash string      `bson:"requestHash"`
+	Done        bool        `bson:"done"`
+	Status      int         `bson:"status,omitempty"`
+	Header      http.Header `bson:"header,omitempty"`
+	Body        []byte      `bson:"body,omitempty"`
+	CreatedAt   time.Time   `bson:"createdAt"`
+	ExpiresAt   time.Time   `bson:"expiresAt"`
+}
+
+// ErrExists - ключ уже зайнято іншим записом
+var ErrExists = errors.New("idempotency key exists")
+
+// Store зберігає записи ключів. Reserve має бути атомарним: з двох
+// одночасних запитів з одним ключем виграє рівно один.
+type Store interface {
+	// Reserve створює запис або повертає ErrExists разом з наявним записом
+	Reserve(ctx context.Context, rec Record) (Record, error)
+	Complete(ctx context.Context, rec Record) error
+	Release(ctx context.Context, key string) error
+}
+
+// Middleware зберігає відповіді POST-запитів з Idempotency-Key на ttl.
+// scope відокремлює ключі різних клієнтів (наприклад, за користувачем).
+//
+//   - повтор з тим самим тілом отримує збережену відповідь і Idempotent-Replayed: true;
+//   - той самий ключ з іншим тілом чи шляхом - 422;
+//   - повтор, поки перший запит ще виконується, - 409.
+//
+// Відповіді 5xx, 401, 403 і 429 не зберігаються: після них ключ звільняється,
+// щоб клієнт міг повторити запит.
+func Middleware(store Store, ttl time.Duration, scope func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				httpError(w, r, http.StatusBadRequest, "idempotency.key_too_long")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if len(body) > maxBody {
+				httpError(w, r, http.StatusRequestEntityTooLarge, "idempotency.body_too_large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now().UTC()
+			rec := Record{
+				Key:         scope(r) + " " + key,
+				RequestHash: requestHash(r, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+			existing, err := store.Reserve(r.Context(), rec)
+			switch {
+			case errors.Is(err, ErrExists):
+				replay(w, r, existing, rec.RequestHash)
+				return
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			capture := &recorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				// Запит обірвався панікою - звільняємо ключ і пробрасуємо далі
+				if p := recover(); p != nil {
+					store.Release(context.Background(), rec.Key)
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(capture, r)
+
+			if !storable(capture.status) {
+				store.Release(context.Background(), rec.Key)
+				return
+			}
+			rec.Done = true
+			rec.Status = capture.status
+			rec.Header = capture.Header().Clone()
+			rec.Body = capture.body.Bytes()
+			// Незбережену відповідь не віддати повтором, а ключ "в процесі"
+			// відповідав би 409 до кінця ttl - звільняємо його
+			if err := store.Complete(context.Background(), rec); err != nil {
+				log.Println("idempotency: cannot store response:", err)
+				store.Release(context.Background(), rec.Key)
+			}
+		})
+	}
+}
+
+// requestHash - відбиток методу, шляху і тіла: ключ не можна
+// перевикористати для іншого запиту
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func storable(status int) bool {
+	switch {
+	case status >= 500:
+		return false
+	case status == http.StatusUnauthorized, status == http.StatusForbidden, status == http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+func replay(w http.ResponseWriter, r *http.Request, rec Record, hash string) {
+	if rec.RequestHash != hash {
+		httpError(w, r, http.StatusUnprocessableEntity, "idempotency.key_reused")
+		return
+	}
+	if !rec.Done {
+		w.Header().Set("Retry-After", "1")
+		httpError(w, r, http.StatusConflict, "idempotency.in_progress")
+		return
+	}
+	for name, values := range rec.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	w.Write(rec.Body)
+}
+
+// httpError пише повідомлення мовою, яку вибрав LanguageMiddleware
+func httpError(w http.ResponseWriter, r *http.Request, status int, key string) {
+	http.Error(w, i18n.T(i18n.Lang(r.Context()), key), status)
+}
+
+// recorder пише відповідь клієнту і водночас запам'ятовує її
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recorder) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recorder) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,83 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hospital-api/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoStore - Store на колекції idempotency_keys з TTL-індексом
+// по expiresAt; прострочені ключі прибирає сам Mongo
+type MongoStore struct{}
+
+func (s MongoStore) Reserve(ctx context.Context, rec Record) (Record, error) {
+	col := db.Collection("idempotency_keys")
+	_, err := col.InsertOne(ctx, rec)
+	if err == nil {
+		return rec, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return Record{}, err
+	}
+
+	var existing Record
+	err = col.FindOne(ctx, bson.M{"_id": rec.Key}).Decode(&existing)
+	if err == mongo.ErrNoDocuments || err == nil && existing.ExpiresAt.Before(time.Now()) {
+		// TTL-монітор ще не прибрав прострочений ключ або його щойно звільнили
+		col.DeleteOne(ctx, bson.M{"_id": rec.Key, "expiresAt": existing.ExpiresAt})
+		return s.Reserve(ctx, rec)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	return existing, ErrExists
+}
+
+func (MongoStore) Complete(ctx context.Context, rec Record) error {
+	_, err := db.Collection("idempotency_keys").ReplaceOne(ctx, bson.M{"_id": rec.Key}, rec)
+	return err
+}
+
+func (MongoStore) Release(ctx context.Context, key string) error {
+	_, err := db.Collection("idempotency_keys").DeleteOne(ctx, bson.M{"_id": key, "done": false})
+	return err
+}
+
+// MemoryStore - Store у пам'яті процесу, для тестів і одного інстансу
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]Record{}}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, rec Record) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[rec.Key]; ok && existing.ExpiresAt.After(time.Now()) {
+		return existing, ErrExists
+	}
+	s.records[rec.Key] = rec
+	return rec, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[rec.Key] = rec
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
@@ -1,30 +1,220 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
+	_ "time/tzdata" // графік змін рахується за Europe/Kyiv навіть без системної бази поясів
 
 	"hospital-api/db"
+	"hospital-api/events"
 	"hospital-api/handlers"
+	"hospital-api/seed"
 )
 
+const mongoURI = "mongodb://localhost:27017"
+
 func main() {
-	db.Connect("mongodb://localhost:27017")
+	db.Connect(mongoURI)
+
+	// Команди: migrate up | migrate down [n] | migrate status | seed | purge
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "seed":
+			runSeed(os.Args[2:])
+			return
+		case "purge":
+			runPurge(os.Args[2:])
+			return
+		default:
+			log.Fatalf("unknown command %q", os.Args[1])
+		}
+	}
+
+	// Під час старту застосовуємо всі нові міграції
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	applied, err := db.MigrateUp(ctx, db.Database())
+	cancel()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range applied {
+		fmt.Printf("✅ Міграцію %d (%s) застосовано\n", m.Version, m.Name)
+	}
+
+	// Будь-який запис у колекцію скидає її кеш читань; записи інших
+	// процесів приходять через change stream
+	db.OnWrite(handlers.InvalidateCache)
+	go handlers.WatchCache(context.Background(), db.Database(), 2*time.Second)
 
-	// Спочатку реєструємо всі CRUD маршрути
-	handlers.AppointmentRoutes()
-	handlers.StaffRoutes()
-	handlers.MedicineRoutes()
-	handlers.DoctorRoutes()
-	handlers.HospitalRoutes()
-	handlers.DepartmentRoutes()
+	// Реєструємо всі маршрути
+	router := handlers.NewAPI()
 
-	// Потім catch-all "/" в кінці
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, "✅ API працює! Використовуй /hospitals, /appointments, /patients тощо.")
-	})
+	// Зміни для /events: change streams або опитування на standalone mongod
+	go events.Watch(context.Background(), db.Database(), handlers.Events, 2*time.Second, handlers.EventResources...)
+	// Ті самі події - зовнішнім підписникам
+	go handlers.Webhooks.Run(context.Background(), handlers.Events)
+
+	// Індекс для /search: будуємо одразу й перебудовуємо, щоб бачити зміни
+	go searchLoop(30 * time.Second)
+
+	// Щогодини прибираємо м'яко видалені документи, старші за строк зберігання
+	if retention := purgeRetention(); retention > 0 {
+		go purgeLoop(retention)
+	}
+
+	// gRPC API - ті самі дані й авторизація, окремий порт
+	go serveGRPC(":9090")
 
 	fmt.Println("🚀 Server is running on http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	log.Fatal(http.ListenAndServe(":8080", router))
+}
+
+func serveGRPC(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("🚀 gRPC is running on " + addr)
+	log.Fatal(handlers.NewGRPCServer().Serve(lis))
+}
+
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage: migrate up | down [n] | status")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	database := db.Database()
+
+	switch args[0] {
+	case "up":
+		applied, err := db.MigrateUp(ctx, database)
+		for _, m := range applied {
+			fmt.Printf("up   %3d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := db.MigrateDown(ctx, database, steps)
+		for _, m := range reverted {
+			fmt.Printf("down %3d %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "status":
+		statuses, err := db.MigrationsStatus(ctx, database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			if st.Unknown {
+				state += " (unknown to this build)"
+			}
+			fmt.Printf("%3d %-28s %s\n", st.Version, st.Name, state)
+		}
+
+	default:
+		log.Fatalf("unknown migrate command %q", args[0])
+	}
+}
+
+func runSeed(args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	file := fs.String("file", "data.json", "fixtures file (.json, .yaml or .yml)")
+	wipe := fs.Bool("wipe", false, "delete existing documents before loading")
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ds, err := seed.LoadFile(ctx, db.Database(), *file, *wipe)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("🌱 Завантажено: %d лікарень, %d відділень, %d лікарів, %d працівників, %d ліків, %d записів\n",
+		len(ds.Hospitals), len(ds.Departments), len(ds.Doctors), len(ds.Staff), len(ds.Medications), len(ds.Appointments))
+}
+
+// purgeRetention - строк зберігання видалених документів із SOFT_DELETE_RETENTION
+// (тривалість Go, наприклад "720h"; "0" вимикає фонове прибирання)
+func purgeRetention() time.Duration {
+	value := os.Getenv("SOFT_DELETE_RETENTION")
+	if value == "" {
+		return db.DefaultRetention
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("invalid SOFT_DELETE_RETENTION %q: %v", value, err)
+	}
+	return retention
+}
+
+func purgeLoop(retention time.Duration) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		purged, err := db.PurgeDeleted(ctx, db.Database(), time.Now().Add(-retention))
+		cancel()
+		if err != nil {
+			log.Println("purge:", err)
+		}
+		for name, n := range purged {
+			log.Printf("purge: %s - %d documents", name, n)
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
+func searchLoop(interval time.Duration) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		if err := handlers.RefreshSearch(ctx); err != nil {
+			log.Println("search:", err)
+		}
+		cancel()
+		time.Sleep(interval)
+	}
+}
+
+func runPurge(args []string) {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	retention := fs.Duration("retention", purgeRetention(), "delete records soft-deleted longer ago than this")
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	purged, err := db.PurgeDeleted(ctx, db.Database(), time.Now().Add(-*retention))
+	for _, name := range db.SoftDeleteCollections {
+		fmt.Printf("%-14s %d\n", name, purged[name])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AdmissionActive     = "active"
+	AdmissionDischarged = "discharged"
+)
+
+// Admission - госпіталізація пацієнта на конкретне ліжко
+type Admission struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PatientID    primitive.ObjectID `bson:"patientId" json:"patientId"`
+	BedID        primitive.ObjectID `bson:"bedId" json:"bedId"`
+	WardID       primitive.ObjectID `bson:"ward_id" json:"wardId"`
+	DepartmentID primitive.ObjectID `bson:"department_id" json:"departmentId"`
+	HospitalID   primitive.ObjectID `bson:"hospital_id" json:"hospitalId"`
+	Status       string             `bson:"status" json:"status"`
+	AdmittedAt   time.Time          `bson:"admittedAt" json:"admittedAt"`
+	DischargedAt *time.Time         `bson:"dischargedAt,omitempty" json:"dischargedAt,omitempty"`
+	Transfers    []BedTransfer      `bson:"transfers,omitempty" json:"transfers,omitempty"`
+}
+
+// BedTransfer - переведення пацієнта на інше ліжко
+type BedTransfer struct {
+	FromBedID primitive.ObjectID `bson:"fromBedId" json:"fromBedId"`
+	ToBedID   primitive.ObjectID `bson:"toBedId" json:"toBedId"`
+	At        time.Time          `bson:"at" json:"at"`
+}
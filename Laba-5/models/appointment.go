@@ -1,14 +1,111 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Стани запису до лікаря
+const (
+	AppointmentBooked     = "booked"
+	AppointmentCheckedIn  = "checked_in"
+	AppointmentInProgress = "in_progress"
+	AppointmentCompleted  = "completed"
+	AppointmentCancelled  = "cancelled"
+	AppointmentNoShow     = "no_show"
+)
+
+// AppointmentStatuses - усі стани в порядку життєвого циклу
+var AppointmentStatuses = []string{
+	AppointmentBooked, AppointmentCheckedIn, AppointmentInProgress,
+	AppointmentCompleted, AppointmentCancelled, AppointmentNoShow,
+}
+
 type Appointment struct {
+	ID                   primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	PatientID            primitive.ObjectID  `bson:"patientId" json:"patientId"`
+	DoctorID             primitive.ObjectID  `bson:"doctorId" json:"doctorId"`
+	Date                 time.Time           `bson:"date" json:"date"`
+	SeriesID             *primitive.ObjectID `bson:"seriesId,omitempty" json:"seriesId,omitempty"`
+	AppointmentLifecycle `bson:",inline"`
+	SoftDelete           `bson:",inline"`
+}
+
+// AppointmentLifecycle - стан запису й час кожного переходу (для звітів:
+// скільки чекали після реєстрації, скільки тривав прийом тощо).
+// Записи, створені до появи статусів, мають порожній Status і вважаються booked.
+type AppointmentLifecycle struct {
+	Status       string     `bson:"status,omitempty" json:"status"`
+	CheckedInAt  *time.Time `bson:"checkedInAt,omitempty" json:"checkedInAt,omitempty"`
+	StartedAt    *time.Time `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	CompletedAt  *time.Time `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	CancelledAt  *time.Time `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+	CancelReason string     `bson:"cancelReason,omitempty" json:"cancelReason,omitempty"`
+	NoShowAt     *time.Time `bson:"noShowAt,omitempty" json:"noShowAt,omitempty"`
+}
+
+// State - поточний стан; порожній означає booked
+func (l AppointmentLifecycle) State() string {
+	if l.Status == "" {
+		return AppointmentBooked
+	}
+	return l.Status
+}
+
+// Released - запис не займає час лікаря (скасований чи неявка)
+func (l AppointmentLifecycle) Released() bool {
+	state := l.State()
+	return state == AppointmentCancelled || state == AppointmentNoShow
+}
+
+// AppointmentTransition - дія над записом: з яких станів у який, і в яке
+// поле (bson) записується час переходу
+type AppointmentTransition struct {
+	Action string
+	From   []string
+	To     string
+	Field  string
+}
+
+// AppointmentTransitions - дозволені переходи; решта станів кінцеві
+var AppointmentTransitions = []AppointmentTransition{
+	{Action: "check-in", From: []string{AppointmentBooked}, To: AppointmentCheckedIn, Field: "checkedInAt"},
+	{Action: "start", From: []string{AppointmentCheckedIn}, To: AppointmentInProgress, Field: "startedAt"},
+	{Action: "complete", From: []string{AppointmentInProgress}, To: AppointmentCompleted, Field: "completedAt"},
+	{Action: "cancel", From: []string{AppointmentBooked, AppointmentCheckedIn}, To: AppointmentCancelled, Field: "cancelledAt"},
+	{Action: "no-show", From: []string{AppointmentBooked}, To: AppointmentNoShow, Field: "noShowAt"},
+}
+
+// Allows - чи можна виконати перехід зі стану status
+func (t AppointmentTransition) Allows(status string) bool {
+	if status == "" {
+		status = AppointmentBooked
+	}
+	return slices.Contains(t.From, status)
+}
+
+// AppointmentCancellation - тіло POST /appointments/{id}/cancel
+type AppointmentCancellation struct {
+	Reason string `json:"reason"`
+}
+
+// AppointmentSeries - повторювані записи (фізіотерапія, діаліз): правило
+// RFC 5545 і перший прийом. Самі записи - звичайні Appointment з SeriesID,
+// тож їхні стани, перенесення й скасування працюють як для окремих.
+type AppointmentSeries struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	PatientID primitive.ObjectID `bson:"patientId" json:"patientId"`
 	DoctorID  primitive.ObjectID `bson:"doctorId" json:"doctorId"`
-	Date      time.Time          `bson:"date" json:"date"`
+	Start     time.Time          `bson:"start" json:"start"`
+	RRule     string             `bson:"rrule" json:"rrule"`
+}
+
+// OccurrenceChange - тіло PUT /appointment-series/{id}/occurrences/{appointmentId}.
+// Порожні поля не змінюються; RRule - нове правило для решти серії.
+type OccurrenceChange struct {
+	DoctorID primitive.ObjectID `json:"doctorId,omitempty"`
+	Date     time.Time          `json:"date,omitempty"`
+	RRule    string             `json:"rrule,omitempty"`
 }
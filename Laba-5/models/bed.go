@@ -0,0 +1,17 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bed - ліжко в палаті; PatientID заповнений, поки ліжко зайняте
+type Bed struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Label        string              `bson:"label" json:"label"`
+	WardID       primitive.ObjectID  `bson:"ward_id" json:"wardId"`
+	DepartmentID primitive.ObjectID  `bson:"department_id" json:"departmentId"`
+	HospitalID   primitive.ObjectID  `bson:"hospital_id" json:"hospitalId"`
+	PatientID    *primitive.ObjectID `bson:"patientId,omitempty" json:"patientId,omitempty"`
+	AdmissionID  *primitive.ObjectID `bson:"admissionId,omitempty" json:"admissionId,omitempty"`
+	SoftDelete   `bson:",inline"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Unavailability - час, коли лікар не приймає (відпустка, конференція).
+// Блоки з імпортованого .ics мають UID події: повторний імпорт того ж
+// файлу оновлює їх, а не дублює.
+type Unavailability struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DoctorID primitive.ObjectID `bson:"doctorId" json:"doctorId"`
+	Start    time.Time          `bson:"start" json:"start"`
+	End      time.Time          `bson:"end" json:"end"`
+	Summary  string             `bson:"summary,omitempty" json:"summary,omitempty"`
+	UID      string             `bson:"uid,omitempty" json:"uid,omitempty"`
+}
+
+// CalendarToken - секрет однієї стрічки .ics користувача: Feed - "doctors"
+// чи "patients", FeedID - чия стрічка. Календарні програми не вміють
+// надсилати JWT, тож токен іде в ?token=; у базі лежить лише його SHA-256,
+// а сам токен показується один раз.
+type CalendarToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username  string             `bson:"username" json:"username"`
+	Feed      string             `bson:"feed" json:"feed"`
+	FeedID    primitive.ObjectID `bson:"feedId" json:"feedId"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Path - адреса стрічки, яку відкриває токен
+func (t CalendarToken) Path() string {
+	return "/" + t.Feed + "/" + t.FeedID.Hex() + "/calendar.ics"
+}
@@ -9,4 +9,5 @@ type Department struct {
 	Name       string             `bson:"name" json:"name"`
 	HospitalID primitive.ObjectID `bson:"hospital_id" json:"hospitalId"`
 	Floor      int                `bson:"floor" json:"floor"`
+	SoftDelete `bson:",inline"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Dispensation - видача ліків пацієнту; на Quantity зменшується Medicine.Stock
+type Dispensation struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MedicineID  primitive.ObjectID `bson:"medicineId" json:"medicineId"`
+	PatientID   primitive.ObjectID `bson:"patientId" json:"patientId"`
+	Quantity    int                `bson:"quantity" json:"quantity"`
+	DispensedAt time.Time          `bson:"dispensedAt" json:"dispensedAt"`
+	DispensedBy string             `bson:"dispensedBy" json:"dispensedBy"`
+}
@@ -8,6 +8,7 @@ type Doctor struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name            string             `bson:"name" json:"name"`
 	Specialty       string             `bson:"specialty" json:"specialty"`
-	Department      string             `bson:"department" json:"department"`
+	Department      primitive.ObjectID `bson:"department,omitempty" json:"department"`
 	ExperienceYears int                `bson:"experience_years" json:"experienceYears"`
+	SoftDelete      `bson:",inline"`
 }
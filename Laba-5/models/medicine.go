@@ -10,4 +10,6 @@ type Medicine struct {
 	Dosage       string             `bson:"dosage" json:"dosage"`
 	Manufacturer string             `bson:"manufacturer" json:"manufacturer"`
 	Stock        int                `bson:"stock" json:"stock"`
+	Price        float64            `bson:"price" json:"price"`
+	SoftDelete   `bson:",inline"`
 }
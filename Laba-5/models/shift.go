@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Shift - визначення зміни у відділенні. Start і End - місцевий час "HH:MM";
+// якщо End не пізніше за Start, зміна закінчується наступного дня (нічна).
+// Coverage - скільки працівників кожної ролі має бути на зміні.
+type Shift struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`
+	HospitalID   primitive.ObjectID `bson:"hospital_id" json:"hospitalId"`
+	DepartmentID primitive.ObjectID `bson:"department_id" json:"departmentId"`
+	Start        string             `bson:"start" json:"start"`
+	End          string             `bson:"end" json:"end"`
+	Coverage     map[string]int     `bson:"coverage,omitempty" json:"coverage,omitempty"`
+	SoftDelete   `bson:",inline"`
+}
+
+// Window повертає початок і кінець зміни, що починається в день date
+func (s Shift) Window(date time.Time) (time.Time, time.Time, error) {
+	start, err := clockOn(date, s.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start: %w", err)
+	}
+	end, err := clockOn(date, s.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end: %w", err)
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+func clockOn(date time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be HH:MM")
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, date.Location()), nil
+}
+
+// RosterEntry - призначення працівника на зміну в конкретний день.
+// Role і час зберігаються знімком, щоб графік не змінювався заднім
+// числом, коли редагують працівника чи зміну.
+type RosterEntry struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	StaffID      primitive.ObjectID `bson:"staffId" json:"staffId"`
+	ShiftID      primitive.ObjectID `bson:"shiftId" json:"shiftId"`
+	DepartmentID primitive.ObjectID `bson:"department_id" json:"departmentId"`
+	Role         string             `bson:"role" json:"role"`
+	Date         string             `bson:"date" json:"date"`
+	Start        time.Time          `bson:"start" json:"start"`
+	End          time.Time          `bson:"end" json:"end"`
+}
+
+// Hours - тривалість призначення в годинах
+func (e RosterEntry) Hours() float64 {
+	return e.End.Sub(e.Start).Hours()
+}
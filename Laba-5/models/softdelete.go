@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SoftDelete - позначка м'якого видалення: хто і коли видалив документ.
+// Вбудовується в моделі; видалені документи лишаються в базі, доки їх
+// не прибере purge після строку зберігання.
+type SoftDelete struct {
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy string     `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+}
+
+// ClearDeleted прибирає позначку: видаляють лише через DELETE, а не
+// полями тіла чи рядка імпорту
+func (s *SoftDelete) ClearDeleted() {
+	*s = SoftDelete{}
+}
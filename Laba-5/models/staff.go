@@ -4,9 +4,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Staff.Shift - бажана зміна працівника (назва Shift), яку генератор
+// графіка враховує, коли вибирає між рівними кандидатами
 type Staff struct {
-	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name  string             `bson:"name" json:"name"`
-	Role  string             `bson:"role" json:"role"`
-	Shift string             `bson:"shift" json:"shift"`
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`
+	Role         string             `bson:"role" json:"role"`
+	Shift        string             `bson:"shift" json:"shift"`
+	HospitalID   primitive.ObjectID `bson:"hospital_id,omitempty" json:"hospitalId"`
+	DepartmentID primitive.ObjectID `bson:"department_id,omitempty" json:"departmentId"`
+	SoftDelete   `bson:",inline"`
 }
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"hospital-api/i18n"
+	"hospital-api/recurrence"
+)
+
+// FieldError - помилка валідації одного поля (поле у json-імені).
+// Code - ключ повідомлення в каталозі i18n, Message - його текст.
+type FieldError struct {
+	Field   string        `json:"field"`
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Args    []interface{} `json:"-"`
+}
+
+// NewFieldError створює помилку з англійським повідомленням
+func NewFieldError(field, code string, args ...interface{}) FieldError {
+	return FieldError{Field: field, Code: code, Message: i18n.T(i18n.Default, code, args...), Args: args}
+}
+
+// ValidationErrors - усі помилки валідації одного документа
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	parts := make([]string, len(v))
+	for i, fe := range v {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Localize повертає копію з повідомленнями мовою lang
+func (v ValidationErrors) Localize(lang string) ValidationErrors {
+	out := make(ValidationErrors, len(v))
+	for i, fe := range v {
+		out[i] = fe
+		if fe.Code != "" {
+			out[i].Message = i18n.T(lang, fe.Code, fe.Args...)
+		}
+	}
+	return out
+}
+
+func (v *ValidationErrors) add(field, code string, args ...interface{}) {
+	*v = append(*v, NewFieldError(field, code, args...))
+}
+
+func (v ValidationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (h Hospital) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(h.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	if h.Beds < 0 {
+		errs.add("beds", "validation.not_negative")
+	}
+	return errs.err()
+}
+
+func (d Department) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(d.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	if d.HospitalID.IsZero() {
+		errs.add("hospitalId", "validation.required")
+	}
+	return errs.err()
+}
+
+func (d Doctor) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(d.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	if d.ExperienceYears < 0 {
+		errs.add("experienceYears", "validation.not_negative")
+	}
+	return errs.err()
+}
+
+func (s Staff) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(s.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	return errs.err()
+}
+
+func (m Medicine) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(m.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	if m.Stock < 0 {
+		errs.add("stock", "validation.not_negative")
+	}
+	if m.Price < 0 {
+		errs.add("price", "validation.not_negative")
+	}
+	return errs.err()
+}
+
+func (a Appointment) Validate() error {
+	var errs ValidationErrors
+	if a.PatientID.IsZero() {
+		errs.add("patientId", "validation.required")
+	}
+	if a.DoctorID.IsZero() {
+		errs.add("doctorId", "validation.required")
+	}
+	if a.Date.IsZero() {
+		errs.add("date", "validation.required")
+	}
+	if a.Status != "" && !slices.Contains(AppointmentStatuses, a.Status) {
+		errs.add("status", "validation.unknown_status", a.Status)
+	}
+	return errs.err()
+}
+
+func (c AppointmentCancellation) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(c.Reason) == "" {
+		errs.add("reason", "validation.required")
+	}
+	return errs.err()
+}
+
+func (s AppointmentSeries) Validate() error {
+	var errs ValidationErrors
+	if s.PatientID.IsZero() {
+		errs.add("patientId", "validation.required")
+	}
+	if s.DoctorID.IsZero() {
+		errs.add("doctorId", "validation.required")
+	}
+	if s.Start.IsZero() {
+		errs.add("start", "validation.required")
+	}
+	if strings.TrimSpace(s.RRule) == "" {
+		errs.add("rrule", "validation.required")
+	} else if rule, err := recurrence.Parse(s.RRule); err != nil {
+		errs.add("rrule", "validation.rrule", err.Error())
+	} else if !s.Start.IsZero() {
+		dates, err := rule.Expand(s.Start, nil)
+		if err == nil && len(dates) == 0 {
+			err = errors.New("no occurrences")
+		}
+		if err != nil {
+			errs.add("rrule", "validation.rrule", err.Error())
+		}
+	}
+	return errs.err()
+}
+
+func (c OccurrenceChange) Validate() error {
+	var errs ValidationErrors
+	if c.DoctorID.IsZero() && c.Date.IsZero() && c.RRule == "" {
+		errs.add("date", "validation.required")
+	}
+	if c.RRule != "" {
+		if _, err := recurrence.Parse(c.RRule); err != nil {
+			errs.add("rrule", "validation.rrule", err.Error())
+		}
+	}
+	return errs.err()
+}
+
+func (u Unavailability) Validate() error {
+	var errs ValidationErrors
+	if u.DoctorID.IsZero() {
+		errs.add("doctorId", "validation.required")
+	}
+	if u.Start.IsZero() {
+		errs.add("start", "validation.required")
+	}
+	if !u.End.After(u.Start) {
+		errs.add("end", "validation.after_start")
+	}
+	return errs.err()
+}
+
+func (w Ward) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(w.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	if w.DepartmentID.IsZero() {
+		errs.add("departmentId", "validation.required")
+	}
+	if w.Capacity < 1 {
+		errs.add("capacity", "validation.min", 1)
+	}
+	return errs.err()
+}
+
+func (b Bed) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(b.Label) == "" {
+		errs.add("label", "validation.required")
+	}
+	if b.WardID.IsZero() {
+		errs.add("wardId", "validation.required")
+	}
+	return errs.err()
+}
+
+func (s Shift) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(s.Name) == "" {
+		errs.add("name", "validation.required")
+	}
+	if s.DepartmentID.IsZero() {
+		errs.add("departmentId", "validation.required")
+	}
+	if _, err := time.Parse("15:04", s.Start); err != nil {
+		errs.add("start", "validation.time")
+	}
+	if _, err := time.Parse("15:04", s.End); err != nil {
+		errs.add("end", "validation.time")
+	}
+	for role, n := range s.Coverage {
+		if n < 0 {
+			errs.add("coverage."+role, "validation.not_negative")
+		}
+	}
+	return errs.err()
+}
+
+func (e RosterEntry) Validate() error {
+	var errs ValidationErrors
+	if e.StaffID.IsZero() {
+		errs.add("staffId", "validation.required")
+	}
+	if e.ShiftID.IsZero() {
+		errs.add("shiftId", "validation.required")
+	}
+	if _, err := time.Parse(time.DateOnly, e.Date); err != nil {
+		errs.add("date", "validation.date")
+	}
+	return errs.err()
+}
+
+func (h Webhook) Validate() error {
+	var errs ValidationErrors
+	if u, err := url.Parse(h.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.add("url", "validation.url")
+	}
+	if len(h.Events) == 0 {
+		errs.add("events", "validation.required")
+	}
+	for _, e := range h.Events {
+		if e != "*" && strings.Count(e, ".") != 1 {
+			errs.add("events", "validation.event_pattern", e)
+		}
+	}
+	return errs.err()
+}
+
+func (d Dispensation) Validate() error {
+	var errs ValidationErrors
+	if d.PatientID.IsZero() {
+		errs.add("patientId", "validation.required")
+	}
+	if d.Quantity <= 0 {
+		errs.add("quantity", "validation.positive")
+	}
+	return errs.err()
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ward - палата у відділенні. Beds - скільки ліжок уже створено,
+// Capacity - скільки ліжок палата може мати взагалі.
+type Ward struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`
+	HospitalID   primitive.ObjectID `bson:"hospital_id" json:"hospitalId"`
+	DepartmentID primitive.ObjectID `bson:"department_id" json:"departmentId"`
+	Capacity     int                `bson:"capacity" json:"capacity"`
+	Beds         int                `bson:"beds" json:"beds"`
+	SoftDelete   `bson:",inline"`
+}
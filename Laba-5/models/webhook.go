@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Webhook - підписка зовнішньої системи на події. Events - типи подій
+// "ресурс.дія" (наприклад, "appointments.created"), "ресурс.*" або "*".
+// Secret підписує тіла запитів і показується лише при створенні.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL       string             `bson:"url" json:"url"`
+	Events    []string           `bson:"events" json:"events"`
+	Secret    string             `bson:"secret" json:"secret,omitempty"`
+	Active    bool               `bson:"active" json:"active"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// DeadLetter - доставка, яка не вдалася після всіх спроб
+type DeadLetter struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID  primitive.ObjectID `bson:"webhookId" json:"webhookId"`
+	DeliveryID string             `bson:"deliveryId" json:"deliveryId"`
+	EventType  string             `bson:"eventType" json:"eventType"`
+	Payload    string             `bson:"payload" json:"payload"`
+	Attempts   int                `bson:"attempts" json:"attempts"`
+	LastStatus int                `bson:"lastStatus,omitempty" json:"lastStatus,omitempty"`
+	LastError  string             `bson:"lastError" json:"lastError"`
+	FailedAt   time.Time          `bson:"failedAt" json:"failedAt"`
+}
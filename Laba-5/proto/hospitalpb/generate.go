@@ -0,0 +1,5 @@
+// Package hospitalpb - згенерований з ../hospital.proto код gRPC API.
+// Після зміни .proto: go generate ./proto/...
+package hospitalpb
+
+//go:generate protoc -I .. --go_out=../.. --go_opt=module=hospital-api --go-grpc_out=../.. --go-grpc_opt=module=hospital-api ../hospital.proto
@@ -0,0 +1,1788 @@
+// gRPC API лікарень. Ті самі дані й правила доступу, що й у REST:
+// лікарні, відділення й лікарі - за ключем x-api-key, працівники й записи -
+// за JWT (authorization: Bearer ...), ліки - без авторизації.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: hospital.proto
+
+package hospitalpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AppointmentEvent_Type int32
+
+const (
+	AppointmentEvent_TYPE_UNSPECIFIED AppointmentEvent_Type = 0
+	AppointmentEvent_CREATED          AppointmentEvent_Type = 1
+	AppointmentEvent_UPDATED          AppointmentEvent_Type = 2
+	AppointmentEvent_DELETED          AppointmentEvent_Type = 3
+)
+
+// Enum value maps for AppointmentEvent_Type.
+var (
+	AppointmentEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "CREATED",
+		2: "UPDATED",
+		3: "DELETED",
+	}
+	AppointmentEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"CREATED":          1,
+		"UPDATED":          2,
+		"DELETED":          3,
+	}
+)
+
+func (x AppointmentEvent_Type) Enum() *AppointmentEvent_Type {
+	p := new(AppointmentEvent_Type)
+	*p = x
+	return p
+}
+
+func (x AppointmentEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AppointmentEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_hospital_proto_enumTypes[0].Descriptor()
+}
+
+func (AppointmentEvent_Type) Type() protoreflect.EnumType {
+	return &file_hospital_proto_enumTypes[0]
+}
+
+func (x AppointmentEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AppointmentEvent_Type.Descriptor instead.
+func (AppointmentEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{18, 0}
+}
+
+// Позначка м'якого видалення; порожня для живих документів
+type Deletion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	DeletedBy     string                 `protobuf:"bytes,2,opt,name=deleted_by,json=deletedBy,proto3" json:"deleted_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deletion) Reset() {
+	*x = Deletion{}
+	mi := &file_hospital_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deletion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deletion) ProtoMessage() {}
+
+func (x *Deletion) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deletion.ProtoReflect.Descriptor instead.
+func (*Deletion) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Deletion) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Deletion) GetDeletedBy() string {
+	if x != nil {
+		return x.DeletedBy
+	}
+	return ""
+}
+
+type Hospital struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Beds          int32                  `protobuf:"varint,4,opt,name=beds,proto3" json:"beds,omitempty"`
+	Deletion      *Deletion              `protobuf:"bytes,5,opt,name=deletion,proto3" json:"deletion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hospital) Reset() {
+	*x = Hospital{}
+	mi := &file_hospital_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hospital) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hospital) ProtoMessage() {}
+
+func (x *Hospital) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hospital.ProtoReflect.Descriptor instead.
+func (*Hospital) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Hospital) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Hospital) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Hospital) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Hospital) GetBeds() int32 {
+	if x != nil {
+		return x.Beds
+	}
+	return 0
+}
+
+func (x *Hospital) GetDeletion() *Deletion {
+	if x != nil {
+		return x.Deletion
+	}
+	return nil
+}
+
+type Department struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	HospitalId    string                 `protobuf:"bytes,3,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	Floor         int32                  `protobuf:"varint,4,opt,name=floor,proto3" json:"floor,omitempty"`
+	Deletion      *Deletion              `protobuf:"bytes,5,opt,name=deletion,proto3" json:"deletion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Department) Reset() {
+	*x = Department{}
+	mi := &file_hospital_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Department) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Department) ProtoMessage() {}
+
+func (x *Department) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Department.ProtoReflect.Descriptor instead.
+func (*Department) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Department) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Department) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Department) GetHospitalId() string {
+	if x != nil {
+		return x.HospitalId
+	}
+	return ""
+}
+
+func (x *Department) GetFloor() int32 {
+	if x != nil {
+		return x.Floor
+	}
+	return 0
+}
+
+func (x *Department) GetDeletion() *Deletion {
+	if x != nil {
+		return x.Deletion
+	}
+	return nil
+}
+
+type Doctor struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Specialty       string                 `protobuf:"bytes,3,opt,name=specialty,proto3" json:"specialty,omitempty"`
+	DepartmentId    string                 `protobuf:"bytes,4,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	ExperienceYears int32                  `protobuf:"varint,5,opt,name=experience_years,json=experienceYears,proto3" json:"experience_years,omitempty"`
+	Deletion        *Deletion              `protobuf:"bytes,6,opt,name=deletion,proto3" json:"deletion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Doctor) Reset() {
+	*x = Doctor{}
+	mi := &file_hospital_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Doctor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Doctor) ProtoMessage() {}
+
+func (x *Doctor) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Doctor.ProtoReflect.Descriptor instead.
+func (*Doctor) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Doctor) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Doctor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Doctor) GetSpecialty() string {
+	if x != nil {
+		return x.Specialty
+	}
+	return ""
+}
+
+func (x *Doctor) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *Doctor) GetExperienceYears() int32 {
+	if x != nil {
+		return x.ExperienceYears
+	}
+	return 0
+}
+
+func (x *Doctor) GetDeletion() *Deletion {
+	if x != nil {
+		return x.Deletion
+	}
+	return nil
+}
+
+type StaffMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	Shift         string                 `protobuf:"bytes,4,opt,name=shift,proto3" json:"shift,omitempty"`
+	HospitalId    string                 `protobuf:"bytes,5,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	DepartmentId  string                 `protobuf:"bytes,6,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Deletion      *Deletion              `protobuf:"bytes,7,opt,name=deletion,proto3" json:"deletion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StaffMember) Reset() {
+	*x = StaffMember{}
+	mi := &file_hospital_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StaffMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StaffMember) ProtoMessage() {}
+
+func (x *StaffMember) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StaffMember.ProtoReflect.Descriptor instead.
+func (*StaffMember) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StaffMember) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StaffMember) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StaffMember) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *StaffMember) GetShift() string {
+	if x != nil {
+		return x.Shift
+	}
+	return ""
+}
+
+func (x *StaffMember) GetHospitalId() string {
+	if x != nil {
+		return x.HospitalId
+	}
+	return ""
+}
+
+func (x *StaffMember) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *StaffMember) GetDeletion() *Deletion {
+	if x != nil {
+		return x.Deletion
+	}
+	return nil
+}
+
+type Medicine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Dosage        string                 `protobuf:"bytes,3,opt,name=dosage,proto3" json:"dosage,omitempty"`
+	Manufacturer  string                 `protobuf:"bytes,4,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	Price         float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
+	Deletion      *Deletion              `protobuf:"bytes,7,opt,name=deletion,proto3" json:"deletion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Medicine) Reset() {
+	*x = Medicine{}
+	mi := &file_hospital_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Medicine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Medicine) ProtoMessage() {}
+
+func (x *Medicine) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Medicine.ProtoReflect.Descriptor instead.
+func (*Medicine) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Medicine) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Medicine) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Medicine) GetDosage() string {
+	if x != nil {
+		return x.Dosage
+	}
+	return ""
+}
+
+func (x *Medicine) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *Medicine) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *Medicine) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Medicine) GetDeletion() *Deletion {
+	if x != nil {
+		return x.Deletion
+	}
+	return nil
+}
+
+type Appointment struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PatientId string                 `protobuf:"bytes,2,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	DoctorId  string                 `protobuf:"bytes,3,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	Date      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	Deletion  *Deletion              `protobuf:"bytes,5,opt,name=deletion,proto3" json:"deletion,omitempty"`
+	// booked, checked_in, in_progress, completed, cancelled або no_show
+	Status string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// Час переходів; порожні, якщо запис ще не був у відповідному стані
+	CheckedInAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=checked_in_at,json=checkedInAt,proto3" json:"checked_in_at,omitempty"`
+	StartedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CancelledAt  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	CancelReason string                 `protobuf:"bytes,11,opt,name=cancel_reason,json=cancelReason,proto3" json:"cancel_reason,omitempty"`
+	NoShowAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=no_show_at,json=noShowAt,proto3" json:"no_show_at,omitempty"`
+	// Серія повторюваних записів; порожній для окремого запису
+	SeriesId      string `protobuf:"bytes,13,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Appointment) Reset() {
+	*x = Appointment{}
+	mi := &file_hospital_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Appointment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Appointment) ProtoMessage() {}
+
+func (x *Appointment) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Appointment.ProtoReflect.Descriptor instead.
+func (*Appointment) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Appointment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Appointment) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *Appointment) GetDoctorId() string {
+	if x != nil {
+		return x.DoctorId
+	}
+	return ""
+}
+
+func (x *Appointment) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Appointment) GetDeletion() *Deletion {
+	if x != nil {
+		return x.Deletion
+	}
+	return nil
+}
+
+func (x *Appointment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Appointment) GetCheckedInAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedInAt
+	}
+	return nil
+}
+
+func (x *Appointment) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Appointment) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Appointment) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *Appointment) GetCancelReason() string {
+	if x != nil {
+		return x.CancelReason
+	}
+	return ""
+}
+
+func (x *Appointment) GetNoShowAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NoShowAt
+	}
+	return nil
+}
+
+func (x *Appointment) GetSeriesId() string {
+	if x != nil {
+		return x.SeriesId
+	}
+	return ""
+}
+
+// include_deleted - як ?includeDeleted=true, лише для admin
+type GetRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_hospital_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+// Фільтри списків повторюють query-параметри REST
+type ListHospitalsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	City           string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	Beds           *int32                 `protobuf:"varint,3,opt,name=beds,proto3,oneof" json:"beds,omitempty"`
+	MinBeds        *int32                 `protobuf:"varint,4,opt,name=min_beds,json=minBeds,proto3,oneof" json:"min_beds,omitempty"`
+	MaxBeds        *int32                 `protobuf:"varint,5,opt,name=max_beds,json=maxBeds,proto3,oneof" json:"max_beds,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,6,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListHospitalsRequest) Reset() {
+	*x = ListHospitalsRequest{}
+	mi := &file_hospital_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHospitalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHospitalsRequest) ProtoMessage() {}
+
+func (x *ListHospitalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHospitalsRequest.ProtoReflect.Descriptor instead.
+func (*ListHospitalsRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListHospitalsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetBeds() int32 {
+	if x != nil && x.Beds != nil {
+		return *x.Beds
+	}
+	return 0
+}
+
+func (x *ListHospitalsRequest) GetMinBeds() int32 {
+	if x != nil && x.MinBeds != nil {
+		return *x.MinBeds
+	}
+	return 0
+}
+
+func (x *ListHospitalsRequest) GetMaxBeds() int32 {
+	if x != nil && x.MaxBeds != nil {
+		return *x.MaxBeds
+	}
+	return 0
+}
+
+func (x *ListHospitalsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListDepartmentsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	HospitalId     string                 `protobuf:"bytes,2,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	Floor          *int32                 `protobuf:"varint,3,opt,name=floor,proto3,oneof" json:"floor,omitempty"`
+	MinFloor       *int32                 `protobuf:"varint,4,opt,name=min_floor,json=minFloor,proto3,oneof" json:"min_floor,omitempty"`
+	MaxFloor       *int32                 `protobuf:"varint,5,opt,name=max_floor,json=maxFloor,proto3,oneof" json:"max_floor,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,6,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListDepartmentsRequest) Reset() {
+	*x = ListDepartmentsRequest{}
+	mi := &file_hospital_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDepartmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDepartmentsRequest) ProtoMessage() {}
+
+func (x *ListDepartmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDepartmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListDepartmentsRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDepartmentsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListDepartmentsRequest) GetHospitalId() string {
+	if x != nil {
+		return x.HospitalId
+	}
+	return ""
+}
+
+func (x *ListDepartmentsRequest) GetFloor() int32 {
+	if x != nil && x.Floor != nil {
+		return *x.Floor
+	}
+	return 0
+}
+
+func (x *ListDepartmentsRequest) GetMinFloor() int32 {
+	if x != nil && x.MinFloor != nil {
+		return *x.MinFloor
+	}
+	return 0
+}
+
+func (x *ListDepartmentsRequest) GetMaxFloor() int32 {
+	if x != nil && x.MaxFloor != nil {
+		return *x.MaxFloor
+	}
+	return 0
+}
+
+func (x *ListDepartmentsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListDoctorsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Specialty       string                 `protobuf:"bytes,2,opt,name=specialty,proto3" json:"specialty,omitempty"`
+	DepartmentId    string                 `protobuf:"bytes,3,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	ExperienceYears *int32                 `protobuf:"varint,4,opt,name=experience_years,json=experienceYears,proto3,oneof" json:"experience_years,omitempty"`
+	MinExperience   *int32                 `protobuf:"varint,5,opt,name=min_experience,json=minExperience,proto3,oneof" json:"min_experience,omitempty"`
+	MaxExperience   *int32                 `protobuf:"varint,6,opt,name=max_experience,json=maxExperience,proto3,oneof" json:"max_experience,omitempty"`
+	IncludeDeleted  bool                   `protobuf:"varint,7,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListDoctorsRequest) Reset() {
+	*x = ListDoctorsRequest{}
+	mi := &file_hospital_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDoctorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDoctorsRequest) ProtoMessage() {}
+
+func (x *ListDoctorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDoctorsRequest.ProtoReflect.Descriptor instead.
+func (*ListDoctorsRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListDoctorsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListDoctorsRequest) GetSpecialty() string {
+	if x != nil {
+		return x.Specialty
+	}
+	return ""
+}
+
+func (x *ListDoctorsRequest) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *ListDoctorsRequest) GetExperienceYears() int32 {
+	if x != nil && x.ExperienceYears != nil {
+		return *x.ExperienceYears
+	}
+	return 0
+}
+
+func (x *ListDoctorsRequest) GetMinExperience() int32 {
+	if x != nil && x.MinExperience != nil {
+		return *x.MinExperience
+	}
+	return 0
+}
+
+func (x *ListDoctorsRequest) GetMaxExperience() int32 {
+	if x != nil && x.MaxExperience != nil {
+		return *x.MaxExperience
+	}
+	return 0
+}
+
+func (x *ListDoctorsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListStaffRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Role           string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Shift          string                 `protobuf:"bytes,3,opt,name=shift,proto3" json:"shift,omitempty"`
+	HospitalId     string                 `protobuf:"bytes,4,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	DepartmentId   string                 `protobuf:"bytes,5,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,6,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListStaffRequest) Reset() {
+	*x = ListStaffRequest{}
+	mi := &file_hospital_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStaffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStaffRequest) ProtoMessage() {}
+
+func (x *ListStaffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStaffRequest.ProtoReflect.Descriptor instead.
+func (*ListStaffRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListStaffRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListStaffRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListStaffRequest) GetShift() string {
+	if x != nil {
+		return x.Shift
+	}
+	return ""
+}
+
+func (x *ListStaffRequest) GetHospitalId() string {
+	if x != nil {
+		return x.HospitalId
+	}
+	return ""
+}
+
+func (x *ListStaffRequest) GetDepartmentId() string {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return ""
+}
+
+func (x *ListStaffRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListMedicationsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Dosage         string                 `protobuf:"bytes,2,opt,name=dosage,proto3" json:"dosage,omitempty"`
+	Manufacturer   string                 `protobuf:"bytes,3,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,4,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListMedicationsRequest) Reset() {
+	*x = ListMedicationsRequest{}
+	mi := &file_hospital_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMedicationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMedicationsRequest) ProtoMessage() {}
+
+func (x *ListMedicationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMedicationsRequest.ProtoReflect.Descriptor instead.
+func (*ListMedicationsRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListMedicationsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListMedicationsRequest) GetDosage() string {
+	if x != nil {
+		return x.Dosage
+	}
+	return ""
+}
+
+func (x *ListMedicationsRequest) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *ListMedicationsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListAppointmentsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PatientId string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	DoctorId  string                 `protobuf:"bytes,2,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	// date - день у форматі YYYY-MM-DD
+	Date           string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	IncludeDeleted bool   `protobuf:"varint,4,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	Status         string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListAppointmentsRequest) Reset() {
+	*x = ListAppointmentsRequest{}
+	mi := &file_hospital_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppointmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppointmentsRequest) ProtoMessage() {}
+
+func (x *ListAppointmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppointmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAppointmentsRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListAppointmentsRequest) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *ListAppointmentsRequest) GetDoctorId() string {
+	if x != nil {
+		return x.DoctorId
+	}
+	return ""
+}
+
+func (x *ListAppointmentsRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ListAppointmentsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *ListAppointmentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type CreateAppointmentRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PatientId string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	DoctorId  string                 `protobuf:"bytes,2,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	// Без дати запис створюється на поточний час
+	Date          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAppointmentRequest) Reset() {
+	*x = CreateAppointmentRequest{}
+	mi := &file_hospital_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAppointmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAppointmentRequest) ProtoMessage() {}
+
+func (x *CreateAppointmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAppointmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateAppointmentRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateAppointmentRequest) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *CreateAppointmentRequest) GetDoctorId() string {
+	if x != nil {
+		return x.DoctorId
+	}
+	return ""
+}
+
+func (x *CreateAppointmentRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+type DeleteAppointmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAppointmentRequest) Reset() {
+	*x = DeleteAppointmentRequest{}
+	mi := &file_hospital_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAppointmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAppointmentRequest) ProtoMessage() {}
+
+func (x *DeleteAppointmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAppointmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAppointmentRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteAppointmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteAppointmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAppointmentResponse) Reset() {
+	*x = DeleteAppointmentResponse{}
+	mi := &file_hospital_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAppointmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAppointmentResponse) ProtoMessage() {}
+
+func (x *DeleteAppointmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAppointmentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAppointmentResponse) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{16}
+}
+
+// ids - лише ці записи; порожній список - усі
+type WatchAppointmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAppointmentsRequest) Reset() {
+	*x = WatchAppointmentsRequest{}
+	mi := &file_hospital_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAppointmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAppointmentsRequest) ProtoMessage() {}
+
+func (x *WatchAppointmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAppointmentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAppointmentsRequest) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchAppointmentsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type AppointmentEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          AppointmentEvent_Type  `protobuf:"varint,2,opt,name=type,proto3,enum=hospital.v1.AppointmentEvent_Type" json:"type,omitempty"`
+	AppointmentId string                 `protobuf:"bytes,3,opt,name=appointment_id,json=appointmentId,proto3" json:"appointment_id,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	// Запис після зміни; порожній для DELETED
+	Appointment   *Appointment `protobuf:"bytes,5,opt,name=appointment,proto3" json:"appointment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppointmentEvent) Reset() {
+	*x = AppointmentEvent{}
+	mi := &file_hospital_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppointmentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppointmentEvent) ProtoMessage() {}
+
+func (x *AppointmentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hospital_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppointmentEvent.ProtoReflect.Descriptor instead.
+func (*AppointmentEvent) Descriptor() ([]byte, []int) {
+	return file_hospital_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AppointmentEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AppointmentEvent) GetType() AppointmentEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return AppointmentEvent_TYPE_UNSPECIFIED
+}
+
+func (x *AppointmentEvent) GetAppointmentId() string {
+	if x != nil {
+		return x.AppointmentId
+	}
+	return ""
+}
+
+func (x *AppointmentEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AppointmentEvent) GetAppointment() *Appointment {
+	if x != nil {
+		return x.Appointment
+	}
+	return nil
+}
+
+var File_hospital_proto protoreflect.FileDescriptor
+
+const file_hospital_proto_rawDesc = "" +
+	"\n" +
+	"\x0ehospital.proto\x12\vhospital.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"d\n" +
+	"\bDeletion\x129\n" +
+	"\n" +
+	"deleted_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x1d\n" +
+	"\n" +
+	"deleted_by\x18\x02 \x01(\tR\tdeletedBy\"\x91\x01\n" +
+	"\bHospital\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x12\n" +
+	"\x04beds\x18\x04 \x01(\x05R\x04beds\x121\n" +
+	"\bdeletion\x18\x05 \x01(\v2\x15.hospital.v1.DeletionR\bdeletion\"\x9a\x01\n" +
+	"\n" +
+	"Department\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vhospital_id\x18\x03 \x01(\tR\n" +
+	"hospitalId\x12\x14\n" +
+	"\x05floor\x18\x04 \x01(\x05R\x05floor\x121\n" +
+	"\bdeletion\x18\x05 \x01(\v2\x15.hospital.v1.DeletionR\bdeletion\"\xcd\x01\n" +
+	"\x06Doctor\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tspecialty\x18\x03 \x01(\tR\tspecialty\x12#\n" +
+	"\rdepartment_id\x18\x04 \x01(\tR\fdepartmentId\x12)\n" +
+	"\x10experience_years\x18\x05 \x01(\x05R\x0fexperienceYears\x121\n" +
+	"\bdeletion\x18\x06 \x01(\v2\x15.hospital.v1.DeletionR\bdeletion\"\xd4\x01\n" +
+	"\vStaffMember\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x14\n" +
+	"\x05shift\x18\x04 \x01(\tR\x05shift\x12\x1f\n" +
+	"\vhospital_id\x18\x05 \x01(\tR\n" +
+	"hospitalId\x12#\n" +
+	"\rdepartment_id\x18\x06 \x01(\tR\fdepartmentId\x121\n" +
+	"\bdeletion\x18\a \x01(\v2\x15.hospital.v1.DeletionR\bdeletion\"\xc9\x01\n" +
+	"\bMedicine\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06dosage\x18\x03 \x01(\tR\x06dosage\x12\"\n" +
+	"\fmanufacturer\x18\x04 \x01(\tR\fmanufacturer\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x01R\x05price\x121\n" +
+	"\bdeletion\x18\a \x01(\v2\x15.hospital.v1.DeletionR\bdeletion\"\xc9\x04\n" +
+	"\vAppointment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x02 \x01(\tR\tpatientId\x12\x1b\n" +
+	"\tdoctor_id\x18\x03 \x01(\tR\bdoctorId\x12.\n" +
+	"\x04date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x121\n" +
+	"\bdeletion\x18\x05 \x01(\v2\x15.hospital.v1.DeletionR\bdeletion\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12>\n" +
+	"\rchecked_in_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcheckedInAt\x129\n" +
+	"\n" +
+	"started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12=\n" +
+	"\fcancelled_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x12#\n" +
+	"\rcancel_reason\x18\v \x01(\tR\fcancelReason\x128\n" +
+	"\n" +
+	"no_show_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\bnoShowAt\x12\x1b\n" +
+	"\tseries_id\x18\r \x01(\tR\bseriesId\"E\n" +
+	"\n" +
+	"GetRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xe3\x01\n" +
+	"\x14ListHospitalsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x17\n" +
+	"\x04beds\x18\x03 \x01(\x05H\x00R\x04beds\x88\x01\x01\x12\x1e\n" +
+	"\bmin_beds\x18\x04 \x01(\x05H\x01R\aminBeds\x88\x01\x01\x12\x1e\n" +
+	"\bmax_beds\x18\x05 \x01(\x05H\x02R\amaxBeds\x88\x01\x01\x12'\n" +
+	"\x0finclude_deleted\x18\x06 \x01(\bR\x0eincludeDeletedB\a\n" +
+	"\x05_bedsB\v\n" +
+	"\t_min_bedsB\v\n" +
+	"\t_max_beds\"\xfb\x01\n" +
+	"\x16ListDepartmentsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vhospital_id\x18\x02 \x01(\tR\n" +
+	"hospitalId\x12\x19\n" +
+	"\x05floor\x18\x03 \x01(\x05H\x00R\x05floor\x88\x01\x01\x12 \n" +
+	"\tmin_floor\x18\x04 \x01(\x05H\x01R\bminFloor\x88\x01\x01\x12 \n" +
+	"\tmax_floor\x18\x05 \x01(\x05H\x02R\bmaxFloor\x88\x01\x01\x12'\n" +
+	"\x0finclude_deleted\x18\x06 \x01(\bR\x0eincludeDeletedB\b\n" +
+	"\x06_floorB\f\n" +
+	"\n" +
+	"_min_floorB\f\n" +
+	"\n" +
+	"_max_floor\"\xd7\x02\n" +
+	"\x12ListDoctorsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\tspecialty\x18\x02 \x01(\tR\tspecialty\x12#\n" +
+	"\rdepartment_id\x18\x03 \x01(\tR\fdepartmentId\x12.\n" +
+	"\x10experience_years\x18\x04 \x01(\x05H\x00R\x0fexperienceYears\x88\x01\x01\x12*\n" +
+	"\x0emin_experience\x18\x05 \x01(\x05H\x01R\rminExperience\x88\x01\x01\x12*\n" +
+	"\x0emax_experience\x18\x06 \x01(\x05H\x02R\rmaxExperience\x88\x01\x01\x12'\n" +
+	"\x0finclude_deleted\x18\a \x01(\bR\x0eincludeDeletedB\x13\n" +
+	"\x11_experience_yearsB\x11\n" +
+	"\x0f_min_experienceB\x11\n" +
+	"\x0f_max_experience\"\xbf\x01\n" +
+	"\x10ListStaffRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x14\n" +
+	"\x05shift\x18\x03 \x01(\tR\x05shift\x12\x1f\n" +
+	"\vhospital_id\x18\x04 \x01(\tR\n" +
+	"hospitalId\x12#\n" +
+	"\rdepartment_id\x18\x05 \x01(\tR\fdepartmentId\x12'\n" +
+	"\x0finclude_deleted\x18\x06 \x01(\bR\x0eincludeDeleted\"\x91\x01\n" +
+	"\x16ListMedicationsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06dosage\x18\x02 \x01(\tR\x06dosage\x12\"\n" +
+	"\fmanufacturer\x18\x03 \x01(\tR\fmanufacturer\x12'\n" +
+	"\x0finclude_deleted\x18\x04 \x01(\bR\x0eincludeDeleted\"\xaa\x01\n" +
+	"\x17ListAppointmentsRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12\x1b\n" +
+	"\tdoctor_id\x18\x02 \x01(\tR\bdoctorId\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12'\n" +
+	"\x0finclude_deleted\x18\x04 \x01(\bR\x0eincludeDeleted\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"\x86\x01\n" +
+	"\x18CreateAppointmentRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12\x1b\n" +
+	"\tdoctor_id\x18\x02 \x01(\tR\bdoctorId\x12.\n" +
+	"\x04date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\"*\n" +
+	"\x18DeleteAppointmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1b\n" +
+	"\x19DeleteAppointmentResponse\",\n" +
+	"\x18WatchAppointmentsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\xb2\x02\n" +
+	"\x10AppointmentEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\x04type\x18\x02 \x01(\x0e2\".hospital.v1.AppointmentEvent.TypeR\x04type\x12%\n" +
+	"\x0eappointment_id\x18\x03 \x01(\tR\rappointmentId\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12:\n" +
+	"\vappointment\x18\x05 \x01(\v2\x18.hospital.v1.AppointmentR\vappointment\"C\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCREATED\x10\x01\x12\v\n" +
+	"\aUPDATED\x10\x02\x12\v\n" +
+	"\aDELETED\x10\x032\x9d\x01\n" +
+	"\x0fHospitalService\x12=\n" +
+	"\vGetHospital\x12\x17.hospital.v1.GetRequest\x1a\x15.hospital.v1.Hospital\x12K\n" +
+	"\rListHospitals\x12!.hospital.v1.ListHospitalsRequest\x1a\x15.hospital.v1.Hospital0\x012\xa9\x01\n" +
+	"\x11DepartmentService\x12A\n" +
+	"\rGetDepartment\x12\x17.hospital.v1.GetRequest\x1a\x17.hospital.v1.Department\x12Q\n" +
+	"\x0fListDepartments\x12#.hospital.v1.ListDepartmentsRequest\x1a\x17.hospital.v1.Department0\x012\x91\x01\n" +
+	"\rDoctorService\x129\n" +
+	"\tGetDoctor\x12\x17.hospital.v1.GetRequest\x1a\x13.hospital.v1.Doctor\x12E\n" +
+	"\vListDoctors\x12\x1f.hospital.v1.ListDoctorsRequest\x1a\x13.hospital.v1.Doctor0\x012\x9b\x01\n" +
+	"\fStaffService\x12C\n" +
+	"\x0eGetStaffMember\x12\x17.hospital.v1.GetRequest\x1a\x18.hospital.v1.StaffMember\x12F\n" +
+	"\tListStaff\x12\x1d.hospital.v1.ListStaffRequest\x1a\x18.hospital.v1.StaffMember0\x012\xa3\x01\n" +
+	"\x11MedicationService\x12=\n" +
+	"\vGetMedicine\x12\x17.hospital.v1.GetRequest\x1a\x15.hospital.v1.Medicine\x12O\n" +
+	"\x0fListMedications\x12#.hospital.v1.ListMedicationsRequest\x1a\x15.hospital.v1.Medicine0\x012\xc6\x03\n" +
+	"\x12AppointmentService\x12C\n" +
+	"\x0eGetAppointment\x12\x17.hospital.v1.GetRequest\x1a\x18.hospital.v1.Appointment\x12T\n" +
+	"\x10ListAppointments\x12$.hospital.v1.ListAppointmentsRequest\x1a\x18.hospital.v1.Appointment0\x01\x12T\n" +
+	"\x11CreateAppointment\x12%.hospital.v1.CreateAppointmentRequest\x1a\x18.hospital.v1.Appointment\x12b\n" +
+	"\x11DeleteAppointment\x12%.hospital.v1.DeleteAppointmentRequest\x1a&.hospital.v1.DeleteAppointmentResponse\x12[\n" +
+	"\x11WatchAppointments\x12%.hospital.v1.WatchAppointmentsRequest\x1a\x1d.hospital.v1.AppointmentEvent0\x01B*Z(hospital-api/proto/hospitalpb;hospitalpbb\x06proto3"
+
+var (
+	file_hospital_proto_rawDescOnce sync.Once
+	file_hospital_proto_rawDescData []byte
+)
+
+func file_hospital_proto_rawDescGZIP() []byte {
+	file_hospital_proto_rawDescOnce.Do(func() {
+		file_hospital_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hospital_proto_rawDesc), len(file_hospital_proto_rawDesc)))
+	})
+	return file_hospital_proto_rawDescData
+}
+
+var file_hospital_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_hospital_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_hospital_proto_goTypes = []any{
+	(AppointmentEvent_Type)(0),        // 0: hospital.v1.AppointmentEvent.Type
+	(*Deletion)(nil),                  // 1: hospital.v1.Deletion
+	(*Hospital)(nil),                  // 2: hospital.v1.Hospital
+	(*Department)(nil),                // 3: hospital.v1.Department
+	(*Doctor)(nil),                    // 4: hospital.v1.Doctor
+	(*StaffMember)(nil),               // 5: hospital.v1.StaffMember
+	(*Medicine)(nil),                  // 6: hospital.v1.Medicine
+	(*Appointment)(nil),               // 7: hospital.v1.Appointment
+	(*GetRequest)(nil),                // 8: hospital.v1.GetRequest
+	(*ListHospitalsRequest)(nil),      // 9: hospital.v1.ListHospitalsRequest
+	(*ListDepartmentsRequest)(nil),    // 10: hospital.v1.ListDepartmentsRequest
+	(*ListDoctorsRequest)(nil),        // 11: hospital.v1.ListDoctorsRequest
+	(*ListStaffRequest)(nil),          // 12: hospital.v1.ListStaffRequest
+	(*ListMedicationsRequest)(nil),    // 13: hospital.v1.ListMedicationsRequest
+	(*ListAppointmentsRequest)(nil),   // 14: hospital.v1.ListAppointmentsRequest
+	(*CreateAppointmentRequest)(nil),  // 15: hospital.v1.CreateAppointmentRequest
+	(*DeleteAppointmentRequest)(nil),  // 16: hospital.v1.DeleteAppointmentRequest
+	(*DeleteAppointmentResponse)(nil), // 17: hospital.v1.DeleteAppointmentResponse
+	(*WatchAppointmentsRequest)(nil),  // 18: hospital.v1.WatchAppointmentsRequest
+	(*AppointmentEvent)(nil),          // 19: hospital.v1.AppointmentEvent
+	(*timestamppb.Timestamp)(nil),     // 20: google.protobuf.Timestamp
+}
+var file_hospital_proto_depIdxs = []int32{
+	20, // 0: hospital.v1.Deletion.deleted_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: hospital.v1.Hospital.deletion:type_name -> hospital.v1.Deletion
+	1,  // 2: hospital.v1.Department.deletion:type_name -> hospital.v1.Deletion
+	1,  // 3: hospital.v1.Doctor.deletion:type_name -> hospital.v1.Deletion
+	1,  // 4: hospital.v1.StaffMember.deletion:type_name -> hospital.v1.Deletion
+	1,  // 5: hospital.v1.Medicine.deletion:type_name -> hospital.v1.Deletion
+	20, // 6: hospital.v1.Appointment.date:type_name -> google.protobuf.Timestamp
+	1,  // 7: hospital.v1.Appointment.deletion:type_name -> hospital.v1.Deletion
+	20, // 8: hospital.v1.Appointment.checked_in_at:type_name -> google.protobuf.Timestamp
+	20, // 9: hospital.v1.Appointment.started_at:type_name -> google.protobuf.Timestamp
+	20, // 10: hospital.v1.Appointment.completed_at:type_name -> google.protobuf.Timestamp
+	20, // 11: hospital.v1.Appointment.cancelled_at:type_name -> google.protobuf.Timestamp
+	20, // 12: hospital.v1.Appointment.no_show_at:type_name -> google.protobuf.Timestamp
+	20, // 13: hospital.v1.CreateAppointmentRequest.date:type_name -> google.protobuf.Timestamp
+	0,  // 14: hospital.v1.AppointmentEvent.type:type_name -> hospital.v1.AppointmentEvent.Type
+	20, // 15: hospital.v1.AppointmentEvent.time:type_name -> google.protobuf.Timestamp
+	7,  // 16: hospital.v1.AppointmentEvent.appointment:type_name -> hospital.v1.Appointment
+	8,  // 17: hospital.v1.HospitalService.GetHospital:input_type -> hospital.v1.GetRequest
+	9,  // 18: hospital.v1.HospitalService.ListHospitals:input_type -> hospital.v1.ListHospitalsRequest
+	8,  // 19: hospital.v1.DepartmentService.GetDepartment:input_type -> hospital.v1.GetRequest
+	10, // 20: hospital.v1.DepartmentService.ListDepartments:input_type -> hospital.v1.ListDepartmentsRequest
+	8,  // 21: hospital.v1.DoctorService.GetDoctor:input_type -> hospital.v1.GetRequest
+	11, // 22: hospital.v1.DoctorService.ListDoctors:input_type -> hospital.v1.ListDoctorsRequest
+	8,  // 23: hospital.v1.StaffService.GetStaffMember:input_type -> hospital.v1.GetRequest
+	12, // 24: hospital.v1.StaffService.ListStaff:input_type -> hospital.v1.ListStaffRequest
+	8,  // 25: hospital.v1.MedicationService.GetMedicine:input_type -> hospital.v1.GetRequest
+	13, // 26: hospital.v1.MedicationService.ListMedications:input_type -> hospital.v1.ListMedicationsRequest
+	8,  // 27: hospital.v1.AppointmentService.GetAppointment:input_type -> hospital.v1.GetRequest
+	14, // 28: hospital.v1.AppointmentService.ListAppointments:input_type -> hospital.v1.ListAppointmentsRequest
+	15, // 29: hospital.v1.AppointmentService.CreateAppointment:input_type -> hospital.v1.CreateAppointmentRequest
+	16, // 30: hospital.v1.AppointmentService.DeleteAppointment:input_type -> hospital.v1.DeleteAppointmentRequest
+	18, // 31: hospital.v1.AppointmentService.WatchAppointments:input_type -> hospital.v1.WatchAppointmentsRequest
+	2,  // 32: hospital.v1.HospitalService.GetHospital:output_type -> hospital.v1.Hospital
+	2,  // 33: hospital.v1.HospitalService.ListHospitals:output_type -> hospital.v1.Hospital
+	3,  // 34: hospital.v1.DepartmentService.GetDepartment:output_type -> hospital.v1.Department
+	3,  // 35: hospital.v1.DepartmentService.ListDepartments:output_type -> hospital.v1.Department
+	4,  // 36: hospital.v1.DoctorService.GetDoctor:output_type -> hospital.v1.Doctor
+	4,  // 37: hospital.v1.DoctorService.ListDoctors:output_type -> hospital.v1.Doctor
+	5,  // 38: hospital.v1.StaffService.GetStaffMember:output_type -> hospital.v1.StaffMember
+	5,  // 39: hospital.v1.StaffService.ListStaff:output_type -> hospital.v1.StaffMember
+	6,  // 40: hospital.v1.MedicationService.GetMedicine:output_type -> hospital.v1.Medicine
+	6,  // 41: hospital.v1.MedicationService.ListMedications:output_type -> hospital.v1.Medicine
+	7,  // 42: hospital.v1.AppointmentService.GetAppointment:output_type -> hospital.v1.Appointment
+	7,  // 43: hospital.v1.AppointmentService.ListAppointments:output_type -> hospital.v1.Appointment
+	7,  // 44: hospital.v1.AppointmentService.CreateAppointment:output_type -> hospital.v1.Appointment
+	17, // 45: hospital.v1.AppointmentService.DeleteAppointment:output_type -> hospital.v1.DeleteAppointmentResponse
+	19, // 46: hospital.v1.AppointmentService.WatchAppointments:output_type -> hospital.v1.AppointmentEvent
+	32, // [32:47] is the sub-list for method output_type
+	17, // [17:32] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_hospital_proto_init() }
+func file_hospital_proto_init() {
+	if File_hospital_proto != nil {
+		return
+	}
+	file_hospital_proto_msgTypes[8].OneofWrappers = []any{}
+	file_hospital_proto_msgTypes[9].OneofWrappers = []any{}
+	file_hospital_proto_msgTypes[10].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hospital_proto_rawDesc), len(file_hospital_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   6,
+		},
+		GoTypes:           file_hospital_proto_goTypes,
+		DependencyIndexes: file_hospital_proto_depIdxs,
+		EnumInfos:         file_hospital_proto_enumTypes,
+		MessageInfos:      file_hospital_proto_msgTypes,
+	}.Build()
+	File_hospital_proto = out.File
+	file_hospital_proto_goTypes = nil
+	file_hospital_proto_depIdxs = nil
+}
@@ -0,0 +1,1006 @@
+// gRPC API лікарень. Ті самі дані й правила доступу, що й у REST:
+// лікарні, відділення й лікарі - за ключем x-api-key, працівники й записи -
+// за JWT (authorization: Bearer ...), ліки - без авторизації.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: hospital.proto
+
+package hospitalpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HospitalService_GetHospital_FullMethodName   = "/hospital.v1.HospitalService/GetHospital"
+	HospitalService_ListHospitals_FullMethodName = "/hospital.v1.HospitalService/ListHospitals"
+)
+
+// HospitalServiceClient is the client API for HospitalService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HospitalServiceClient interface {
+	GetHospital(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Hospital, error)
+	ListHospitals(ctx context.Context, in *ListHospitalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Hospital], error)
+}
+
+type hospitalServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHospitalServiceClient(cc grpc.ClientConnInterface) HospitalServiceClient {
+	return &hospitalServiceClient{cc}
+}
+
+func (c *hospitalServiceClient) GetHospital(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Hospital, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Hospital)
+	err := c.cc.Invoke(ctx, HospitalService_GetHospital_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hospitalServiceClient) ListHospitals(ctx context.Context, in *ListHospitalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Hospital], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &HospitalService_ServiceDesc.Streams[0], HospitalService_ListHospitals_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListHospitalsRequest, Hospital]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type HospitalService_ListHospitalsClient = grpc.ServerStreamingClient[Hospital]
+
+// HospitalServiceServer is the server API for HospitalService service.
+// All implementations must embed UnimplementedHospitalServiceServer
+// for forward compatibility.
+type HospitalServiceServer interface {
+	GetHospital(context.Context, *GetRequest) (*Hospital, error)
+	ListHospitals(*ListHospitalsRequest, grpc.ServerStreamingServer[Hospital]) error
+	mustEmbedUnimplementedHospitalServiceServer()
+}
+
+// UnimplementedHospitalServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHospitalServiceServer struct{}
+
+func (UnimplementedHospitalServiceServer) GetHospital(context.Context, *GetRequest) (*Hospital, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHospital not implemented")
+}
+func (UnimplementedHospitalServiceServer) ListHospitals(*ListHospitalsRequest, grpc.ServerStreamingServer[Hospital]) error {
+	return status.Errorf(codes.Unimplemented, "method ListHospitals not implemented")
+}
+func (UnimplementedHospitalServiceServer) mustEmbedUnimplementedHospitalServiceServer() {}
+func (UnimplementedHospitalServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeHospitalServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HospitalServiceServer will
+// result in compilation errors.
+type UnsafeHospitalServiceServer interface {
+	mustEmbedUnimplementedHospitalServiceServer()
+}
+
+func RegisterHospitalServiceServer(s grpc.ServiceRegistrar, srv HospitalServiceServer) {
+	// If the following call pancis, it indicates UnimplementedHospitalServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HospitalService_ServiceDesc, srv)
+}
+
+func _HospitalService_GetHospital_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HospitalServiceServer).GetHospital(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HospitalService_GetHospital_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HospitalServiceServer).GetHospital(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HospitalService_ListHospitals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListHospitalsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HospitalServiceServer).ListHospitals(m, &grpc.GenericServerStream[ListHospitalsRequest, Hospital]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type HospitalService_ListHospitalsServer = grpc.ServerStreamingServer[Hospital]
+
+// HospitalService_ServiceDesc is the grpc.ServiceDesc for HospitalService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HospitalService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.v1.HospitalService",
+	HandlerType: (*HospitalServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHospital",
+			Handler:    _HospitalService_GetHospital_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListHospitals",
+			Handler:       _HospitalService_ListHospitals_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hospital.proto",
+}
+
+const (
+	DepartmentService_GetDepartment_FullMethodName   = "/hospital.v1.DepartmentService/GetDepartment"
+	DepartmentService_ListDepartments_FullMethodName = "/hospital.v1.DepartmentService/ListDepartments"
+)
+
+// DepartmentServiceClient is the client API for DepartmentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DepartmentServiceClient interface {
+	GetDepartment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Department, error)
+	ListDepartments(ctx context.Context, in *ListDepartmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Department], error)
+}
+
+type departmentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDepartmentServiceClient(cc grpc.ClientConnInterface) DepartmentServiceClient {
+	return &departmentServiceClient{cc}
+}
+
+func (c *departmentServiceClient) GetDepartment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Department, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Department)
+	err := c.cc.Invoke(ctx, DepartmentService_GetDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *departmentServiceClient) ListDepartments(ctx context.Context, in *ListDepartmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Department], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DepartmentService_ServiceDesc.Streams[0], DepartmentService_ListDepartments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListDepartmentsRequest, Department]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DepartmentService_ListDepartmentsClient = grpc.ServerStreamingClient[Department]
+
+// DepartmentServiceServer is the server API for DepartmentService service.
+// All implementations must embed UnimplementedDepartmentServiceServer
+// for forward compatibility.
+type DepartmentServiceServer interface {
+	GetDepartment(context.Context, *GetRequest) (*Department, error)
+	ListDepartments(*ListDepartmentsRequest, grpc.ServerStreamingServer[Department]) error
+	mustEmbedUnimplementedDepartmentServiceServer()
+}
+
+// UnimplementedDepartmentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDepartmentServiceServer struct{}
+
+func (UnimplementedDepartmentServiceServer) GetDepartment(context.Context, *GetRequest) (*Department, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDepartment not implemented")
+}
+func (UnimplementedDepartmentServiceServer) ListDepartments(*ListDepartmentsRequest, grpc.ServerStreamingServer[Department]) error {
+	return status.Errorf(codes.Unimplemented, "method ListDepartments not implemented")
+}
+func (UnimplementedDepartmentServiceServer) mustEmbedUnimplementedDepartmentServiceServer() {}
+func (UnimplementedDepartmentServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeDepartmentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DepartmentServiceServer will
+// result in compilation errors.
+type UnsafeDepartmentServiceServer interface {
+	mustEmbedUnimplementedDepartmentServiceServer()
+}
+
+func RegisterDepartmentServiceServer(s grpc.ServiceRegistrar, srv DepartmentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDepartmentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DepartmentService_ServiceDesc, srv)
+}
+
+func _DepartmentService_GetDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DepartmentServiceServer).GetDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DepartmentService_GetDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DepartmentServiceServer).GetDepartment(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DepartmentService_ListDepartments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDepartmentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DepartmentServiceServer).ListDepartments(m, &grpc.GenericServerStream[ListDepartmentsRequest, Department]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DepartmentService_ListDepartmentsServer = grpc.ServerStreamingServer[Department]
+
+// DepartmentService_ServiceDesc is the grpc.ServiceDesc for DepartmentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DepartmentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.v1.DepartmentService",
+	HandlerType: (*DepartmentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDepartment",
+			Handler:    _DepartmentService_GetDepartment_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListDepartments",
+			Handler:       _DepartmentService_ListDepartments_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hospital.proto",
+}
+
+const (
+	DoctorService_GetDoctor_FullMethodName   = "/hospital.v1.DoctorService/GetDoctor"
+	DoctorService_ListDoctors_FullMethodName = "/hospital.v1.DoctorService/ListDoctors"
+)
+
+// DoctorServiceClient is the client API for DoctorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DoctorServiceClient interface {
+	GetDoctor(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Doctor, error)
+	ListDoctors(ctx context.Context, in *ListDoctorsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Doctor], error)
+}
+
+type doctorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDoctorServiceClient(cc grpc.ClientConnInterface) DoctorServiceClient {
+	return &doctorServiceClient{cc}
+}
+
+func (c *doctorServiceClient) GetDoctor(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Doctor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Doctor)
+	err := c.cc.Invoke(ctx, DoctorService_GetDoctor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *doctorServiceClient) ListDoctors(ctx context.Context, in *ListDoctorsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Doctor], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DoctorService_ServiceDesc.Streams[0], DoctorService_ListDoctors_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListDoctorsRequest, Doctor]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DoctorService_ListDoctorsClient = grpc.ServerStreamingClient[Doctor]
+
+// DoctorServiceServer is the server API for DoctorService service.
+// All implementations must embed UnimplementedDoctorServiceServer
+// for forward compatibility.
+type DoctorServiceServer interface {
+	GetDoctor(context.Context, *GetRequest) (*Doctor, error)
+	ListDoctors(*ListDoctorsRequest, grpc.ServerStreamingServer[Doctor]) error
+	mustEmbedUnimplementedDoctorServiceServer()
+}
+
+// UnimplementedDoctorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDoctorServiceServer struct{}
+
+func (UnimplementedDoctorServiceServer) GetDoctor(context.Context, *GetRequest) (*Doctor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDoctor not implemented")
+}
+func (UnimplementedDoctorServiceServer) ListDoctors(*ListDoctorsRequest, grpc.ServerStreamingServer[Doctor]) error {
+	return status.Errorf(codes.Unimplemented, "method ListDoctors not implemented")
+}
+func (UnimplementedDoctorServiceServer) mustEmbedUnimplementedDoctorServiceServer() {}
+func (UnimplementedDoctorServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeDoctorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DoctorServiceServer will
+// result in compilation errors.
+type UnsafeDoctorServiceServer interface {
+	mustEmbedUnimplementedDoctorServiceServer()
+}
+
+func RegisterDoctorServiceServer(s grpc.ServiceRegistrar, srv DoctorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDoctorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DoctorService_ServiceDesc, srv)
+}
+
+func _DoctorService_GetDoctor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DoctorServiceServer).GetDoctor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DoctorService_GetDoctor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DoctorServiceServer).GetDoctor(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DoctorService_ListDoctors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDoctorsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DoctorServiceServer).ListDoctors(m, &grpc.GenericServerStream[ListDoctorsRequest, Doctor]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DoctorService_ListDoctorsServer = grpc.ServerStreamingServer[Doctor]
+
+// DoctorService_ServiceDesc is the grpc.ServiceDesc for DoctorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DoctorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.v1.DoctorService",
+	HandlerType: (*DoctorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDoctor",
+			Handler:    _DoctorService_GetDoctor_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListDoctors",
+			Handler:       _DoctorService_ListDoctors_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hospital.proto",
+}
+
+const (
+	StaffService_GetStaffMember_FullMethodName = "/hospital.v1.StaffService/GetStaffMember"
+	StaffService_ListStaff_FullMethodName      = "/hospital.v1.StaffService/ListStaff"
+)
+
+// StaffServiceClient is the client API for StaffService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StaffServiceClient interface {
+	GetStaffMember(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*StaffMember, error)
+	ListStaff(ctx context.Context, in *ListStaffRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StaffMember], error)
+}
+
+type staffServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStaffServiceClient(cc grpc.ClientConnInterface) StaffServiceClient {
+	return &staffServiceClient{cc}
+}
+
+func (c *staffServiceClient) GetStaffMember(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*StaffMember, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StaffMember)
+	err := c.cc.Invoke(ctx, StaffService_GetStaffMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *staffServiceClient) ListStaff(ctx context.Context, in *ListStaffRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StaffMember], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StaffService_ServiceDesc.Streams[0], StaffService_ListStaff_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListStaffRequest, StaffMember]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StaffService_ListStaffClient = grpc.ServerStreamingClient[StaffMember]
+
+// StaffServiceServer is the server API for StaffService service.
+// All implementations must embed UnimplementedStaffServiceServer
+// for forward compatibility.
+type StaffServiceServer interface {
+	GetStaffMember(context.Context, *GetRequest) (*StaffMember, error)
+	ListStaff(*ListStaffRequest, grpc.ServerStreamingServer[StaffMember]) error
+	mustEmbedUnimplementedStaffServiceServer()
+}
+
+// UnimplementedStaffServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStaffServiceServer struct{}
+
+func (UnimplementedStaffServiceServer) GetStaffMember(context.Context, *GetRequest) (*StaffMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStaffMember not implemented")
+}
+func (UnimplementedStaffServiceServer) ListStaff(*ListStaffRequest, grpc.ServerStreamingServer[StaffMember]) error {
+	return status.Errorf(codes.Unimplemented, "method ListStaff not implemented")
+}
+func (UnimplementedStaffServiceServer) mustEmbedUnimplementedStaffServiceServer() {}
+func (UnimplementedStaffServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeStaffServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StaffServiceServer will
+// result in compilation errors.
+type UnsafeStaffServiceServer interface {
+	mustEmbedUnimplementedStaffServiceServer()
+}
+
+func RegisterStaffServiceServer(s grpc.ServiceRegistrar, srv StaffServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStaffServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StaffService_ServiceDesc, srv)
+}
+
+func _StaffService_GetStaffMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StaffServiceServer).GetStaffMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StaffService_GetStaffMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StaffServiceServer).GetStaffMember(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StaffService_ListStaff_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListStaffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StaffServiceServer).ListStaff(m, &grpc.GenericServerStream[ListStaffRequest, StaffMember]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StaffService_ListStaffServer = grpc.ServerStreamingServer[StaffMember]
+
+// StaffService_ServiceDesc is the grpc.ServiceDesc for StaffService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StaffService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.v1.StaffService",
+	HandlerType: (*StaffServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStaffMember",
+			Handler:    _StaffService_GetStaffMember_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListStaff",
+			Handler:       _StaffService_ListStaff_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hospital.proto",
+}
+
+const (
+	MedicationService_GetMedicine_FullMethodName     = "/hospital.v1.MedicationService/GetMedicine"
+	MedicationService_ListMedications_FullMethodName = "/hospital.v1.MedicationService/ListMedications"
+)
+
+// MedicationServiceClient is the client API for MedicationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MedicationServiceClient interface {
+	GetMedicine(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Medicine, error)
+	ListMedications(ctx context.Context, in *ListMedicationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Medicine], error)
+}
+
+type medicationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMedicationServiceClient(cc grpc.ClientConnInterface) MedicationServiceClient {
+	return &medicationServiceClient{cc}
+}
+
+func (c *medicationServiceClient) GetMedicine(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Medicine, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Medicine)
+	err := c.cc.Invoke(ctx, MedicationService_GetMedicine_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *medicationServiceClient) ListMedications(ctx context.Context, in *ListMedicationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Medicine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MedicationService_ServiceDesc.Streams[0], MedicationService_ListMedications_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListMedicationsRequest, Medicine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MedicationService_ListMedicationsClient = grpc.ServerStreamingClient[Medicine]
+
+// MedicationServiceServer is the server API for MedicationService service.
+// All implementations must embed UnimplementedMedicationServiceServer
+// for forward compatibility.
+type MedicationServiceServer interface {
+	GetMedicine(context.Context, *GetRequest) (*Medicine, error)
+	ListMedications(*ListMedicationsRequest, grpc.ServerStreamingServer[Medicine]) error
+	mustEmbedUnimplementedMedicationServiceServer()
+}
+
+// UnimplementedMedicationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMedicationServiceServer struct{}
+
+func (UnimplementedMedicationServiceServer) GetMedicine(context.Context, *GetRequest) (*Medicine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMedicine not implemented")
+}
+func (UnimplementedMedicationServiceServer) ListMedications(*ListMedicationsRequest, grpc.ServerStreamingServer[Medicine]) error {
+	return status.Errorf(codes.Unimplemented, "method ListMedications not implemented")
+}
+func (UnimplementedMedicationServiceServer) mustEmbedUnimplementedMedicationServiceServer() {}
+func (UnimplementedMedicationServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeMedicationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MedicationServiceServer will
+// result in compilation errors.
+type UnsafeMedicationServiceServer interface {
+	mustEmbedUnimplementedMedicationServiceServer()
+}
+
+func RegisterMedicationServiceServer(s grpc.ServiceRegistrar, srv MedicationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMedicationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MedicationService_ServiceDesc, srv)
+}
+
+func _MedicationService_GetMedicine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MedicationServiceServer).GetMedicine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MedicationService_GetMedicine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MedicationServiceServer).GetMedicine(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MedicationService_ListMedications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListMedicationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MedicationServiceServer).ListMedications(m, &grpc.GenericServerStream[ListMedicationsRequest, Medicine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MedicationService_ListMedicationsServer = grpc.ServerStreamingServer[Medicine]
+
+// MedicationService_ServiceDesc is the grpc.ServiceDesc for MedicationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MedicationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.v1.MedicationService",
+	HandlerType: (*MedicationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMedicine",
+			Handler:    _MedicationService_GetMedicine_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListMedications",
+			Handler:       _MedicationService_ListMedications_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hospital.proto",
+}
+
+const (
+	AppointmentService_GetAppointment_FullMethodName    = "/hospital.v1.AppointmentService/GetAppointment"
+	AppointmentService_ListAppointments_FullMethodName  = "/hospital.v1.AppointmentService/ListAppointments"
+	AppointmentService_CreateAppointment_FullMethodName = "/hospital.v1.AppointmentService/CreateAppointment"
+	AppointmentService_DeleteAppointment_FullMethodName = "/hospital.v1.AppointmentService/DeleteAppointment"
+	AppointmentService_WatchAppointments_FullMethodName = "/hospital.v1.AppointmentService/WatchAppointments"
+)
+
+// AppointmentServiceClient is the client API for AppointmentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AppointmentServiceClient interface {
+	GetAppointment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Appointment, error)
+	ListAppointments(ctx context.Context, in *ListAppointmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Appointment], error)
+	CreateAppointment(ctx context.Context, in *CreateAppointmentRequest, opts ...grpc.CallOption) (*Appointment, error)
+	DeleteAppointment(ctx context.Context, in *DeleteAppointmentRequest, opts ...grpc.CallOption) (*DeleteAppointmentResponse, error)
+	// Потік змін записів: події з тієї ж шини, що й /events
+	WatchAppointments(ctx context.Context, in *WatchAppointmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AppointmentEvent], error)
+}
+
+type appointmentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAppointmentServiceClient(cc grpc.ClientConnInterface) AppointmentServiceClient {
+	return &appointmentServiceClient{cc}
+}
+
+func (c *appointmentServiceClient) GetAppointment(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Appointment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Appointment)
+	err := c.cc.Invoke(ctx, AppointmentService_GetAppointment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appointmentServiceClient) ListAppointments(ctx context.Context, in *ListAppointmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Appointment], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AppointmentService_ServiceDesc.Streams[0], AppointmentService_ListAppointments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListAppointmentsRequest, Appointment]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AppointmentService_ListAppointmentsClient = grpc.ServerStreamingClient[Appointment]
+
+func (c *appointmentServiceClient) CreateAppointment(ctx context.Context, in *CreateAppointmentRequest, opts ...grpc.CallOption) (*Appointment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Appointment)
+	err := c.cc.Invoke(ctx, AppointmentService_CreateAppointment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appointmentServiceClient) DeleteAppointment(ctx context.Context, in *DeleteAppointmentRequest, opts ...grpc.CallOption) (*DeleteAppointmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAppointmentResponse)
+	err := c.cc.Invoke(ctx, AppointmentService_DeleteAppointment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appointmentServiceClient) WatchAppointments(ctx context.Context, in *WatchAppointmentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AppointmentEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AppointmentService_ServiceDesc.Streams[1], AppointmentService_WatchAppointments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAppointmentsRequest, AppointmentEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AppointmentService_WatchAppointmentsClient = grpc.ServerStreamingClient[AppointmentEvent]
+
+// AppointmentServiceServer is the server API for AppointmentService service.
+// All implementations must embed UnimplementedAppointmentServiceServer
+// for forward compatibility.
+type AppointmentServiceServer interface {
+	GetAppointment(context.Context, *GetRequest) (*Appointment, error)
+	ListAppointments(*ListAppointmentsRequest, grpc.ServerStreamingServer[Appointment]) error
+	CreateAppointment(context.Context, *CreateAppointmentRequest) (*Appointment, error)
+	DeleteAppointment(context.Context, *DeleteAppointmentRequest) (*DeleteAppointmentResponse, error)
+	// Потік змін записів: події з тієї ж шини, що й /events
+	WatchAppointments(*WatchAppointmentsRequest, grpc.ServerStreamingServer[AppointmentEvent]) error
+	mustEmbedUnimplementedAppointmentServiceServer()
+}
+
+// UnimplementedAppointmentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAppointmentServiceServer struct{}
+
+func (UnimplementedAppointmentServiceServer) GetAppointment(context.Context, *GetRequest) (*Appointment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAppointment not implemented")
+}
+func (UnimplementedAppointmentServiceServer) ListAppointments(*ListAppointmentsRequest, grpc.ServerStreamingServer[Appointment]) error {
+	return status.Errorf(codes.Unimplemented, "method ListAppointments not implemented")
+}
+func (UnimplementedAppointmentServiceServer) CreateAppointment(context.Context, *CreateAppointmentRequest) (*Appointment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAppointment not implemented")
+}
+func (UnimplementedAppointmentServiceServer) DeleteAppointment(context.Context, *DeleteAppointmentRequest) (*DeleteAppointmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAppointment not implemented")
+}
+func (UnimplementedAppointmentServiceServer) WatchAppointments(*WatchAppointmentsRequest, grpc.ServerStreamingServer[AppointmentEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAppointments not implemented")
+}
+func (UnimplementedAppointmentServiceServer) mustEmbedUnimplementedAppointmentServiceServer() {}
+func (UnimplementedAppointmentServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeAppointmentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AppointmentServiceServer will
+// result in compilation errors.
+type UnsafeAppointmentServiceServer interface {
+	mustEmbedUnimplementedAppointmentServiceServer()
+}
+
+func RegisterAppointmentServiceServer(s grpc.ServiceRegistrar, srv AppointmentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAppointmentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AppointmentService_ServiceDesc, srv)
+}
+
+func _AppointmentService_GetAppointment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppointmentServiceServer).GetAppointment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppointmentService_GetAppointment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppointmentServiceServer).GetAppointment(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppointmentService_ListAppointments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListAppointmentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AppointmentServiceServer).ListAppointments(m, &grpc.GenericServerStream[ListAppointmentsRequest, Appointment]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AppointmentService_ListAppointmentsServer = grpc.ServerStreamingServer[Appointment]
+
+func _AppointmentService_CreateAppointment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAppointmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppointmentServiceServer).CreateAppointment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppointmentService_CreateAppointment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppointmentServiceServer).CreateAppointment(ctx, req.(*CreateAppointmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppointmentService_DeleteAppointment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAppointmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppointmentServiceServer).DeleteAppointment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppointmentService_DeleteAppointment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppointmentServiceServer).DeleteAppointment(ctx, req.(*DeleteAppointmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppointmentService_WatchAppointments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAppointmentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AppointmentServiceServer).WatchAppointments(m, &grpc.GenericServerStream[WatchAppointmentsRequest, AppointmentEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AppointmentService_WatchAppointmentsServer = grpc.ServerStreamingServer[AppointmentEvent]
+
+// AppointmentService_ServiceDesc is the grpc.ServiceDesc for AppointmentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AppointmentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hospital.v1.AppointmentService",
+	HandlerType: (*AppointmentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAppointment",
+			Handler:    _AppointmentService_GetAppointment_Handler,
+		},
+		{
+			MethodName: "CreateAppointment",
+			Handler:    _AppointmentService_CreateAppointment_Handler,
+		},
+		{
+			MethodName: "DeleteAppointment",
+			Handler:    _AppointmentService_DeleteAppointment_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListAppointments",
+			Handler:       _AppointmentService_ListAppointments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchAppointments",
+			Handler:       _AppointmentService_WatchAppointments_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hospital.proto",
+}
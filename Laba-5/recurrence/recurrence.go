@@ -0,0 +1,267 @@
+// Package recurrence розбирає правила повторення RFC 5545 (RRULE) і
+// розгортає їх у конкретні дати. Підтримується підмножина, потрібна для
+// серій записів: FREQ=DAILY або WEEKLY, INTERVAL, COUNT, UNTIL, BYDAY без
+// числових префіксів і WKST. Правило має закінчуватися (COUNT або UNTIL).
+// Пакет не звертається до бази.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily  = "DAILY"
+	Weekly = "WEEKLY"
+)
+
+// MaxOccurrences - скільки дат найбільше дає одне правило
+const MaxOccurrences = 366
+
+var ErrTooManyOccurrences = fmt.Errorf("rule yields more than %d occurrences", MaxOccurrences)
+
+// Формати UNTIL: UTC, "плаваючий" час (вважаємо UTC) і дата (увесь день)
+const (
+	untilUTC      = "20060102T150405Z"
+	untilFloating = "20060102T150405"
+	untilDate     = "20060102"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Rule - розібране RRULE. Until - останній дозволений момент (включно).
+type Rule struct {
+	Freq      string
+	Interval  int
+	Count     int
+	Until     time.Time
+	ByDay     []time.Weekday
+	WeekStart time.Weekday
+}
+
+// Parse розбирає RRULE; префікс "RRULE:" необов'язковий
+func Parse(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || key == "" || value == "" {
+			return Rule{}, fmt.Errorf("malformed part %q", part)
+		}
+		if seen[key] {
+			return Rule{}, fmt.Errorf("%s is given twice", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			if value != Daily && value != Weekly {
+				return Rule{}, fmt.Errorf("FREQ=%s is not supported, use DAILY or WEEKLY", value)
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			rule.Interval, err = positive(key, value)
+		case "COUNT":
+			rule.Count, err = positive(key, value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					return Rule{}, fmt.Errorf("BYDAY: unsupported day %q", day)
+				}
+				if !slices.Contains(rule.ByDay, wd) {
+					rule.ByDay = append(rule.ByDay, wd)
+				}
+			}
+		case "WKST":
+			wd, ok := weekdays[value]
+			if !ok {
+				return Rule{}, fmt.Errorf("WKST: unknown day %q", value)
+			}
+			rule.WeekStart = wd
+		default:
+			return Rule{}, fmt.Errorf("%s is not supported", key)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return Rule{}, errors.New("FREQ is required")
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return Rule{}, errors.New("COUNT and UNTIL cannot be used together")
+	case rule.Count == 0 && rule.Until.IsZero():
+		return Rule{}, errors.New("the rule must end: set COUNT or UNTIL")
+	}
+	return rule, nil
+}
+
+func positive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", key)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse(untilUTC, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(untilFloating, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(untilDate, value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ")
+}
+
+// String повертає правило у вигляді RRULE (без префікса)
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = dayName(wd)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+dayName(r.WeekStart))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilUTC))
+	}
+	return strings.Join(parts, ";")
+}
+
+func dayName(wd time.Weekday) string {
+	for name, d := range weekdays {
+		if d == wd {
+			return name
+		}
+	}
+	return ""
+}
+
+// Expand повертає дати повторів, починаючи зі start. Дні рахуються в
+// часовому поясі loc (nil - UTC), і кожен повтор має той самий місцевий
+// час, що й start, навіть після переходу на літній час. Дати, що не
+// відповідають правилу, пропускаються - зокрема й сам start.
+func (r Rule) Expand(start time.Time, loc *time.Location) ([]time.Time, error) {
+	return r.ExpandFrom(start, time.Time{}, loc)
+}
+
+// ExpandFrom - те саме, що Expand, але повертає лише дати, не раніші за
+// from. Правило все одно відраховується від start (COUNT, INTERVAL), а
+// MaxOccurrences обмежує лише повернені дати - так давно розпочате
+// безкінечне правило можна розгорнути за останній проміжок.
+func (r Rule) ExpandFrom(start, from time.Time, loc *time.Location) ([]time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	start = start.In(loc)
+	interval := max(r.Interval, 1)
+	byDay := r.ByDay
+	if len(byDay) == 0 && r.Freq == Weekly {
+		byDay = []time.Weekday{start.Weekday()}
+	}
+
+	y, m, d := start.Date()
+	first := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	firstWeek := r.weekStart(first)
+
+	var dates []time.Time
+	seen := 0
+	// Правило повторюється з періодом 7*interval днів: якщо за цей час
+	// не знайшлося жодної дати, далі їх теж не буде
+	idle := 0
+	for day := first; idle < 7*interval; day = day.AddDate(0, 0, 1) {
+		idle++
+		if !r.matches(day, first, firstWeek, interval, byDay) {
+			continue
+		}
+		at := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, loc)
+		if at.Before(start) {
+			continue
+		}
+		if !r.Until.IsZero() && at.After(r.Until) {
+			break
+		}
+		idle = 0
+		seen++
+		if !at.Before(from) {
+			if len(dates) == MaxOccurrences {
+				return nil, ErrTooManyOccurrences
+			}
+			dates = append(dates, at)
+		}
+		if r.Count > 0 && seen == r.Count {
+			break
+		}
+	}
+	return dates, nil
+}
+
+func (r Rule) matches(day, first, firstWeek time.Time, interval int, byDay []time.Weekday) bool {
+	if len(byDay) > 0 && !slices.Contains(byDay, day.Weekday()) {
+		return false
+	}
+	if r.Freq == Weekly {
+		return daysBetween(firstWeek, r.weekStart(day))/7%interval == 0
+	}
+	return daysBetween(first, day)%interval == 0
+}
+
+// weekStart - початок тижня (WKST), що містить день day
+func (r Rule) weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) - int(r.WeekStart) + 7) % 7))
+}
+
+// daysBetween - кількість календарних днів; не залежить від переходу на літній час
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// EndBefore - те саме правило, що закінчується перед моментом t:
+// так серію обрізають, коли змінюють або скасовують її решту
+func (r Rule) EndBefore(t time.Time) Rule {
+	r.Count = 0
+	r.Until = t.Add(-time.Second).UTC()
+	return r
+}
+
+// Skip - правило для решти серії після перших n повторів
+func (r Rule) Skip(n int) Rule {
+	if r.Count > 0 {
+		r.Count = max(r.Count-n, 0)
+	}
+	return r
+}
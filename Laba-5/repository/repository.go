@@ -0,0 +1,100 @@
+// Package repository - читання колекцій через кеш. Запис іде напряму в
+// Mongo, а кеш колекції скидається монітором команд (db.OnWrite), тож
+// інвалідація не залежить від того, який обробник писав.
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"hospital-api/cache"
+	"hospital-api/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Meta - звідки відповідь і коли колекція востаннє змінювалась
+type Meta struct {
+	Hit      bool
+	Modified time.Time
+}
+
+// Repository читає документи T з однієї колекції. Без Cache ходить
+// прямо в базу.
+type Repository[T any] struct {
+	Collection string
+	Cache      cache.Cache
+}
+
+func New[T any](collection string, c cache.Cache) *Repository[T] {
+	return &Repository[T]{Collection: collection, Cache: c}
+}
+
+// cached - обгортка, бо bson.Marshal не приймає зріз на верхньому рівні
+type cached[V any] struct {
+	Value V `bson:"v"`
+}
+
+// Find - усі документи за filter
+func (r *Repository[T]) Find(ctx context.Context, filter bson.M) ([]T, Meta, error) {
+	return load(ctx, r, "find", filter, func(ctx context.Context) ([]T, error) {
+		cursor, err := db.Collection(r.Collection).Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		var docs []T
+		err = cursor.All(ctx, &docs)
+		return docs, err
+	})
+}
+
+// FindOne - перший документ за filter; mongo.ErrNoDocuments не кешується
+func (r *Repository[T]) FindOne(ctx context.Context, filter bson.M) (T, Meta, error) {
+	return load(ctx, r, "one", filter, func(ctx context.Context) (T, error) {
+		var doc T
+		err := db.Collection(r.Collection).FindOne(ctx, filter).Decode(&doc)
+		return doc, err
+	})
+}
+
+func load[T, V any](ctx context.Context, r *Repository[T], op string, filter bson.M, query func(context.Context) (V, error)) (V, Meta, error) {
+	if r.Cache == nil {
+		value, err := query(ctx)
+		return value, Meta{Modified: time.Now().UTC()}, err
+	}
+
+	key, err := cacheKey(op, filter)
+	if err != nil {
+		var zero V
+		return zero, Meta{}, err
+	}
+	if entry, ok := r.Cache.Get(ctx, r.Collection, key); ok {
+		var c cached[V]
+		if err := bson.Unmarshal(entry.Value, &c); err == nil {
+			return c.Value, Meta{Hit: true, Modified: entry.Modified}, nil
+		}
+	}
+
+	// Версію беремо до запиту: якщо колекцію змінять, поки ми читаємо,
+	// Set відкине вже застарілий результат
+	version, modified := r.Cache.Version(ctx, r.Collection)
+	value, err := query(ctx)
+	if err != nil {
+		return value, Meta{}, err
+	}
+	if raw, err := bson.Marshal(cached[V]{Value: value}); err == nil {
+		r.Cache.Set(ctx, r.Collection, key, cache.Entry{Value: raw, Version: version, Modified: modified})
+	}
+	return value, Meta{Modified: modified}, nil
+}
+
+// cacheKey - канонічний ключ запиту: encoding/json сортує ключі мап,
+// тож однакові фільтри дають однаковий ключ
+func cacheKey(op string, filter bson.M) (string, error) {
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+	return op + " " + string(b), nil
+}
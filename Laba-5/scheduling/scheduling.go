@@ -0,0 +1,405 @@
+// Package scheduling перевіряє графік змін на відповідність нормам
+// (мінімальний відпочинок, максимум годин на тиждень, покриття ролей)
+// і генерує справедливий тижневий графік. Пакет не звертається до бази:
+// обробники передають сюди вже завантажені зміни, працівників і призначення.
+package scheduling
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"hospital-api/i18n"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RuleMinRest        = "min_rest"
+	RuleMaxWeeklyHours = "max_weekly_hours"
+	RuleCoverage       = "coverage"
+	RuleShiftTime      = "shift_time"
+)
+
+// Rules - норми графіка. Location - часовий пояс, у якому задані
+// години змін і рахуються календарні тижні (nil - UTC).
+type Rules struct {
+	MinRest        time.Duration
+	MaxWeeklyHours float64
+	Location       *time.Location
+}
+
+// DefaultRules - 11 годин відпочинку між змінами і 48 годин на тиждень
+var DefaultRules = Rules{MinRest: 11 * time.Hour, MaxWeeklyHours: 48}
+
+func (r Rules) location() *time.Location {
+	if r.Location == nil {
+		return time.UTC
+	}
+	return r.Location
+}
+
+// Violation - одне порушення норм графіка. Code - ключ повідомлення в
+// каталозі i18n, Message - його текст (англійською, доки не викликано Localize).
+type Violation struct {
+	Rule    string              `json:"rule"`
+	StaffID *primitive.ObjectID `json:"staffId,omitempty"`
+	ShiftID *primitive.ObjectID `json:"shiftId,omitempty"`
+	Date    string              `json:"date,omitempty"`
+	Code    string              `json:"code,omitempty"`
+	Message string              `json:"message"`
+	Args    []interface{}       `json:"-"`
+}
+
+func (v *Violation) describe(code string, args ...interface{}) {
+	v.Code = code
+	v.Args = args
+	v.Message = i18n.T(i18n.Default, code, args...)
+}
+
+// Localize повертає копію порушень з повідомленнями мовою lang
+func Localize(violations []Violation, lang string) []Violation {
+	out := make([]Violation, len(violations))
+	for i, v := range violations {
+		out[i] = v
+		if v.Code != "" {
+			out[i].Message = i18n.T(lang, v.Code, v.Args...)
+		}
+	}
+	return out
+}
+
+// Entry будує призначення працівника на зміну в день date
+func (r Rules) Entry(shift models.Shift, staff models.Staff, date time.Time) (models.RosterEntry, error) {
+	day := date.In(r.location())
+	start, end, err := shift.Window(day)
+	if err != nil {
+		return models.RosterEntry{}, err
+	}
+	return models.RosterEntry{
+		StaffID:      staff.ID,
+		ShiftID:      shift.ID,
+		DepartmentID: shift.DepartmentID,
+		Role:         staff.Role,
+		Date:         day.Format(time.DateOnly),
+		Start:        start,
+		End:          end,
+	}, nil
+}
+
+// Day розбирає дату YYYY-MM-DD у часовому поясі правил
+func (r Rules) Day(date string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, date, r.location())
+}
+
+// WeekStart - понеділок тижня, що містить t, опівночі в часовому поясі правил
+func (r Rules) WeekStart(t time.Time) time.Time {
+	loc := r.location()
+	y, m, d := t.In(loc).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// Check перевіряє всі призначення entries: відпочинок і тижневі години
+// для кожного працівника, а покриття - для змін shifts у днях [from, to).
+func Check(rules Rules, shifts []models.Shift, entries []models.RosterEntry, from, to time.Time) []Violation {
+	var out []Violation
+	for _, list := range byStaff(entries) {
+		for i := 1; i < len(list); i++ {
+			if restConflict(rules, list[i-1], list[i]) {
+				out = append(out, restViolation(rules, list[i-1], list[i]))
+			}
+		}
+		out = append(out, hoursViolations(rules, list, nil)...)
+	}
+	out = append(out, coverageViolations(rules, shifts, entries, from, to)...)
+	sortViolations(out)
+	return out
+}
+
+// CheckAssignment перевіряє, чи можна додати entry до призначень
+// того ж працівника existing, і повертає лише порушення, які воно спричинить
+func CheckAssignment(rules Rules, entry models.RosterEntry, existing []models.RosterEntry) []Violation {
+	var out []Violation
+	week := []models.RosterEntry{entry}
+	for _, e := range existing {
+		if e.StaffID != entry.StaffID || e.ID == entry.ID && !e.ID.IsZero() {
+			continue
+		}
+		if restConflict(rules, e, entry) {
+			out = append(out, restViolation(rules, e, entry))
+		}
+		week = append(week, e)
+	}
+	key := weekKey(rules, entry)
+	out = append(out, hoursViolations(rules, week, &key)...)
+	sortViolations(out)
+	return out
+}
+
+// restConflict - чи між двома змінами менше мінімального відпочинку
+// (зокрема, чи вони перетинаються)
+func restConflict(rules Rules, a, b models.RosterEntry) bool {
+	return a.Start.Before(b.End.Add(rules.MinRest)) && b.Start.Before(a.End.Add(rules.MinRest))
+}
+
+func restViolation(rules Rules, a, b models.RosterEntry) Violation {
+	if b.Start.Before(a.Start) {
+		a, b = b, a
+	}
+	id := b.StaffID
+	v := Violation{Rule: RuleMinRest, StaffID: &id, Date: b.Date}
+	if rest := b.Start.Sub(a.End); rest >= 0 {
+		v.describe("roster.min_rest", rest.String(), a.Date, b.Date, rules.MinRest.String())
+	} else {
+		v.describe("roster.overlap", a.Date, b.Date)
+	}
+	return v
+}
+
+type isoWeek struct{ year, week int }
+
+func weekKey(rules Rules, e models.RosterEntry) isoWeek {
+	y, w := e.Start.In(rules.location()).ISOWeek()
+	return isoWeek{y, w}
+}
+
+// hoursViolations рахує години працівника по тижнях; only обмежує один тиждень
+func hoursViolations(rules Rules, list []models.RosterEntry, only *isoWeek) []Violation {
+	if rules.MaxWeeklyHours <= 0 || len(list) == 0 {
+		return nil
+	}
+	hours := map[isoWeek]float64{}
+	var weeks []isoWeek
+	for _, e := range list {
+		key := weekKey(rules, e)
+		if only != nil && key != *only {
+			continue
+		}
+		if _, ok := hours[key]; !ok {
+			weeks = append(weeks, key)
+		}
+		hours[key] += e.Hours()
+	}
+
+	var out []Violation
+	id := list[0].StaffID
+	for _, key := range weeks {
+		if hours[key] > rules.MaxWeeklyHours {
+			v := Violation{Rule: RuleMaxWeeklyHours, StaffID: &id}
+			v.describe("roster.max_weekly_hours", hours[key], key.year, key.week, rules.MaxWeeklyHours)
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func coverageViolations(rules Rules, shifts []models.Shift, entries []models.RosterEntry, from, to time.Time) []Violation {
+	type slot struct {
+		shift primitive.ObjectID
+		date  string
+		role  string
+	}
+	counts := map[slot]int{}
+	for _, e := range entries {
+		counts[slot{e.ShiftID, e.Date, strings.ToLower(e.Role)}]++
+	}
+
+	var out []Violation
+	for _, day := range days(rules, from, to) {
+		date := day.Format(time.DateOnly)
+		for _, shift := range shifts {
+			for _, role := range sortedRoles(shift.Coverage) {
+				need := shift.Coverage[role]
+				if have := counts[slot{shift.ID, date, strings.ToLower(role)}]; have < need {
+					id := shift.ID
+					v := Violation{Rule: RuleCoverage, ShiftID: &id, Date: date}
+					v.describe("roster.coverage", shift.Name, have, need, role)
+					out = append(out, v)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Generate пропонує графік на тиждень (з понеділка), що містить weekStart. Наявні призначення
+// existing зберігаються й враховуються в нормах, тож генератор лише
+// заповнює прогалини. Кандидатів на місце впорядковано за справедливістю:
+// спершу ті, хто має менше годин цього тижня, далі - хто бажає саме цю
+// зміну (Staff.Shift), далі - хто рідше стояв на цій зміні.
+// Повертає нові призначення та порушення, що лишилися в тижні.
+func Generate(rules Rules, shifts []models.Shift, staff []models.Staff, existing []models.RosterEntry, weekStart time.Time) ([]models.RosterEntry, []Violation) {
+	from := rules.WeekStart(weekStart)
+	to := from.AddDate(0, 0, 7)
+
+	ordered := append([]models.Shift(nil), shifts...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Start != ordered[j].Start {
+			return ordered[i].Start < ordered[j].Start
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+
+	people := append([]models.Staff(nil), staff...)
+	sort.SliceStable(people, func(i, j int) bool {
+		if people[i].Name != people[j].Name {
+			return people[i].Name < people[j].Name
+		}
+		return people[i].ID.Hex() < people[j].ID.Hex()
+	})
+
+	assigned := map[primitive.ObjectID][]models.RosterEntry{}
+	for _, e := range existing {
+		assigned[e.StaffID] = append(assigned[e.StaffID], e)
+	}
+	week := isoWeekOf(from)
+	weekHours := func(id primitive.ObjectID) float64 {
+		var h float64
+		for _, e := range assigned[id] {
+			if weekKey(rules, e) == week {
+				h += e.Hours()
+			}
+		}
+		return h
+	}
+	timesOn := func(id, shiftID primitive.ObjectID) int {
+		n := 0
+		for _, e := range assigned[id] {
+			if e.ShiftID == shiftID {
+				n++
+			}
+		}
+		return n
+	}
+
+	var created []models.RosterEntry
+	// Зміну з невалідним часом пропускаємо й повідомляємо про неї один раз,
+	// решта графіка генерується як звичайно
+	var broken []Violation
+	brokenShift := map[primitive.ObjectID]bool{}
+	for _, day := range days(rules, from, to) {
+		for _, shift := range ordered {
+			for _, role := range sortedRoles(shift.Coverage) {
+				need := shift.Coverage[role]
+				var candidates []models.RosterEntry
+				for _, person := range people {
+					if person.DepartmentID != shift.DepartmentID || !strings.EqualFold(person.Role, role) {
+						continue
+					}
+					entry, err := rules.Entry(shift, person, day)
+					if err != nil {
+						if !brokenShift[shift.ID] {
+							brokenShift[shift.ID] = true
+							id := shift.ID
+							v := Violation{Rule: RuleShiftTime, ShiftID: &id}
+							v.describe("roster.shift_invalid", shift.Name)
+							broken = append(broken, v)
+						}
+						continue
+					}
+					if onShift(assigned[person.ID], entry) {
+						need--
+						continue
+					}
+					if len(CheckAssignment(rules, entry, assigned[person.ID])) == 0 {
+						candidates = append(candidates, entry)
+					}
+				}
+
+				preferred := func(e models.RosterEntry) bool {
+					for _, p := range people {
+						if p.ID == e.StaffID {
+							return p.Shift != "" && strings.EqualFold(p.Shift, shift.Name)
+						}
+					}
+					return false
+				}
+				sort.SliceStable(candidates, func(i, j int) bool {
+					a, b := candidates[i].StaffID, candidates[j].StaffID
+					if ha, hb := weekHours(a), weekHours(b); ha != hb {
+						return ha < hb
+					}
+					if pa, pb := preferred(candidates[i]), preferred(candidates[j]); pa != pb {
+						return pa
+					}
+					return timesOn(a, shift.ID) < timesOn(b, shift.ID)
+				})
+
+				for _, entry := range candidates {
+					if need <= 0 {
+						break
+					}
+					// Попередні кандидати могли змінити годину/відпочинок - перевіряємо ще раз
+					if len(CheckAssignment(rules, entry, assigned[entry.StaffID])) > 0 {
+						continue
+					}
+					assigned[entry.StaffID] = append(assigned[entry.StaffID], entry)
+					created = append(created, entry)
+					need--
+				}
+			}
+		}
+	}
+
+	all := append(append([]models.RosterEntry(nil), existing...), created...)
+	return created, append(broken, Check(rules, shifts, all, from, to)...)
+}
+
+// onShift - чи працівник уже стоїть на цій зміні цього дня
+func onShift(list []models.RosterEntry, entry models.RosterEntry) bool {
+	for _, e := range list {
+		if e.ShiftID == entry.ShiftID && e.Date == entry.Date {
+			return true
+		}
+	}
+	return false
+}
+
+func isoWeekOf(t time.Time) isoWeek {
+	y, w := t.ISOWeek()
+	return isoWeek{y, w}
+}
+
+// days повертає опівночі кожного дня в [from, to) у часовому поясі правил
+func days(rules Rules, from, to time.Time) []time.Time {
+	loc := rules.location()
+	y, m, d := from.In(loc).Date()
+	var out []time.Time
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		out = append(out, day)
+	}
+	return out
+}
+
+func byStaff(entries []models.RosterEntry) map[primitive.ObjectID][]models.RosterEntry {
+	out := map[primitive.ObjectID][]models.RosterEntry{}
+	for _, e := range entries {
+		out[e.StaffID] = append(out[e.StaffID], e)
+	}
+	for _, list := range out {
+		sort.Slice(list, func(i, j int) bool { return list[i].Start.Before(list[j].Start) })
+	}
+	return out
+}
+
+func sortedRoles(coverage map[string]int) []string {
+	roles := make([]string, 0, len(coverage))
+	for role, n := range coverage {
+		if n > 0 {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func sortViolations(v []Violation) {
+	sort.SliceStable(v, func(i, j int) bool {
+		if v[i].Date != v[j].Date {
+			return v[i].Date < v[j].Date
+		}
+		return v[i].Rule < v[j].Rule
+	})
+}
@@ -0,0 +1,212 @@
+// Package search - вбудований повнотекстовий індекс: пошук по кількох
+// ресурсах одразу з ранжуванням, без урахування діакритики й регістру,
+// з транслітерацією і прощенням описок.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Field - текст документа і його вага в ранжуванні (ім'я важить більше за посаду)
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document - те, що індексується: один лікар, працівник, препарат тощо
+type Document struct {
+	Resource string
+	ID       string
+	Title    string
+	Fields   []Field
+}
+
+// Hit - знайдений документ; більший Score - релевантніший
+type Hit struct {
+	Resource string  `json:"resource"`
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Score    float64 `json:"score"`
+}
+
+// Options обмежує пошук ресурсами і кількістю результатів
+type Options struct {
+	Resources []string
+	Limit     int
+}
+
+// Якість збігу слова запиту зі словом документа
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.75
+	typoMatch   = 0.6 // за кожну наступну помилку - мінус typoPenalty
+	typoPenalty = 0.15
+)
+
+type docKey struct {
+	resource, id string
+}
+
+// Index - індекс у пам'яті. Load замінює вміст цілком, Search можна
+// викликати паралельно з Load.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[docKey]Document
+	postings map[string]map[docKey]float64 // слово → документ → найбільша вага поля
+	loaded   bool
+}
+
+func NewIndex() *Index {
+	return &Index{docs: map[docKey]Document{}, postings: map[string]map[docKey]float64{}}
+}
+
+// Load будує індекс з docs і атомарно підміняє ним попередній
+func (ix *Index) Load(docs []Document) {
+	byKey := make(map[docKey]Document, len(docs))
+	postings := map[string]map[docKey]float64{}
+	add := func(term string, key docKey, weight float64) {
+		if postings[term] == nil {
+			postings[term] = map[docKey]float64{}
+		}
+		postings[term][key] = math.Max(postings[term][key], weight)
+	}
+
+	for _, doc := range docs {
+		key := docKey{doc.Resource, doc.ID}
+		byKey[key] = doc
+		for _, f := range doc.Fields {
+			for _, token := range Tokens(f.Text) {
+				add(token, key, f.Weight)
+				if latin := Translit(token); latin != "" {
+					add(latin, key, f.Weight)
+				}
+			}
+		}
+	}
+
+	ix.mu.Lock()
+	ix.docs, ix.postings, ix.loaded = byKey, postings, true
+	ix.mu.Unlock()
+}
+
+// Loaded - чи індекс уже хоч раз завантажено
+func (ix *Index) Loaded() bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.loaded
+}
+
+// Len - кількість документів в індексі
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Search шукає документи, що містять усі слова запиту (точно, як префікс
+// або з описками). Кожне слово запиту пробується і в латиниці.
+func (ix *Index) Search(query string, opts Options) []Hit {
+	tokens := Tokens(query)
+	if len(tokens) == 0 {
+		return nil
+	}
+	allowed := map[string]bool{}
+	for _, r := range opts.Resources {
+		allowed[r] = true
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var total map[docKey]float64
+	for _, token := range tokens {
+		forms := []string{token}
+		if latin := Translit(token); latin != "" {
+			forms = append(forms, latin)
+		}
+
+		scores := map[docKey]float64{}
+		for term, docs := range ix.postings {
+			quality := 0.0
+			for _, form := range forms {
+				quality = math.Max(quality, match(form, term))
+			}
+			if quality == 0 {
+				continue
+			}
+			for key, weight := range docs {
+				if len(allowed) > 0 && !allowed[key.resource] {
+					continue
+				}
+				scores[key] = math.Max(scores[key], quality*weight)
+			}
+		}
+
+		// Документ має збігтися з кожним словом запиту
+		if total == nil {
+			total = scores
+			continue
+		}
+		for key := range total {
+			if s, ok := scores[key]; ok {
+				total[key] += s
+			} else {
+				delete(total, key)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(total))
+	for key, score := range total {
+		doc := ix.docs[key]
+		hits = append(hits, Hit{
+			Resource: doc.Resource,
+			ID:       doc.ID,
+			Title:    doc.Title,
+			Score:    math.Round(score*1000) / 1000,
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Title != hits[j].Title {
+			return hits[i].Title < hits[j].Title
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits
+}
+
+// match - наскільки слово запиту q відповідає слову документа term; 0 - ніяк
+func match(q, term string) float64 {
+	if q == term {
+		return exactMatch
+	}
+	qLen := utf8.RuneCountInString(q)
+	if qLen >= 2 && strings.HasPrefix(term, q) {
+		return prefixMatch
+	}
+
+	typos := maxTypos(qLen)
+	if typos == 0 {
+		return 0
+	}
+	if d := Distance(q, term, typos); d <= typos {
+		return typoMatch - typoPenalty*float64(d-1)
+	}
+	// Описка в ще не дописаному слові: порівнюємо з початком term
+	if runes := []rune(term); len(runes) > qLen {
+		if d := Distance(q, string(runes[:qLen]), 1); d <= 1 {
+			return typoMatch - typoPenalty
+		}
+	}
+	return 0
+}
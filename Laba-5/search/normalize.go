@@ -0,0 +1,68 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold зводить слово до форми для порівняння: нижній регістр, без
+// діакритики (й→и, ї→і, ё→е, é→e) і з кількома українськими літерами,
+// які часто плутають або не мають на розкладці: ґ→г, є→е.
+func Fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// комбінований знак - наголос, бреве, діерезис
+		case r == 'ґ':
+			b.WriteRune('г')
+		case r == 'є':
+			b.WriteRune('е')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// apostrophes - варіанти апострофа в іменах (Мар'яна, Мар’яна, Марʼяна);
+// вони не розривають слово, а просто зникають
+var apostrophes = strings.NewReplacer("'", "", "’", "", "ʼ", "", "`", "")
+
+// Tokens розбиває текст на складені слова
+func Tokens(text string) []string {
+	return strings.FieldsFunc(Fold(apostrophes.Replace(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// translit - латинські відповідники складених кириличних літер
+// (за українською транслітерацією, спрощено; російські ы, э, ъ теж)
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'д': "d", 'е': "e", 'ж': "zh",
+	'з': "z", 'и': "y", 'і': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+	'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ь': "",
+	'ю': "iu", 'я': "ia", 'ы': "y", 'э': "e", 'ъ': "",
+}
+
+// Translit - латинська форма складеного слова, щоб "Shevchenko" знаходило
+// "Шевченко" і навпаки. Для слова без кирилиці повертає "".
+func Translit(token string) string {
+	var b strings.Builder
+	cyrillic := false
+	for _, r := range token {
+		if latin, ok := translit[r]; ok {
+			b.WriteString(latin)
+			cyrillic = true
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	if !cyrillic {
+		return ""
+	}
+	return b.String()
+}
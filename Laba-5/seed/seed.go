@@ -0,0 +1,306 @@
+// Package seed завантажує фікстури (JSON або YAML) у hospital_db.
+// Записи можуть мати символьне ім'я "ref", а інші записи посилаються
+// на них рядком "@ім'я" - під час завантаження посилання замінюються на ObjectID.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gopkg.in/yaml.v3"
+)
+
+// Порядок важливий: спочатку ті, на кого посилаються
+var collections = []string{"hospitals", "departments", "doctors", "staff", "medications", "appointments"}
+
+// Fixtures - сирі записи фікстур по колекціях, ще з символьними посиланнями
+type Fixtures struct {
+	Hospitals    []map[string]interface{} `json:"hospitals"`
+	Departments  []map[string]interface{} `json:"departments"`
+	Doctors      []map[string]interface{} `json:"doctors"`
+	Staff        []map[string]interface{} `json:"staff"`
+	Medications  []map[string]interface{} `json:"medications"`
+	Appointments []map[string]interface{} `json:"appointments"`
+}
+
+// Dataset - фікстури з розв'язаними посиланнями, готові до вставки
+type Dataset struct {
+	Hospitals    []models.Hospital
+	Departments  []models.Department
+	Doctors      []models.Doctor
+	Staff        []models.Staff
+	Medications  []models.Medicine
+	Appointments []models.Appointment
+
+	// Refs - ObjectID кожного запису з "ref", зручно для тестів
+	Refs map[string]primitive.ObjectID
+}
+
+// ReadFile читає фікстури з файлу; формат визначається за розширенням
+func ReadFile(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := "json"
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	return Parse(data, format)
+}
+
+// Parse розбирає фікстури у форматі "json" або "yaml"
+func Parse(data []byte, format string) (*Fixtures, error) {
+	var fixtures Fixtures
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return &fixtures, nil
+	}
+
+	switch format {
+	case "json":
+		if err := decodeStrict(data, &fixtures); err != nil {
+			return nil, fmt.Errorf("parse json fixtures: %w", err)
+		}
+	case "yaml":
+		// YAML переганяємо через JSON, щоб діяли ті самі json-теги моделей
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse yaml fixtures: %w", err)
+		}
+		converted, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parse yaml fixtures: %w", err)
+		}
+		if err := decodeStrict(converted, &fixtures); err != nil {
+			return nil, fmt.Errorf("parse yaml fixtures: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported fixtures format %q", format)
+	}
+	return &fixtures, nil
+}
+
+func (f *Fixtures) sections() map[string][]map[string]interface{} {
+	return map[string][]map[string]interface{}{
+		"hospitals":    f.Hospitals,
+		"departments":  f.Departments,
+		"doctors":      f.Doctors,
+		"staff":        f.Staff,
+		"medications":  f.Medications,
+		"appointments": f.Appointments,
+	}
+}
+
+// Resolve видає кожному запису з "ref" новий ObjectID і підставляє
+// ці ID замість посилань "@ref" у всіх полях
+func (f *Fixtures) Resolve() (*Dataset, error) {
+	sections := f.sections()
+	refs := map[string]primitive.ObjectID{}
+
+	// Перший прохід: збираємо імена
+	for _, name := range collections {
+		for i, rec := range sections[name] {
+			ref, ok := rec["ref"]
+			if !ok {
+				continue
+			}
+			refName, ok := ref.(string)
+			if !ok || refName == "" {
+				return nil, fmt.Errorf("%s[%d]: ref must be a non-empty string", name, i)
+			}
+			if _, dup := refs[refName]; dup {
+				return nil, fmt.Errorf("%s[%d]: duplicate ref %q", name, i, refName)
+			}
+			refs[refName] = primitive.NewObjectID()
+		}
+	}
+
+	// Другий прохід: підставляємо ID і декодуємо в моделі
+	ds := &Dataset{Refs: refs}
+	for _, name := range collections {
+		for i, rec := range sections[name] {
+			resolved, err := resolveRecord(rec, refs)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+			}
+			if ref, ok := resolved["ref"].(string); ok {
+				resolved["id"] = refs[ref].Hex()
+				delete(resolved, "ref")
+			}
+			data, err := json.Marshal(resolved)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+			}
+			if err := ds.decode(name, data); err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+			}
+		}
+	}
+	return ds, nil
+}
+
+func resolveRecord(rec map[string]interface{}, refs map[string]primitive.ObjectID) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(rec))
+	for key, value := range rec {
+		resolved, err := resolveValue(value, refs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		out[key] = resolved
+	}
+	return out, nil
+}
+
+func resolveValue(value interface{}, refs map[string]primitive.ObjectID) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.HasPrefix(v, "@") {
+			return v, nil
+		}
+		id, ok := refs[strings.TrimPrefix(v, "@")]
+		if !ok {
+			return nil, fmt.Errorf("unknown reference %q", v)
+		}
+		return id.Hex(), nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := resolveValue(item, refs)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	case map[string]interface{}:
+		return resolveRecord(v, refs)
+	default:
+		return v, nil
+	}
+}
+
+// decode розбирає запис у модель колекції і перевіряє його. Невідомі
+// поля (зокрема bson-імена на кшталт hospital_id) - помилка, а не тиша.
+func (ds *Dataset) decode(collection string, data []byte) error {
+	var err error
+	switch collection {
+	case "hospitals":
+		ds.Hospitals, err = appendRecord(ds.Hospitals, data)
+	case "departments":
+		ds.Departments, err = appendRecord(ds.Departments, data)
+	case "doctors":
+		ds.Doctors, err = appendRecord(ds.Doctors, data)
+	case "staff":
+		ds.Staff, err = appendRecord(ds.Staff, data)
+	case "medications":
+		ds.Medications, err = appendRecord(ds.Medications, data)
+	case "appointments":
+		ds.Appointments, err = appendRecord(ds.Appointments, data)
+	}
+	return err
+}
+
+func appendRecord[T interface{ Validate() error }](records []T, data []byte) ([]T, error) {
+	var m T
+	if err := decodeStrict(data, &m); err != nil {
+		return records, err
+	}
+	if err := m.Validate(); err != nil {
+		return records, err
+	}
+	return append(records, m), nil
+}
+
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func (ds *Dataset) documents(collection string) []interface{} {
+	var docs []interface{}
+	switch collection {
+	case "hospitals":
+		for _, m := range ds.Hospitals {
+			docs = append(docs, m)
+		}
+	case "departments":
+		for _, m := range ds.Departments {
+			docs = append(docs, m)
+		}
+	case "doctors":
+		for _, m := range ds.Doctors {
+			docs = append(docs, m)
+		}
+	case "staff":
+		for _, m := range ds.Staff {
+			docs = append(docs, m)
+		}
+	case "medications":
+		for _, m := range ds.Medications {
+			docs = append(docs, m)
+		}
+	case "appointments":
+		for _, m := range ds.Appointments {
+			docs = append(docs, m)
+		}
+	}
+	return docs
+}
+
+// Wipe видаляє всі документи з колекцій фікстур. Колекції (а з ними
+// індекси та валідатори з міграцій) залишаються на місці.
+func Wipe(ctx context.Context, database *mongo.Database) error {
+	for _, name := range collections {
+		if _, err := database.Collection(name).DeleteMany(ctx, bson.M{}); err != nil {
+			return fmt.Errorf("wipe %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Load вставляє набір даних у базу; з wipe спочатку очищує колекції
+func Load(ctx context.Context, database *mongo.Database, ds *Dataset, wipe bool) error {
+	if wipe {
+		if err := Wipe(ctx, database); err != nil {
+			return err
+		}
+	}
+	for _, name := range collections {
+		docs := ds.documents(name)
+		if len(docs) == 0 {
+			continue
+		}
+		if _, err := database.Collection(name).InsertMany(ctx, docs); err != nil {
+			return fmt.Errorf("seed %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// LoadFile - ReadFile + Resolve + Load одним викликом, зокрема для тестів
+func LoadFile(ctx context.Context, database *mongo.Database, path string, wipe bool) (*Dataset, error) {
+	fixtures, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := fixtures.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	if err := Load(ctx, database, ds, wipe); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
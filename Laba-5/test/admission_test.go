@@ -0,0 +1,569 @@
+package math
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"hospital-api/admission"
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ward - палата в пам'яті з тими ж умовами, що й фільтри оновлень у Mongo.
+// fail імітує одноразовий збій методу (undo після нього вже вдається).
+type ward struct {
+	mu         sync.Mutex
+	info       models.Ward
+	beds       map[primitive.ObjectID]*models.Bed
+	admissions map[primitive.ObjectID]*models.Admission
+	fail       string
+}
+
+var wardDepartment = primitive.NewObjectID()
+
+func newWard(capacity int, beds ...primitive.ObjectID) *ward {
+	wd := &ward{
+		info:       models.Ward{ID: primitive.NewObjectID(), DepartmentID: wardDepartment, Capacity: capacity, Beds: len(beds)},
+		beds:       map[primitive.ObjectID]*models.Bed{},
+		admissions: map[primitive.ObjectID]*models.Admission{},
+	}
+	for _, id := range beds {
+		wd.beds[id] = &models.Bed{ID: id, WardID: wd.info.ID, DepartmentID: wardDepartment}
+	}
+	return wd
+}
+
+func (wd *ward) failing(method string) error {
+	if wd.fail == method {
+		wd.fail = ""
+		return errors.New(method + " failed")
+	}
+	return nil
+}
+
+func (wd *ward) ClaimBed(_ context.Context, req admission.Request, a models.Admission, exclude primitive.ObjectID) (models.Bed, error) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("ClaimBed"); err != nil {
+		return models.Bed{}, err
+	}
+	for id, bed := range wd.beds {
+		if bed.PatientID != nil || id == exclude || (!req.BedID.IsZero() && id != req.BedID) {
+			continue
+		}
+		bed.PatientID, bed.AdmissionID = &a.PatientID, &a.ID
+		return *bed, nil
+	}
+	return models.Bed{}, admission.ErrNoFreeBed
+}
+
+func (wd *ward) ReleaseBed(_ context.Context, bedID, admissionID primitive.ObjectID) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("ReleaseBed"); err != nil {
+		return err
+	}
+	if bed := wd.beds[bedID]; bed != nil && bed.AdmissionID != nil && *bed.AdmissionID == admissionID {
+		bed.PatientID, bed.AdmissionID = nil, nil
+	}
+	return nil
+}
+
+func (wd *ward) InsertAdmission(_ context.Context, a models.Admission) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("InsertAdmission"); err != nil {
+		return err
+	}
+	// Унікальний індекс на активні госпіталізації пацієнта
+	for _, other := range wd.admissions {
+		if other.PatientID == a.PatientID && other.Status == models.AdmissionActive {
+			return admission.ErrAlreadyAdmitted
+		}
+	}
+	wd.admissions[a.ID] = &a
+	return nil
+}
+
+func (wd *ward) DeleteAdmission(_ context.Context, id primitive.ObjectID) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	delete(wd.admissions, id)
+	return nil
+}
+
+func (wd *ward) MoveAdmission(_ context.Context, id, from primitive.ObjectID, bed models.Bed, transfer models.BedTransfer) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("MoveAdmission"); err != nil {
+		return err
+	}
+	a := wd.admissions[id]
+	if a == nil || a.Status != models.AdmissionActive || a.BedID != from {
+		return admission.ErrConflict
+	}
+	a.BedID = bed.ID
+	a.Transfers = append(a.Transfers, transfer)
+	return nil
+}
+
+func (wd *ward) UnmoveAdmission(_ context.Context, previous models.Admission) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	a := wd.admissions[previous.ID]
+	a.BedID = previous.BedID
+	a.Transfers = a.Transfers[:len(a.Transfers)-1]
+	return nil
+}
+
+func (wd *ward) SetStatus(_ context.Context, id primitive.ObjectID, from, to string, dischargedAt *time.Time) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	a := wd.admissions[id]
+	if a == nil || a.Status != from {
+		return admission.ErrNotActive
+	}
+	a.Status, a.DischargedAt = to, dischargedAt
+	return nil
+}
+
+func (wd *ward) ReserveBed(_ context.Context, wardID primitive.ObjectID) (models.Ward, error) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if wardID != wd.info.ID || wd.info.Beds >= wd.info.Capacity {
+		return models.Ward{}, admission.ErrWardFull
+	}
+	wd.info.Beds++
+	return wd.info, nil
+}
+
+func (wd *ward) UnreserveBed(_ context.Context, wardID primitive.ObjectID) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("UnreserveBed"); err != nil {
+		return err
+	}
+	wd.info.Beds--
+	return nil
+}
+
+func (wd *ward) InsertBed(_ context.Context, bed models.Bed) (primitive.ObjectID, error) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("InsertBed"); err != nil {
+		return primitive.NilObjectID, err
+	}
+	bed.ID = primitive.NewObjectID()
+	wd.beds[bed.ID] = &bed
+	return bed.ID, nil
+}
+
+func (wd *ward) TrashBed(_ context.Context, bedID primitive.ObjectID, mark models.SoftDelete) (models.Bed, error) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	bed := wd.beds[bedID]
+	if bed == nil || bed.DeletedAt != nil || bed.PatientID != nil {
+		return models.Bed{}, admission.ErrBedNotFree
+	}
+	bed.SoftDelete = mark
+	return *bed, nil
+}
+
+func (wd *ward) UntrashBed(_ context.Context, bedID primitive.ObjectID) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if err := wd.failing("UntrashBed"); err != nil {
+		return err
+	}
+	bed := wd.beds[bedID]
+	if bed == nil || bed.DeletedAt == nil {
+		return admission.ErrBedNotInTrash
+	}
+	bed.SoftDelete = models.SoftDelete{}
+	return nil
+}
+
+// trashed - чи ліжко в кошику
+func (wd *ward) trashed(bedID primitive.ObjectID) bool {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	return wd.beds[bedID].DeletedAt != nil
+}
+
+// occupied - хто лежить на ліжку (nil - вільне)
+func (wd *ward) occupied(bedID primitive.ObjectID) *primitive.ObjectID {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	return wd.beds[bedID].AdmissionID
+}
+
+func inWork(fn func(u *db.UnitOfWork) error) error {
+	return db.RunInTransaction(context.Background(), fn)
+}
+
+var admittedAt = time.Date(2025, time.March, 3, 10, 0, 0, 0, time.UTC)
+
+func admit(wd *ward, req admission.Request) (models.Admission, error) {
+	var a models.Admission
+	err := inWork(func(u *db.UnitOfWork) (err error) {
+		a, err = admission.Admit(u, wd, req, admittedAt)
+		return err
+	})
+	return a, err
+}
+
+// ------------------ Прийом ------------------
+func TestAdmit(t *testing.T) {
+	bed1, bed2 := primitive.NewObjectID(), primitive.NewObjectID()
+	patient := primitive.NewObjectID()
+
+	tests := []struct {
+		name     string
+		setup    func(wd *ward)
+		req      admission.Request
+		err      error
+		freeBeds int
+	}{
+		{"specific bed", nil, admission.Request{PatientID: patient, BedID: bed1}, nil, 1},
+		{"any bed of department", nil, admission.Request{PatientID: patient, DepartmentID: wardDepartment}, nil, 1},
+		{"bed taken", func(wd *ward) {
+			admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed1})
+		}, admission.Request{PatientID: patient, BedID: bed1}, admission.ErrNoFreeBed, 1},
+		{"department full", func(wd *ward) {
+			admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed1})
+			admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed2})
+		}, admission.Request{PatientID: patient, DepartmentID: wardDepartment}, admission.ErrNoFreeBed, 0},
+		// Ліжко, зайняте до помилки вставки, має звільнитися
+		{"already admitted", func(wd *ward) {
+			admit(wd, admission.Request{PatientID: patient, BedID: bed1})
+		}, admission.Request{PatientID: patient, BedID: bed2}, admission.ErrAlreadyAdmitted, 1},
+		{"insert fails", func(wd *ward) { wd.fail = "InsertAdmission" },
+			admission.Request{PatientID: patient, BedID: bed1}, errors.New("InsertAdmission failed"), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd := newWard(2, bed1, bed2)
+			if tt.setup != nil {
+				tt.setup(wd)
+			}
+			a, err := admit(wd, tt.req)
+			switch {
+			case tt.err == nil && err != nil:
+				t.Fatalf("admit: %v", err)
+			case tt.err != nil && (err == nil || err.Error() != tt.err.Error()):
+				t.Fatalf("admit = %v; want %v", err, tt.err)
+			case tt.err == nil && a.Status != models.AdmissionActive:
+				t.Errorf("status = %q", a.Status)
+			}
+			free := 0
+			for id := range wd.beds {
+				if wd.occupied(id) == nil {
+					free++
+				}
+			}
+			if free != tt.freeBeds {
+				t.Errorf("%d free beds; want %d", free, tt.freeBeds)
+			}
+		})
+	}
+}
+
+// ------------------ Одне ліжко - одна госпіталізація ------------------
+func TestAdmitNoDoubleBooking(t *testing.T) {
+	bed := primitive.NewObjectID()
+	wd := newWard(1, bed)
+
+	var wg sync.WaitGroup
+	results := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed})
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	admitted := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			admitted++
+		case !errors.Is(err, admission.ErrNoFreeBed):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if admitted != 1 || len(wd.admissions) != 1 {
+		t.Errorf("%d admitted, %d admissions; want exactly one", admitted, len(wd.admissions))
+	}
+}
+
+// ------------------ Переведення ------------------
+func TestTransfer(t *testing.T) {
+	bed1, bed2 := primitive.NewObjectID(), primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		req    func(a models.Admission) admission.Request
+		fail   string
+		err    error
+		onBed  func() primitive.ObjectID
+		oldBed bool // чи лишилось старе ліжко за госпіталізацією
+	}{
+		{"to specific bed", func(models.Admission) admission.Request { return admission.Request{BedID: bed2} }, "", nil, func() primitive.ObjectID { return bed2 }, false},
+		{"to any bed of department", func(models.Admission) admission.Request {
+			return admission.Request{DepartmentID: wardDepartment}
+		}, "", nil, func() primitive.ObjectID { return bed2 }, false},
+		{"same bed", func(a models.Admission) admission.Request { return admission.Request{BedID: a.BedID} }, "", admission.ErrSameBed, func() primitive.ObjectID { return bed1 }, true},
+		// Збій звільнення старого ліжка: переведення скасовується повністю
+		{"release fails", func(models.Admission) admission.Request { return admission.Request{BedID: bed2} }, "ReleaseBed", errors.New("ReleaseBed failed"), func() primitive.ObjectID { return bed1 }, true},
+		{"move fails", func(models.Admission) admission.Request { return admission.Request{BedID: bed2} }, "MoveAdmission", errors.New("MoveAdmission failed"), func() primitive.ObjectID { return bed1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd := newWard(2, bed1, bed2)
+			a, err := admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			wd.fail = tt.fail
+
+			err = inWork(func(u *db.UnitOfWork) error {
+				_, err := admission.Transfer(u, wd, a, tt.req(a), admittedAt.Add(time.Hour))
+				return err
+			})
+			wd.fail = ""
+			if (tt.err == nil) != (err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Fatalf("transfer = %v; want %v", err, tt.err)
+			}
+
+			stored := wd.admissions[a.ID]
+			if want := tt.onBed(); stored.BedID != want {
+				t.Errorf("admission on %v; want %v", stored.BedID, want)
+			}
+			if held := wd.occupied(bed1) != nil; held != tt.oldBed {
+				t.Errorf("old bed held = %v; want %v", held, tt.oldBed)
+			}
+			if tt.err == nil && len(stored.Transfers) != 1 {
+				t.Errorf("transfers = %v; want one", stored.Transfers)
+			}
+			if tt.err != nil && (len(stored.Transfers) != 0 || wd.occupied(bed2) != nil) {
+				t.Errorf("failed transfer left transfers %v, new bed held %v", stored.Transfers, wd.occupied(bed2) != nil)
+			}
+		})
+	}
+}
+
+// ------------------ Виписка ------------------
+func TestDischarge(t *testing.T) {
+	bed := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		fail    string
+		twice   bool
+		err     error
+		status  string
+		bedHeld bool
+	}{
+		{"discharged", "", false, nil, models.AdmissionDischarged, false},
+		// Ліжко не звільнилось - виписка скасовується, а не лишає ліжко зайнятим назавжди
+		{"release fails", "ReleaseBed", false, errors.New("ReleaseBed failed"), models.AdmissionActive, true},
+		{"already discharged", "", true, admission.ErrNotActive, models.AdmissionDischarged, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd := newWard(1, bed)
+			a, err := admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			discharge := func() error {
+				return inWork(func(u *db.UnitOfWork) error {
+					_, err := admission.Discharge(u, wd, a, admittedAt.Add(24*time.Hour))
+					return err
+				})
+			}
+			if tt.twice {
+				discharge()
+			}
+			wd.fail = tt.fail
+			err = discharge()
+			if (tt.err == nil) != (err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Fatalf("discharge = %v; want %v", err, tt.err)
+			}
+			stored := wd.admissions[a.ID]
+			if stored.Status != tt.status {
+				t.Errorf("status = %q; want %q", stored.Status, tt.status)
+			}
+			if stored.Status == models.AdmissionActive && stored.DischargedAt != nil {
+				t.Error("active admission has dischargedAt")
+			}
+			if held := wd.occupied(bed) != nil; held != tt.bedHeld {
+				t.Errorf("bed held = %v; want %v", held, tt.bedHeld)
+			}
+		})
+	}
+}
+
+// ------------------ Місткість палати ------------------
+func TestAddBedWardCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		existing int
+		fail     string
+		err      error
+		beds     int
+	}{
+		{"room left", 2, 1, "", nil, 2},
+		{"full", 2, 2, "", admission.ErrWardFull, 2},
+		// Вставка впала - зарезервоване місце повертається
+		{"insert fails", 2, 1, "InsertBed", errors.New("InsertBed failed"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var existing []primitive.ObjectID
+			for i := 0; i < tt.existing; i++ {
+				existing = append(existing, primitive.NewObjectID())
+			}
+			wd := newWard(tt.capacity, existing...)
+			wd.fail = tt.fail
+
+			var bed models.Bed
+			err := inWork(func(u *db.UnitOfWork) (err error) {
+				bed, err = admission.AddBed(u, wd, models.Bed{Label: "A", WardID: wd.info.ID})
+				return err
+			})
+			if (tt.err == nil) != (err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Fatalf("add bed = %v; want %v", err, tt.err)
+			}
+			if wd.info.Beds != tt.beds {
+				t.Errorf("ward beds = %d; want %d", wd.info.Beds, tt.beds)
+			}
+			if tt.err == nil && bed.DepartmentID != wardDepartment {
+				t.Errorf("bed department = %v; want the ward's", bed.DepartmentID)
+			}
+		})
+	}
+
+	// Паралельні вставки не перевищують місткість
+	wd := newWard(3)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inWork(func(u *db.UnitOfWork) error {
+				_, err := admission.AddBed(u, wd, models.Bed{Label: "B", WardID: wd.info.ID})
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	if wd.info.Beds != 3 || len(wd.beds) != 3 {
+		t.Errorf("ward beds = %d, created %d; want 3", wd.info.Beds, len(wd.beds))
+	}
+}
+
+// ------------------ Видалення ліжка звільняє місце в палаті ------------------
+func TestRemoveBed(t *testing.T) {
+	tests := []struct {
+		name     string
+		occupied bool
+		fail     string
+		err      error
+		trashed  bool
+		beds     int
+	}{
+		{"free bed", false, "", nil, true, 1},
+		{"occupied bed", true, "", admission.ErrBedNotFree, false, 2},
+		// Лічильник палати не оновився - ліжко повертається з кошика
+		{"counter fails", false, "UnreserveBed", errors.New("UnreserveBed failed"), false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bed := primitive.NewObjectID()
+			wd := newWard(2, bed, primitive.NewObjectID())
+			if tt.occupied {
+				if _, err := admit(wd, admission.Request{PatientID: primitive.NewObjectID(), BedID: bed}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			wd.fail = tt.fail
+
+			err := inWork(func(u *db.UnitOfWork) error {
+				_, err := admission.RemoveBed(u, wd, bed, "admin", admittedAt)
+				return err
+			})
+			if (tt.err == nil) != (err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Fatalf("remove bed = %v; want %v", err, tt.err)
+			}
+			if got := wd.trashed(bed); got != tt.trashed {
+				t.Errorf("trashed = %v; want %v", got, tt.trashed)
+			}
+			if wd.info.Beds != tt.beds {
+				t.Errorf("ward beds = %d; want %d", wd.info.Beds, tt.beds)
+			}
+		})
+	}
+}
+
+// ------------------ Відновлення ліжка займає місце в палаті ------------------
+func TestRestoreBed(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		fail     string
+		err      error
+		trashed  bool
+		beds     int
+	}{
+		{"room left", 2, "", nil, false, 2},
+		// Друге ліжко палати на одне місце займає його й після видалення
+		{"ward full", 1, "", admission.ErrWardFull, true, 1},
+		// Ліжко не повернулось - зайняте місце звільняється
+		{"restore fails", 2, "UntrashBed", errors.New("UntrashBed failed"), true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bed := primitive.NewObjectID()
+			wd := newWard(tt.capacity, bed, primitive.NewObjectID())
+			removed, err := inWorkBed(func(u *db.UnitOfWork) (models.Bed, error) {
+				return admission.RemoveBed(u, wd, bed, "admin", admittedAt)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			wd.fail = tt.fail
+
+			err = inWork(func(u *db.UnitOfWork) error {
+				return admission.RestoreBed(u, wd, removed)
+			})
+			if (tt.err == nil) != (err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Fatalf("restore bed = %v; want %v", err, tt.err)
+			}
+			if got := wd.trashed(bed); got != tt.trashed {
+				t.Errorf("trashed = %v; want %v", got, tt.trashed)
+			}
+			if wd.info.Beds != tt.beds {
+				t.Errorf("ward beds = %d; want %d", wd.info.Beds, tt.beds)
+			}
+		})
+	}
+}
+
+func inWorkBed(fn func(u *db.UnitOfWork) (models.Bed, error)) (bed models.Bed, err error) {
+	err = inWork(func(u *db.UnitOfWork) error {
+		bed, err = fn(u)
+		return err
+	})
+	return bed, err
+}
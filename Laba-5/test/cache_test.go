@@ -0,0 +1,165 @@
+package math
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-api/cache"
+	"hospital-api/events"
+	"hospital-api/handlers"
+)
+
+func entry(c cache.Cache, collection, value string) cache.Entry {
+	version, modified := c.Version(context.Background(), collection)
+	return cache.Entry{Value: []byte(value), Version: version, Modified: modified}
+}
+
+func TestMemoryCacheHitsAndMisses(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemory(cache.Options{TTL: time.Minute})
+
+	if _, ok := c.Get(ctx, "hospitals", "all"); ok {
+		t.Fatal("empty cache returned an entry")
+	}
+	c.Set(ctx, "hospitals", "all", entry(c, "hospitals", "[1]"))
+	got, ok := c.Get(ctx, "hospitals", "all")
+	if !ok || string(got.Value) != "[1]" {
+		t.Fatalf("Get = %q, %v", got.Value, ok)
+	}
+
+	st := c.Stats()["hospitals"]
+	if st.Hits != 1 || st.Misses != 1 || st.Entries != 1 || st.Bytes != 3 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := cache.NewMemory(cache.Options{TTL: 30 * time.Second})
+	c.SetClock(func() time.Time { return now })
+
+	c.Set(ctx, "doctors", "k", entry(c, "doctors", "v"))
+	now = now.Add(29 * time.Second)
+	if _, ok := c.Get(ctx, "doctors", "k"); !ok {
+		t.Error("entry expired before TTL")
+	}
+	now = now.Add(time.Second)
+	if _, ok := c.Get(ctx, "doctors", "k"); ok {
+		t.Error("entry outlived TTL")
+	}
+	if st := c.Stats()["doctors"]; st.Entries != 0 {
+		t.Errorf("expired entry still counted: %+v", st)
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemory(cache.Options{MaxEntries: 2})
+
+	c.Set(ctx, "doctors", "a", entry(c, "doctors", "1"))
+	c.Set(ctx, "doctors", "b", entry(c, "doctors", "2"))
+	c.Get(ctx, "doctors", "a") // a тепер свіжіший за b
+	c.Set(ctx, "doctors", "c", entry(c, "doctors", "3"))
+
+	if _, ok := c.Get(ctx, "doctors", "b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(ctx, "doctors", key); !ok {
+			t.Errorf("entry %q was evicted", key)
+		}
+	}
+	if st := c.Stats()["doctors"]; st.Evictions != 1 {
+		t.Errorf("evictions = %d, want 1", st.Evictions)
+	}
+}
+
+func TestMemoryCacheMaxBytes(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemory(cache.Options{MaxBytes: 5})
+
+	c.Set(ctx, "doctors", "big", entry(c, "doctors", "123456"))
+	if _, ok := c.Get(ctx, "doctors", "big"); ok {
+		t.Error("entry larger than MaxBytes was cached")
+	}
+	c.Set(ctx, "doctors", "a", entry(c, "doctors", "123"))
+	c.Set(ctx, "doctors", "b", entry(c, "doctors", "456"))
+	if st := c.Stats()["doctors"]; st.Bytes > 5 || st.Entries != 1 {
+		t.Errorf("stats = %+v, want one entry within 5 bytes", st)
+	}
+}
+
+func TestMemoryCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemory(cache.Options{})
+
+	c.Set(ctx, "hospitals", "k", entry(c, "hospitals", "v"))
+	c.Set(ctx, "doctors", "k", entry(c, "doctors", "v"))
+	// Читання почалося до запису, а закінчилося після
+	stale := entry(c, "hospitals", "old")
+	c.Invalidate(ctx, "hospitals")
+	c.Set(ctx, "hospitals", "late", stale)
+
+	if _, ok := c.Get(ctx, "hospitals", "k"); ok {
+		t.Error("entry survived invalidation")
+	}
+	if _, ok := c.Get(ctx, "hospitals", "late"); ok {
+		t.Error("result read before the write was cached after invalidation")
+	}
+	if _, ok := c.Get(ctx, "doctors", "k"); !ok {
+		t.Error("invalidation cleared another collection")
+	}
+	if st := c.Stats()["hospitals"]; st.Invalidations != 1 {
+		t.Errorf("invalidations = %d, want 1", st.Invalidations)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	router := handlers.NewAPI()
+	handlers.Cache.Get(context.Background(), "hospitals", "metrics-test")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer "+loginToken(t, router, "admin", "admin123"))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `hospital_cache_misses_total{collection="hospitals"}`) {
+		t.Errorf("metrics missing hospitals misses:\n%s", rec.Body.String())
+	}
+}
+
+// Зміни з бази (change stream чи опитування) скидають кеш так само, як
+// записи цього процесу, - інакше 304 віддавався б на застарілі дані
+func TestCacheInvalidatedByDatabaseChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := events.NewBus()
+	go handlers.InvalidateOnChanges(ctx, bus)
+
+	before, _ := handlers.Cache.Version(ctx, "doctors")
+	deadline := time.Now().Add(time.Second)
+	for {
+		// Підписка могла ще не встигнути з'явитися - публікуємо, доки не дійде
+		bus.Publish(events.Event{Type: events.Updated, Resource: "doctors", ResourceID: "x"})
+		if after, _ := handlers.Cache.Version(ctx, "doctors"); after != before {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("doctors cache was not invalidated by a change event")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
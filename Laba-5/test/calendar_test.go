@@ -0,0 +1,351 @@
+package math
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-api/handlers"
+	"hospital-api/ical"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ------------------ Запис .ics ------------------
+func TestEncodeCalendar(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	// Записи по обидва боки переходу на літній час (30 березня 2025)
+	calendar := ical.Calendar{Name: "Dr. House", Location: kyiv, Events: []ical.Event{
+		{UID: "a@hospital-api", Start: time.Date(2025, time.March, 27, 9, 30, 0, 0, kyiv), End: time.Date(2025, time.March, 27, 10, 0, 0, 0, kyiv),
+			Summary: "Прийом пацієнта; кабінет 12, другий поверх — " + strings.Repeat("довгий опис ", 6), Status: ical.StatusConfirmed},
+		{UID: "b@hospital-api", Start: time.Date(2025, time.April, 3, 9, 30, 0, 0, kyiv), End: time.Date(2025, time.April, 3, 10, 0, 0, 0, kyiv),
+			Status: ical.StatusCancelled},
+	}}
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Error("lines must end with CRLF")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d bytes: %q", len(line), line)
+		}
+	}
+	for _, want := range []string{
+		"DTSTART;TZID=Europe/Kyiv:20250327T093000",
+		"DTSTART;TZID=Europe/Kyiv:20250403T093000",
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Kyiv",
+		// Перехід о 03:00 за зимовим часом, з +0200 на +0300
+		"BEGIN:DAYLIGHT\r\nDTSTART:20250330T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0300",
+		"STATUS:CANCELLED",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	if unfolded := strings.ReplaceAll(out, "\r\n ", ""); !strings.Contains(unfolded, `SUMMARY:Прийом пацієнта\; кабінет 12\, другий поверх`) {
+		t.Errorf("SUMMARY not escaped:\n%s", unfolded)
+	}
+}
+
+// ------------------ Читання .ics ------------------
+func TestReadCalendar(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	file := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:vacation",
+		"DTSTART;VALUE=DATE:20250407",
+		"DTEND;VALUE=DATE:20250412",
+		"SUMMARY:Відпустка\\, море",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:conference",
+		`DTSTART;TZID="Europe/Kyiv":20250415T090000`,
+		"DURATION:PT3H30M",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken",
+		"DTSTART:2025-04-16",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:weekly",
+		"DTSTART:20250416T060000Z",
+		"DTEND:20250416T07",
+		" 0000Z",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	var events []ical.Event
+	var failed []int
+	err = ical.Read(strings.NewReader(file), kyiv, func(line int, e ical.Event, err error) error {
+		if err != nil {
+			failed = append(failed, line)
+			return nil
+		}
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0] != 17 {
+		t.Errorf("failed at lines %v; want [17]", failed)
+	}
+	if len(events) != 3 {
+		t.Fatalf("%d events; want 3", len(events))
+	}
+
+	vacation, conference, weekly := events[0], events[1], events[2]
+	if !vacation.AllDay || !vacation.Start.Equal(time.Date(2025, time.April, 7, 0, 0, 0, 0, kyiv)) || vacation.Summary != "Відпустка, море" {
+		t.Errorf("vacation = %+v", vacation)
+	}
+	if !conference.Start.Equal(time.Date(2025, time.April, 15, 6, 0, 0, 0, time.UTC)) || conference.End.Sub(conference.Start) != 210*time.Minute {
+		t.Errorf("conference %v..%v", conference.Start, conference.End)
+	}
+	if !weekly.End.Equal(time.Date(2025, time.April, 16, 7, 0, 0, 0, time.UTC)) || weekly.RRule != "FREQ=WEEKLY;COUNT=4" || !weekly.Transparent {
+		t.Errorf("weekly = %+v", weekly)
+	}
+
+	if err := ical.Read(strings.NewReader("name,date\n"), kyiv, nil); err == nil {
+		t.Error("CSV accepted as iCalendar")
+	}
+}
+
+// ------------------ EXDATE і RECURRENCE-ID ------------------
+func TestReadRecurrenceExceptions(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	file := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:rounds",
+		"DTSTART;TZID=Europe/Kyiv:20250407T090000",
+		"DTEND;TZID=Europe/Kyiv:20250407T100000",
+		"RRULE:FREQ=WEEKLY;COUNT=6",
+		"EXDATE;TZID=Europe/Kyiv:20250414T090000,20250421T090000",
+		"EXDATE:20250505T060000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:rounds",
+		"RECURRENCE-ID;TZID=Europe/Kyiv:20250428T090000",
+		"DTSTART;TZID=Europe/Kyiv:20250428T140000",
+		"DTEND;TZID=Europe/Kyiv:20250428T150000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	var events []ical.Event
+	err = ical.Read(strings.NewReader(file), time.UTC, func(_ int, e ical.Event, err error) error {
+		events = append(events, e)
+		return err
+	})
+	if err != nil || len(events) != 2 {
+		t.Fatalf("read %d events: %v", len(events), err)
+	}
+	want := []time.Time{
+		time.Date(2025, time.April, 14, 9, 0, 0, 0, kyiv),
+		time.Date(2025, time.April, 21, 9, 0, 0, 0, kyiv),
+		time.Date(2025, time.May, 5, 6, 0, 0, 0, time.UTC),
+	}
+	if got := events[0].ExDates; len(got) != len(want) || !got[0].Equal(want[0]) || !got[1].Equal(want[1]) || !got[2].Equal(want[2]) {
+		t.Errorf("ExDates = %v; want %v", got, want)
+	}
+	if !events[0].RecurrenceID.IsZero() {
+		t.Errorf("master RecurrenceID = %v", events[0].RecurrenceID)
+	}
+	if id := events[1].RecurrenceID; !id.Equal(time.Date(2025, time.April, 28, 9, 0, 0, 0, kyiv)) {
+		t.Errorf("RecurrenceID = %v", id)
+	}
+}
+
+// ------------------ Записане читається назад ------------------
+func TestCalendarRoundTrip(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	want := ical.Event{
+		UID: "x@hospital-api", Start: time.Date(2025, time.October, 26, 2, 30, 0, 0, kyiv), End: time.Date(2025, time.October, 26, 5, 0, 0, 0, kyiv),
+		Summary: "Нічне чергування, корпус Б", Description: "рядок 1\nрядок 2", Status: ical.StatusTentative,
+	}
+	var buf bytes.Buffer
+	if err := (ical.Calendar{Location: kyiv, Events: []ical.Event{want}}).Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got []ical.Event
+	err = ical.Read(&buf, time.UTC, func(_ int, e ical.Event, err error) error {
+		got = append(got, e)
+		return err
+	})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("read back %d events: %v", len(got), err)
+	}
+	e := got[0]
+	if e.UID != want.UID || !e.Start.Equal(want.Start) || !e.End.Equal(want.End) ||
+		e.Summary != want.Summary || e.Description != want.Description || e.Status != want.Status {
+		t.Errorf("got %+v; want %+v", e, want)
+	}
+}
+
+// ------------------ Токен відкриває лише свою стрічку ------------------
+func TestCalendarTokenPath(t *testing.T) {
+	id, _ := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	tests := []struct {
+		token models.CalendarToken
+		want  string
+	}{
+		{models.CalendarToken{Feed: "doctors", FeedID: id}, "/doctors/507f1f77bcf86cd799439011/calendar.ics"},
+		{models.CalendarToken{Feed: "patients", FeedID: id}, "/patients/507f1f77bcf86cd799439011/calendar.ics"},
+		// Старий токен без стрічки не збігається з жодною адресою
+		{models.CalendarToken{}, "//000000000000000000000000/calendar.ics"},
+	}
+	for _, tt := range tests {
+		if got := tt.token.Path(); got != tt.want {
+			t.Errorf("Path() = %q; want %q", got, tt.want)
+		}
+	}
+}
+
+// ------------------ Стрічки й імпорт перевіряють запит до БД ------------------
+func TestCalendarRoutes(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+	admin := loginToken(t, router, "admin", "admin123")
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		token       string
+		contentType string
+		code        int
+	}{
+		{"feed without token", http.MethodGet, "/doctors/507f1f77bcf86cd799439011/calendar.ics", "", "", http.StatusUnauthorized},
+		{"feed bad id", http.MethodGet, "/patients/nope/calendar.ics", reader, "", http.StatusBadRequest},
+		{"token without login", http.MethodPost, "/calendar/token", "", "", http.StatusUnauthorized},
+		{"token without feed", http.MethodPost, "/calendar/token", reader, "", http.StatusBadRequest},
+		{"token for two feeds", http.MethodPost, "/calendar/token?doctorId=507f1f77bcf86cd799439011&patientId=507f1f77bcf86cd799439012", reader, "", http.StatusBadRequest},
+		{"token bad feed id", http.MethodPost, "/calendar/token?patientId=nope", reader, "", http.StatusBadRequest},
+		{"revoke bad feed id", http.MethodDelete, "/calendar/token?doctorId=nope", reader, "", http.StatusBadRequest},
+		{"reader imports", http.MethodPost, "/doctors/507f1f77bcf86cd799439011/unavailability/import", reader, "text/calendar", http.StatusForbidden},
+		{"import csv", http.MethodPost, "/doctors/507f1f77bcf86cd799439011/unavailability/import", admin, "text/csv", http.StatusUnsupportedMediaType},
+		{"reader deletes block", http.MethodDelete, "/doctors/507f1f77bcf86cd799439011/unavailability/507f1f77bcf86cd799439012", reader, "", http.StatusForbidden},
+		{"bad block id", http.MethodDelete, "/doctors/507f1f77bcf86cd799439011/unavailability/nope", admin, "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("%s %s = %d %q; want %d", tt.method, tt.path, rec.Code, rec.Body.String(), tt.code)
+			}
+		})
+	}
+
+	// Dry run лише розбирає файл і до БД не звертається
+	file := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:v\r\nDTSTART;VALUE=DATE:20250407\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:c\r\nDTSTART:20250408T090000Z\r\nSTATUS:CANCELLED\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/doctors/507f1f77bcf86cd799439011/unavailability/import?dryRun=true", strings.NewReader(file))
+	req.Header.Set("Content-Type", "text/calendar")
+	req.Header.Set("Authorization", "Bearer "+admin)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, `"blocked":1`) || !strings.Contains(body, `"skipped":1`) {
+		t.Errorf("dry run = %d %s", rec.Code, body)
+	}
+
+	// З 5 повторів: один викреслено EXDATE, один скасовано, один перенесено -
+	// перенесений замінює свій повтор, а не додається окремим блоком
+	file = strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT", "UID:w", "DTSTART:20250407T090000Z", "DTEND:20250407T100000Z",
+		"RRULE:FREQ=WEEKLY;COUNT=5", "EXDATE:20250414T090000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:w", "RECURRENCE-ID:20250421T090000Z",
+		"DTSTART:20250421T130000Z", "DTEND:20250421T140000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:w", "RECURRENCE-ID:20250428T090000Z",
+		"DTSTART:20250428T090000Z", "STATUS:CANCELLED", "END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	req = httptest.NewRequest(http.MethodPost, "/doctors/507f1f77bcf86cd799439011/unavailability/import?dryRun=true", strings.NewReader(file))
+	req.Header.Set("Content-Type", "text/calendar")
+	req.Header.Set("Authorization", "Bearer "+admin)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if body := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(body, `"total":3`) ||
+		!strings.Contains(body, `"blocked":3`) || !strings.Contains(body, `"skipped":1`) || !strings.Contains(body, `"failed":0`) {
+		t.Errorf("dry run with exceptions = %d %s", rec.Code, body)
+	}
+}
+
+// Давня безкінечна подія блокує лише рік уперед, а не всі роки від DTSTART
+func TestImportOldOpenEndedRRule(t *testing.T) {
+	router := handlers.NewAPI()
+	admin := loginToken(t, router, "admin", "admin123")
+
+	tests := []struct {
+		name     string
+		rrule    string
+		min, max int
+	}{
+		{"weekly since 2019", "FREQ=WEEKLY;BYDAY=MO", 52, 54},
+		{"daily since 2019", "FREQ=DAILY", 365, 366},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT", "UID:old", "DTSTART:20190107T090000Z", "DTEND:20190107T100000Z",
+				"RRULE:" + tt.rrule, "END:VEVENT",
+				"END:VCALENDAR",
+			}, "\r\n")
+			req := httptest.NewRequest(http.MethodPost, "/doctors/507f1f77bcf86cd799439011/unavailability/import?dryRun=true", strings.NewReader(file))
+			req.Header.Set("Content-Type", "text/calendar")
+			req.Header.Set("Authorization", "Bearer "+admin)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			var result struct {
+				Blocked int `json:"blocked"`
+				Failed  int `json:"failed"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil || rec.Code != http.StatusOK {
+				t.Fatalf("import = %d %s", rec.Code, rec.Body.String())
+			}
+			if result.Failed != 0 || result.Blocked < tt.min || result.Blocked > tt.max {
+				t.Errorf("blocked %d, failed %d; want %d..%d blocks: %s", result.Blocked, result.Failed, tt.min, tt.max, rec.Body.String())
+			}
+		})
+	}
+}
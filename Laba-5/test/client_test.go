@@ -0,0 +1,318 @@
+package math
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hospital-api/client"
+	"hospital-api/handlers"
+	"hospital-api/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ------------------ Фільтри збігаються з query-параметрами API ------------------
+func TestClientFiltersMatchOpenAPI(t *testing.T) {
+	spec := fetchSpec(t, handlers.NewAPI())
+
+	filters := map[string]interface{}{
+		"/hospitals":          client.HospitalFilter{},
+		"/departments":        client.DepartmentFilter{},
+		"/doctors":            client.DoctorFilter{},
+		"/staff":              client.StaffFilter{},
+		"/medications":        client.MedicineFilter{},
+		"/appointments":       client.AppointmentFilter{},
+		"/appointment-series": client.SeriesFilter{},
+		"/wards":              client.WardFilter{},
+		"/beds":               client.BedFilter{},
+		"/admissions":         client.AdmissionFilter{},
+		"/shifts":             client.ShiftFilter{},
+		"/roster":             client.RosterFilter{},
+	}
+	for path, filter := range filters {
+		var tags []string
+		typ := reflect.TypeOf(filter)
+		for i := 0; i < typ.NumField(); i++ {
+			tags = append(tags, typ.Field(i).Tag.Get("query"))
+		}
+		var documented []string
+		for _, p := range spec.Paths[path]["get"].Parameters {
+			// expand змінює форму відповіді, а клієнт повертає типи models
+			if p.In == "query" && p.Name != "expand" {
+				documented = append(documented, p.Name)
+			}
+		}
+		sort.Strings(tags)
+		sort.Strings(documented)
+		if !reflect.DeepEqual(tags, documented) {
+			t.Errorf("%s: %s has %v, API documents %v", path, typ.Name(), tags, documented)
+		}
+	}
+}
+
+func TestClientFilterValues(t *testing.T) {
+	department := primitive.NewObjectID()
+	got := client.Values(client.DoctorFilter{Specialty: "кардіо", Department: department, MinExperience: client.Int(0)}).Encode()
+	want := "department=" + department.Hex() + "&minExperience=0&specialty=%D0%BA%D0%B0%D1%80%D0%B4%D1%96%D0%BE"
+	if got != want {
+		t.Errorf("DoctorFilter = %q, want %q", got, want)
+	}
+	if got := client.Values(client.BedFilter{Free: client.Bool(false)}).Encode(); got != "free=false" {
+		t.Errorf("BedFilter{Free: false} = %q", got)
+	}
+	if got := client.Values(client.HospitalFilter{}).Encode(); got != "" {
+		t.Errorf("empty filter = %q", got)
+	}
+}
+
+// ------------------ Вхід і перевхід ------------------
+
+// authServer видає токени з коротким exp і приймає лише останній виданий
+type authServer struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	logins int
+	token  string
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Path == "/login" {
+		s.logins++
+		claims := jwt.RegisteredClaims{ID: strconv.Itoa(s.logins), ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.ttl))}
+		s.token, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test"))
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"token":"`+s.token+`"}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		http.Error(w, "Missing or invalid token", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `[]`)
+}
+
+func TestClientLogsInAndRefreshes(t *testing.T) {
+	ctx := context.Background()
+
+	// Токен живе менше за запас перевходу - кожен запит входить заново
+	short := &authServer{ttl: 30 * time.Second}
+	server := httptest.NewServer(short)
+	defer server.Close()
+	api := client.New(server.URL, client.Options{Username: "admin", Password: "admin123"})
+	for i := 0; i < 2; i++ {
+		if _, err := api.ListShifts(ctx, client.ShiftFilter{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if short.logins != 2 {
+		t.Errorf("logins with expiring token = %d, want 2", short.logins)
+	}
+
+	// Довгий токен використовується повторно
+	long := &authServer{ttl: time.Hour}
+	server2 := httptest.NewServer(long)
+	defer server2.Close()
+	api = client.New(server2.URL, client.Options{Username: "admin", Password: "admin123"})
+	for i := 0; i < 3; i++ {
+		if _, err := api.ListShifts(ctx, client.ShiftFilter{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if long.logins != 1 {
+		t.Errorf("logins with valid token = %d, want 1", long.logins)
+	}
+
+	// Сервер відкликав токен - клієнт один раз входить заново
+	long.mu.Lock()
+	long.token = "revoked"
+	long.mu.Unlock()
+	if _, err := api.ListShifts(ctx, client.ShiftFilter{}); err != nil {
+		t.Fatalf("after revocation: %v", err)
+	}
+	if long.logins != 2 || api.Token() != long.token {
+		t.Errorf("logins after 401 = %d, want 2", long.logins)
+	}
+
+	// Без облікових даних 401 повертається як є
+	api = client.New(server2.URL, client.Options{Token: "stale"})
+	_, err := api.ListShifts(ctx, client.ShiftFilter{})
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("stale static token: %v", err)
+	}
+}
+
+func TestClientAgainstRouter(t *testing.T) {
+	server := httptest.NewServer(handlers.NewAPI())
+	defer server.Close()
+	ctx := context.Background()
+
+	_, err := client.New(server.URL, client.Options{Username: "reader", Password: "wrong"}).ListStaff(ctx, client.StaffFilter{})
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("wrong password: %v", err)
+	}
+
+	// reader входить, але вебхуки лише для admin
+	api := client.New(server.URL, client.Options{Username: "reader", Password: "reader123", Language: "uk"})
+	_, err = api.ListWebhooks(ctx)
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrForbidden) || !errors.As(err, &apiErr) {
+		t.Fatalf("reader listing webhooks: %v", err)
+	}
+	if apiErr.Method != http.MethodGet || apiErr.Path != "/webhooks" || apiErr.Message == "" {
+		t.Errorf("error = %+v", apiErr)
+	}
+	if api.Token() == "" || client.TokenExpiry(api.Token()).IsZero() {
+		t.Error("token with exp should be kept after login")
+	}
+}
+
+// ------------------ Повтори ------------------
+
+// flakyServer відповідає failures разів кодом status, потім - успіхом
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	requests []*http.Request
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	if len(s.requests) <= s.failures {
+		w.Header().Set("Retry-After", "0")
+		http.Error(w, "try later", s.status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		io.WriteString(w, `[{"name":"Парацетамол","stock":5}]`)
+	case http.MethodPost:
+		io.WriteString(w, `{"name":"Парацетамол","stock":5}`)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	ctx := context.Background()
+	fast := client.Options{Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+	flaky := &flakyServer{failures: 2, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+	medications, err := client.New(server.URL, fast).ListMedications(ctx, client.MedicineFilter{})
+	if err != nil || len(medications) != 1 || medications[0].Name != "Парацетамол" {
+		t.Fatalf("GET after two 503: %v %v", medications, err)
+	}
+	if len(flaky.requests) != 3 {
+		t.Errorf("GET attempts = %d, want 3", len(flaky.requests))
+	}
+
+	// Створення повторюється з тим самим Idempotency-Key
+	flaky2 := &flakyServer{failures: 1, status: http.StatusBadGateway}
+	server2 := httptest.NewServer(flaky2)
+	defer server2.Close()
+	if _, err := client.New(server2.URL, fast).CreateMedicine(ctx, models.Medicine{Name: "Парацетамол"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(flaky2.requests) != 2 {
+		t.Fatalf("POST attempts = %d, want 2", len(flaky2.requests))
+	}
+	first, second := flaky2.requests[0].Header.Get("Idempotency-Key"), flaky2.requests[1].Header.Get("Idempotency-Key")
+	if first == "" || first != second {
+		t.Errorf("Idempotency-Key %q then %q", first, second)
+	}
+
+	// Спроби вичерпано - остання помилка типізована
+	flaky3 := &flakyServer{failures: 10, status: http.StatusServiceUnavailable}
+	server3 := httptest.NewServer(flaky3)
+	defer server3.Close()
+	_, err = client.New(server3.URL, fast).ListMedications(ctx, client.MedicineFilter{})
+	if !errors.Is(err, client.ErrUnavailable) || len(flaky3.requests) != 1+client.DefaultMaxRetries {
+		t.Errorf("exhausted retries: %v after %d attempts", err, len(flaky3.requests))
+	}
+
+	// POST без Idempotent і постійні помилки не повторюються
+	flaky4 := &flakyServer{failures: 10, status: http.StatusServiceUnavailable}
+	server4 := httptest.NewServer(flaky4)
+	defer server4.Close()
+	api := client.New(server4.URL, fast)
+	api.Do(ctx, client.Request{Method: http.MethodPost, Path: "/medications/x/dispense", Body: []byte(`{}`)})
+	if len(flaky4.requests) != 1 {
+		t.Errorf("plain POST attempts = %d, want 1", len(flaky4.requests))
+	}
+
+	flaky5 := &flakyServer{failures: 10, status: http.StatusNotFound}
+	server5 := httptest.NewServer(flaky5)
+	defer server5.Close()
+	_, err = client.New(server5.URL, fast).GetMedicine(ctx, primitive.NewObjectID())
+	if !errors.Is(err, client.ErrNotFound) || len(flaky5.requests) != 1 {
+		t.Errorf("404: %v after %d attempts", err, len(flaky5.requests))
+	}
+
+	// Скасований контекст перериває очікування між спробами
+	flaky6 := &flakyServer{failures: 10, status: http.StatusServiceUnavailable}
+	server6 := httptest.NewServer(flaky6)
+	defer server6.Close()
+	cancelled, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = client.New(server6.URL, client.Options{Backoff: time.Hour, MaxBackoff: time.Hour}).ListMedications(cancelled, client.MedicineFilter{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("cancelled context: %v", err)
+	}
+}
+
+// ------------------ Типізовані помилки ------------------
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, client.ErrBadRequest},
+		{http.StatusUnauthorized, client.ErrUnauthorized},
+		{http.StatusForbidden, client.ErrForbidden},
+		{http.StatusNotFound, client.ErrNotFound},
+		{http.StatusConflict, client.ErrConflict},
+		{http.StatusUnprocessableEntity, client.ErrValidation},
+		{http.StatusTooManyRequests, client.ErrRateLimited},
+		{http.StatusServiceUnavailable, client.ErrUnavailable},
+		{http.StatusInternalServerError, client.ErrServer},
+	}
+	for _, tt := range tests {
+		err := error(&client.Error{StatusCode: tt.status, Method: http.MethodGet, Path: "/x"})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%d: errors.Is(%v) = false", tt.status, tt.want)
+		}
+	}
+
+	// 422 від графіка несе порушення правил
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		io.WriteString(w, `{"error":"Assignment breaks roster rules","violations":[{"rule":"rest","message":"less than 11h of rest"}]}`)
+	}))
+	defer server.Close()
+	_, err := client.New(server.URL, client.Options{}).AssignShift(context.Background(), client.RosterAssignment{Date: "2025-03-03"}, false)
+	violations, ok := client.Violations(err)
+	if !errors.Is(err, client.ErrValidation) || !ok || len(violations) != 1 || violations[0].Rule != "rest" {
+		t.Errorf("roster rejection: %v %v", err, violations)
+	}
+	if !strings.Contains(err.Error(), "POST /roster: 422") {
+		t.Errorf("error text = %q", err.Error())
+	}
+}
@@ -0,0 +1,213 @@
+package math
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-api/events"
+	"hospital-api/handlers"
+	"hospital-api/models"
+
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// loginToken отримує JWT через /login
+func loginToken(t *testing.T, router http.Handler, username, password string) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"username":"` + username + `","password":"` + password + `"}`)
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/login", body))
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp["token"] == "" {
+		t.Fatalf("login failed: %d %s", rec.Code, rec.Body.String())
+	}
+	return resp["token"]
+}
+
+// ------------------ Фільтр подій ------------------
+func TestEventFilter(t *testing.T) {
+	event := events.Event{Type: events.Created, Resource: "appointments", ResourceID: "abc"}
+
+	tests := []struct {
+		name      string
+		resources string
+		ids       string
+		want      bool
+	}{
+		{"no filter", "", "", true},
+		{"resource match", "medications, appointments", "", true},
+		{"resource mismatch", "medications", "", false},
+		{"id match", "appointments", "abc", true},
+		{"id mismatch", "", "def", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := events.ParseFilter(tt.resources, tt.ids).Match(event); got != tt.want {
+				t.Errorf("Match = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ------------------ Повільного підписника відключають, а не чекають ------------------
+func TestBusDropsSlowSubscriber(t *testing.T) {
+	bus := events.NewBus()
+	slow := bus.Subscribe(events.Filter{}, 1)
+	fast := bus.Subscribe(events.Filter{}, 10)
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(events.Event{Type: events.Updated, Resource: "medications"})
+	}
+
+	if e := <-slow.C; e.ID != "1" {
+		t.Errorf("first event id = %s; want 1", e.ID)
+	}
+	if _, ok := <-slow.C; ok {
+		t.Error("slow subscriber should be closed")
+	}
+	if len(fast.C) != 3 {
+		t.Errorf("fast subscriber got %d events; want 3", len(fast.C))
+	}
+	fast.Close()
+	fast.Close()
+}
+
+// ------------------ Опитування: різниця знімків ------------------
+func TestSnapshotDiff(t *testing.T) {
+	resource := events.Model[models.Medicine]("medications")
+	kept, changed, removed := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	trashed := primitive.NewObjectID()
+	doc := func(id primitive.ObjectID, stock int) bson.Raw {
+		raw, _ := bson.Marshal(models.Medicine{ID: id, Name: "Аспірин", Stock: stock})
+		return raw
+	}
+	deleted := func(id primitive.ObjectID) bson.Raw {
+		now := time.Now()
+		raw, _ := bson.Marshal(models.Medicine{ID: id, Name: "Аспірин", SoftDelete: models.SoftDelete{DeletedAt: &now}})
+		return raw
+	}
+
+	_, snapshot := events.Snapshot(nil).Diff(resource, []bson.Raw{doc(kept, 1), doc(changed, 5), doc(removed, 2), doc(trashed, 3)})
+	added := primitive.NewObjectID()
+	changes, _ := snapshot.Diff(resource, []bson.Raw{doc(kept, 1), doc(changed, 4), doc(added, 9), deleted(trashed)})
+
+	got := map[string]string{}
+	for _, e := range changes {
+		got[e.ResourceID] = e.Type
+	}
+	want := map[string]string{
+		changed.Hex(): events.Updated, added.Hex(): events.Created,
+		removed.Hex(): events.Deleted, trashed.Hex(): events.Deleted,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v; want %v", got, want)
+	}
+	for id, typ := range want {
+		if got[id] != typ {
+			t.Errorf("%s: %s; want %s", id, got[id], typ)
+		}
+	}
+	for _, e := range changes {
+		if e.ResourceID == changed.Hex() && e.Data.(models.Medicine).Stock != 4 {
+			t.Errorf("updated event data = %+v; want stock 4", e.Data)
+		}
+	}
+}
+
+// publishUntil повторює подію, поки тест не отримає її з потоку
+func publishUntil(stop <-chan struct{}, e events.Event) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(20 * time.Millisecond):
+			handlers.Events.Publish(e)
+		}
+	}
+}
+
+// ------------------ /events через SSE ------------------
+func TestEventsSSE(t *testing.T) {
+	router := handlers.NewAPI()
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated /events = %d; want 401", resp.StatusCode)
+	}
+
+	id := primitive.NewObjectID().Hex()
+	token := loginToken(t, router, "reader", "reader123")
+	resp, err = http.Get(server.URL + "/events?resource=appointments&id=" + id + "&access_token=" + token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go publishUntil(stop, events.Event{Type: events.Deleted, Resource: "medications", ResourceID: id})
+	go publishUntil(stop, events.Event{Type: events.Created, Resource: "appointments", ResourceID: id})
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") && line != "event: created" {
+			t.Fatalf("filtered stream delivered %q", line)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var e events.Event
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.Resource != "appointments" || e.ResourceID != id {
+				t.Errorf("event = %+v", e)
+			}
+			return
+		}
+	}
+	t.Fatal("stream ended without an event:", scanner.Err())
+}
+
+// ------------------ /events через WebSocket ------------------
+func TestEventsWebSocket(t *testing.T) {
+	router := handlers.NewAPI()
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	token := loginToken(t, router, "admin", "admin123")
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/events?resource=admissions"
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer " + token}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go publishUntil(stop, events.Event{Type: events.Updated, Resource: "admissions", ResourceID: "a1"})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var e events.Event
+	if err := conn.ReadJSON(&e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != events.Updated || e.Resource != "admissions" || e.ID == "" {
+		t.Errorf("event = %+v", e)
+	}
+}
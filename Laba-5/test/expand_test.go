@@ -0,0 +1,40 @@
+package math
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hospital-api/handlers"
+)
+
+// ------------------ expand не обходить X-API-KEY ------------------
+func TestExpandRequiresAPIKey(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+
+	tests := []struct {
+		name string
+		path string
+		key  string
+	}{
+		{"staff hospital", "/staff?expand=hospital", ""},
+		{"staff department", "/staff?expand=department", ""},
+		{"appointment doctor", "/appointments?expand=doctor", ""},
+		{"wrong key", "/appointments/507f1f77bcf86cd799439011?expand=doctor", "nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+reader)
+			if tt.key != "" {
+				req.Header.Set("X-API-KEY", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("GET %s = %d %q; want 401", tt.path, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
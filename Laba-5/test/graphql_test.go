@@ -0,0 +1,266 @@
+package math
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"hospital-api/handlers"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type graphqlResult struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+func (r graphqlResult) messages() string {
+	var out []string
+	for _, e := range r.Errors {
+		out = append(out, e.Message)
+	}
+	return strings.Join(out, "; ")
+}
+
+// graphqlPost виконує запит до /graphql з токеном (порожній - без
+// заголовка) і X-API-KEY
+func graphqlPost(t *testing.T, router http.Handler, token, query string) (int, graphqlResult) {
+	t.Helper()
+	return graphqlPostKey(t, router, token, "my-secret-key", query)
+}
+
+func graphqlPostKey(t *testing.T, router http.Handler, token, key, query string) (int, graphqlResult) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if key != "" {
+		req.Header.Set("X-API-KEY", key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var result graphqlResult
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("decode %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, result
+}
+
+// ------------------ Типи GraphQL збігаються з моделями ------------------
+func TestGraphQLSchemaFromModels(t *testing.T) {
+	router := handlers.NewAPI()
+	token := loginToken(t, router, "reader", "reader123")
+
+	types := map[string]interface{}{
+		"Hospital":    models.Hospital{},
+		"Department":  models.Department{},
+		"Doctor":      models.Doctor{},
+		"Appointment": models.Appointment{},
+	}
+	relations := map[string][]string{
+		"Hospital":    {"departments"},
+		"Department":  {"hospital", "doctors"},
+		"Doctor":      {"department", "appointments"},
+		"Appointment": {"doctor"},
+	}
+	for name, model := range types {
+		_, res := graphqlPost(t, router, token, `{ __type(name: "`+name+`") { fields { name } } }`)
+		typ, _ := res.Data["__type"].(map[string]interface{})
+		if typ == nil {
+			t.Fatalf("%s: no type in %+v", name, res)
+		}
+		fields := map[string]bool{}
+		for _, f := range typ["fields"].([]interface{}) {
+			fields[f.(map[string]interface{})["name"].(string)] = true
+		}
+		for _, field := range append(jsonFields(reflect.TypeOf(model)), relations[name]...) {
+			if !fields[field] {
+				t.Errorf("%s has no field %q (fields %v)", name, field, fields)
+			}
+		}
+	}
+}
+
+// jsonFields - імена полів моделі в JSON, з урахуванням вбудованих структур
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			names = append(names, jsonFields(f.Type)...)
+			continue
+		}
+		if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ------------------ Авторизація запиту й полів ------------------
+func TestGraphQLAuthorization(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+	admin := loginToken(t, router, "admin", "admin123")
+
+	if code, _ := graphqlPost(t, router, "", `{ hospitals { id } }`); code != http.StatusUnauthorized {
+		t.Errorf("without token: %d, want 401", code)
+	}
+
+	// Лікарні, відділення й лікарі - лише з X-API-KEY, як у REST і gRPC,
+	// зокрема через зв'язки записів
+	for _, query := range []string{
+		`{ hospitals { id } }`,
+		`{ department(id: "507f1f77bcf86cd799439011") { id } }`,
+		`{ doctors { id } }`,
+	} {
+		for _, key := range []string{"", "wrong"} {
+			code, res := graphqlPostKey(t, router, admin, key, query)
+			if code != http.StatusOK || !strings.Contains(res.messages(), "requires a valid X-API-KEY") {
+				t.Errorf("%s with key %q: %d %+v", query, key, code, res)
+			}
+		}
+	}
+
+	// Бронювання - лише admin; помилка поля, а не всього запиту
+	code, res := graphqlPost(t, router, reader, `mutation { bookAppointment(patientId: "a", doctorId: "b") { id } }`)
+	if code != http.StatusOK || res.Data != nil || !strings.Contains(res.messages(), "Forbidden") {
+		t.Errorf("reader booking: %d %+v", code, res)
+	}
+
+	_, res = graphqlPost(t, router, reader, `{ hospital(id: "507f1f77bcf86cd799439011", includeDeleted: true) { id } }`)
+	if !strings.Contains(res.messages(), "includeDeleted requires an admin token") {
+		t.Errorf("reader includeDeleted: %+v", res)
+	}
+
+	_, res = graphqlPost(t, router, admin, `{ doctor(id: "nope") { id } }`)
+	if !strings.Contains(res.messages(), "Invalid ID") {
+		t.Errorf("invalid id: %+v", res)
+	}
+
+	// Валідація - та сама, що в POST /appointments
+	_, res = graphqlPost(t, router, admin, `mutation { bookAppointment(patientId: "bad", doctorId: "bad") { id } }`)
+	if msg := res.messages(); !strings.Contains(msg, "patientId") || !strings.Contains(msg, "doctorId") {
+		t.Errorf("admin booking invalid ids: %+v", res)
+	}
+
+	_, res = graphqlPost(t, router, reader, `{ hospitals { nope } }`)
+	if !strings.Contains(res.messages(), `Cannot query field "nope"`) {
+		t.Errorf("unknown field: %+v", res)
+	}
+}
+
+func TestGraphQLOverGET(t *testing.T) {
+	router := handlers.NewAPI()
+	token := loginToken(t, router, "reader", "reader123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(query), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(`mutation { bookAppointment(patientId: "a", doctorId: "b") { id } }`)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("mutation over GET: %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := get(""); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty query: %d", rec.Code)
+	}
+	rec = get(`{ __typename }`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"__typename":"Query"`) {
+		t.Errorf("query over GET: %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+// ------------------ Зв'язки догружаються одним запитом на рівень ------------------
+func TestGraphQLBatchesRelations(t *testing.T) {
+	var hospitals []models.Hospital
+	var departments []models.Department
+	var doctors []models.Doctor
+	for h := 0; h < 3; h++ {
+		hospital := models.Hospital{ID: primitive.NewObjectID()}
+		hospitals = append(hospitals, hospital)
+		for d := 0; d < 2; d++ {
+			department := models.Department{ID: primitive.NewObjectID(), HospitalID: hospital.ID}
+			departments = append(departments, department)
+			for i := 0; i < 2; i++ {
+				doctors = append(doctors, models.Doctor{ID: primitive.NewObjectID(), Department: department.ID})
+			}
+		}
+	}
+
+	// Фейкові джерела віддають усе: loader сам групує документи за ключем
+	var mu sync.Mutex
+	calls := map[string]int{}
+	count := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[name]++
+	}
+	saved := handlers.GraphQLData
+	defer func() { handlers.GraphQLData = saved }()
+	handlers.GraphQLData = handlers.GraphQLSource{
+		Hospitals: func(context.Context, bson.M) ([]models.Hospital, error) {
+			count("hospitals")
+			return hospitals, nil
+		},
+		Departments: func(context.Context, bson.M) ([]models.Department, error) {
+			count("departments")
+			return departments, nil
+		},
+		Doctors: func(context.Context, bson.M) ([]models.Doctor, error) {
+			count("doctors")
+			return doctors, nil
+		},
+		Appointments: func(context.Context, bson.M) ([]models.Appointment, error) {
+			count("appointments")
+			return []models.Appointment{{ID: primitive.NewObjectID(), DoctorID: doctors[0].ID}}, nil
+		},
+	}
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+
+	_, res := graphqlPost(t, router, reader, `{ hospitals { departments { doctors { id } } } }`)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %s", res.messages())
+	}
+	found := 0
+	for _, h := range res.Data["hospitals"].([]interface{}) {
+		for _, d := range h.(map[string]interface{})["departments"].([]interface{}) {
+			found += len(d.(map[string]interface{})["doctors"].([]interface{}))
+		}
+	}
+	if found != len(doctors) {
+		t.Errorf("%d doctors in response; want %d", found, len(doctors))
+	}
+	if want := map[string]int{"hospitals": 1, "departments": 1, "doctors": 1}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("queries = %v; want %v", calls, want)
+	}
+
+	// Записи доступні з токеном, а лікар у них - лише з X-API-KEY
+	_, res = graphqlPostKey(t, router, reader, "", `{ appointments { id doctor { id } } }`)
+	if len(res.Errors) != 1 || !strings.Contains(res.messages(), "doctor requires a valid X-API-KEY") || calls["doctors"] != 1 {
+		t.Errorf("appointment doctor without key: %+v, queries %v", res, calls)
+	}
+}
@@ -0,0 +1,136 @@
+package math
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-api/events"
+	"hospital-api/handlers"
+	"hospital-api/models"
+	pb "hospital-api/proto/hospitalpb"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcConn - з'єднання з gRPC-сервером у пам'яті
+func grpcConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := handlers.NewGRPCServer()
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withMD(t *testing.T, pairs ...string) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs(pairs...))
+}
+
+func wantCode(t *testing.T, name string, err error, code codes.Code, message string) {
+	t.Helper()
+	st, _ := status.FromError(err)
+	if st.Code() != code || !strings.Contains(st.Message(), message) {
+		t.Errorf("%s: %v, want %s %q", name, err, code, message)
+	}
+}
+
+// ------------------ Авторизація як у REST ------------------
+func TestGRPCAuth(t *testing.T) {
+	conn := grpcConn(t)
+	router := handlers.NewAPI()
+	reader := "Bearer " + loginToken(t, router, "reader", "reader123")
+	id := primitive.NewObjectID().Hex()
+
+	hospitals := pb.NewHospitalServiceClient(conn)
+	_, err := hospitals.GetHospital(withMD(t), &pb.GetRequest{Id: id})
+	wantCode(t, "hospital without key", err, codes.Unauthenticated, "Unauthorized")
+
+	_, err = hospitals.GetHospital(withMD(t, "x-api-key", "my-secret-key", "authorization", reader), &pb.GetRequest{Id: id, IncludeDeleted: true})
+	wantCode(t, "include_deleted as reader", err, codes.PermissionDenied, "includeDeleted requires an admin token")
+
+	_, err = hospitals.GetHospital(withMD(t, "x-api-key", "my-secret-key"), &pb.GetRequest{Id: "nope"})
+	wantCode(t, "invalid id", err, codes.InvalidArgument, "Invalid ID")
+
+	// Потокові методи перевіряються так само; помилка приходить з першим Recv
+	stream, err := pb.NewStaffServiceClient(conn).ListStaff(withMD(t, "accept-language", "uk"), &pb.ListStaffRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	wantCode(t, "staff without token", err, codes.Unauthenticated, "Токен відсутній або недійсний")
+
+	appointments := pb.NewAppointmentServiceClient(conn)
+	_, err = appointments.CreateAppointment(withMD(t, "authorization", reader), &pb.CreateAppointmentRequest{})
+	wantCode(t, "create as reader", err, codes.PermissionDenied, "Forbidden")
+
+	_, err = appointments.DeleteAppointment(withMD(t, "authorization", "Bearer broken"), &pb.DeleteAppointmentRequest{Id: id})
+	wantCode(t, "bad token", err, codes.Unauthenticated, "Unauthorized")
+}
+
+func TestGRPCValidation(t *testing.T) {
+	conn := grpcConn(t)
+	admin := "Bearer " + loginToken(t, handlers.NewAPI(), "admin", "admin123")
+
+	_, err := pb.NewAppointmentServiceClient(conn).CreateAppointment(withMD(t, "authorization", admin),
+		&pb.CreateAppointmentRequest{PatientId: "bad"})
+	wantCode(t, "empty appointment", err, codes.InvalidArgument, "patientId")
+	wantCode(t, "empty appointment", err, codes.InvalidArgument, "doctorId")
+}
+
+// ------------------ Потік змін записів ------------------
+func TestGRPCWatchAppointments(t *testing.T) {
+	conn := grpcConn(t)
+	reader := "Bearer " + loginToken(t, handlers.NewAPI(), "reader", "reader123")
+	watched := primitive.NewObjectID()
+
+	stream, err := pb.NewAppointmentServiceClient(conn).WatchAppointments(withMD(t, "authorization", reader),
+		&pb.WatchAppointmentsRequest{Ids: []string{watched.Hex()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Заголовки приходять, коли підписка вже діє
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	handlers.Events.Publish(events.Event{Type: events.Created, Resource: "medications", ResourceID: watched.Hex()})
+	handlers.Events.Publish(events.Event{Type: events.Created, Resource: "appointments", ResourceID: primitive.NewObjectID().Hex()})
+	handlers.Events.Publish(events.Event{Type: events.Updated, Resource: "appointments", ResourceID: watched.Hex(),
+		Data: models.Appointment{ID: watched, DoctorID: primitive.NewObjectID(), Date: date}})
+	handlers.Events.Publish(events.Event{Type: events.Deleted, Resource: "appointments", ResourceID: watched.Hex()})
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.GetType() != pb.AppointmentEvent_UPDATED || event.GetAppointmentId() != watched.Hex() ||
+		event.GetAppointment().GetId() != watched.Hex() || !event.GetAppointment().GetDate().AsTime().Equal(date) {
+		t.Errorf("first event = %v", event)
+	}
+	event, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.GetType() != pb.AppointmentEvent_DELETED || event.GetAppointment() != nil {
+		t.Errorf("second event = %v", event)
+	}
+}
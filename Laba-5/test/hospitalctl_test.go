@@ -0,0 +1,265 @@
+package math
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+	"hospital-api/hospitalctl"
+)
+
+// runCLI запускає hospitalctl з окремою текою конфігурації і фіксованим оточенням
+func runCLI(t *testing.T, configDir, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	env := map[string]string{"HOSPITALCTL_CONFIG": configDir}
+	cli := &hospitalctl.CLI{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Getenv: func(name string) string { return env[name] },
+	}
+	code := cli.Run(args)
+	return code, stdout.String(), stderr.String()
+}
+
+// ------------------ Ресурси CLI збігаються зі специфікацією ------------------
+func TestCLIResourcesMatchOpenAPI(t *testing.T) {
+	spec := fetchSpec(t, handlers.NewAPI())
+
+	queryParams := func(path, method string) []string {
+		var names []string
+		for _, p := range spec.Paths[path][method].Parameters {
+			if p.In == "query" {
+				names = append(names, p.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+	filterNames := func(filters []hospitalctl.Filter) []string {
+		var names []string
+		for _, f := range filters {
+			names = append(names, f.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	operations := map[string][2]string{
+		hospitalctl.List:   {"", "get"},
+		hospitalctl.Get:    {"/{id}", "get"},
+		hospitalctl.Create: {"", "post"},
+		hospitalctl.Update: {"/{id}", "put"},
+		hospitalctl.Delete: {"/{id}", "delete"},
+	}
+	for _, r := range hospitalctl.Resources {
+		for verb, op := range operations {
+			_, documented := spec.Paths[r.Path+op[0]][op[1]]
+			if documented != r.Supports(verb) {
+				t.Errorf("%s: CLI supports %s = %v, API documents it = %v", r.Name, verb, r.Supports(verb), documented)
+			}
+		}
+		if got, want := filterNames(r.Filters), queryParams(r.Path, "get"); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s list filters = %v, API query parameters = %v", r.Name, got, want)
+		}
+		if r.Supports(hospitalctl.Get) {
+			if got, want := filterNames(r.GetFilters()), queryParams(r.Path+"/{id}", "get"); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s get filters = %v, API query parameters = %v", r.Name, got, want)
+			}
+		}
+	}
+}
+
+// ------------------ login проти справжнього роутера ------------------
+func TestCLILogin(t *testing.T) {
+	server := httptest.NewServer(handlers.NewAPI())
+	defer server.Close()
+	dir := t.TempDir()
+
+	code, _, stderr := runCLI(t, dir, "wrong\n", "login", "--server", server.URL, "--username", "reader", "--password-stdin")
+	if code != 1 || !strings.Contains(stderr, "401") {
+		t.Fatalf("bad password: code %d, stderr %q", code, stderr)
+	}
+
+	code, stdout, stderr := runCLI(t, dir, "reader123\n", "login", "--server", server.URL+"/", "--username", "reader", "--password-stdin")
+	if code != 0 {
+		t.Fatalf("login: code %d, stderr %q", code, stderr)
+	}
+	if !strings.Contains(stdout, "Logged in to "+server.URL+" as reader") {
+		t.Errorf("stdout = %q", stdout)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "credentials.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("credentials.json mode = %o, want 600", perm)
+	}
+	var creds map[string]hospitalctl.Credential
+	data, _ := os.ReadFile(filepath.Join(dir, "credentials.json"))
+	if err := json.Unmarshal(data, &creds); err != nil {
+		t.Fatal(err)
+	}
+	cred := creds[server.URL]
+	if cred.Token == "" || cred.Username != "reader" || cred.ExpiresAt.IsZero() {
+		t.Errorf("cached credential = %+v", cred)
+	}
+
+	// Кешований токен іде в запит: webhooks лише для admin, тож 403, а не 401
+	code, _, stderr = runCLI(t, dir, "", "list", "webhooks", "--server", server.URL)
+	if code != 1 || !strings.Contains(stderr, "403") {
+		t.Errorf("reader listing webhooks: code %d, stderr %q", code, stderr)
+	}
+
+	runCLI(t, dir, "", "logout", "--server", server.URL)
+	code, _, stderr = runCLI(t, dir, "", "list", "webhooks", "--server", server.URL)
+	if code != 1 || !strings.Contains(stderr, "401") || !strings.Contains(stderr, "hospitalctl login") {
+		t.Errorf("after logout: code %d, stderr %q", code, stderr)
+	}
+}
+
+// ------------------ CRUD і формати виводу ------------------
+type recordedRequest struct {
+	Method, Path, Query, APIKey, Body string
+}
+
+func fakeAPI(t *testing.T) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("X-API-KEY"), string(body)})
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/hospitals":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			io.WriteString(w, `[{"id":"h1","name":"Обласна","location":"Київ","beds":120},{"id":"h2","name":"Міська","location":"Львів","beds":80}]`)
+		case r.Method == http.MethodGet && r.URL.Path == "/hospitals/h1":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			io.WriteString(w, `{"id":"h1","name":"Обласна","location":"Київ","beds":120}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/hospitals":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write(body)
+		case r.Method == http.MethodPut, r.Method == http.MethodDelete && r.URL.Path == "/hospitals/h1":
+			io.WriteString(w, "Hospital updated successfully")
+		default:
+			http.Error(w, "Hospital not found", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestCLICrud(t *testing.T) {
+	server, requests := fakeAPI(t)
+	dir := t.TempDir()
+	common := []string{"--server", server.URL, "--api-key", "secret"}
+
+	code, stdout, stderr := runCLI(t, dir, "", append([]string{"list", "hospitals", "--city", "Київ", "--minBeds=50", "--includeDeleted"}, common...)...)
+	if code != 0 {
+		t.Fatalf("list: code %d, stderr %q", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "LOCATION") || !strings.Contains(lines[1], "Обласна") {
+		t.Errorf("table output:\n%s", stdout)
+	}
+	if got := (*requests)[0]; got.Query != "city=%D0%9A%D0%B8%D1%97%D0%B2&includeDeleted=true&minBeds=50" || got.APIKey != "secret" {
+		t.Errorf("list request = %+v", got)
+	}
+
+	code, stdout, _ = runCLI(t, dir, "", append([]string{"get", "hospital", "h1", "-o", "json"}, common...)...)
+	var got map[string]interface{}
+	if code != 0 || json.Unmarshal([]byte(stdout), &got) != nil || got["name"] != "Обласна" {
+		t.Errorf("get -o json: code %d, stdout %q", code, stdout)
+	}
+
+	code, stdout, _ = runCLI(t, dir, "", append([]string{"get", "hospitals", "h1", "-o", "yaml"}, common...)...)
+	if want := "id: h1\nname: Обласна\nlocation: Київ\nbeds: 120\n"; code != 0 || stdout != want {
+		t.Errorf("get -o yaml = %q, want %q", stdout, want)
+	}
+
+	// YAML з stdin стає JSON-тілом запиту
+	code, _, stderr = runCLI(t, dir, "name: Нова\nlocation: Одеса\nbeds: 10\n", append([]string{"create", "hospitals", "-f", "-"}, common...)...)
+	if code != 0 {
+		t.Fatalf("create: code %d, stderr %q", code, stderr)
+	}
+	var created map[string]interface{}
+	if err := json.Unmarshal([]byte((*requests)[3].Body), &created); err != nil || created["beds"] != float64(10) {
+		t.Errorf("create body = %q", (*requests)[3].Body)
+	}
+
+	code, stdout, _ = runCLI(t, dir, "", append([]string{"update", "hospitals", "h1", "--data", `{"name":"Оновлена"}`}, common...)...)
+	if code != 0 || strings.TrimSpace(stdout) != "Hospital updated successfully" {
+		t.Errorf("update: code %d, stdout %q", code, stdout)
+	}
+
+	code, _, stderr = runCLI(t, dir, "", append([]string{"delete", "hospitals", "missing"}, common...)...)
+	if code != 1 || !strings.Contains(stderr, "404 Hospital not found") {
+		t.Errorf("delete missing: code %d, stderr %q", code, stderr)
+	}
+}
+
+func TestCLIUsageErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", "patients"}, "unknown resource"},
+		{[]string{"update", "wards", "w1", "--data", "{}"}, "wards does not support update"},
+		{[]string{"get", "hospitals"}, "needs an id"},
+		{[]string{"list", "hospitals", "-o", "xml"}, "unknown output format"},
+		{[]string{"list", "wards", "--city", "Київ"}, "flag provided but not defined: -city"},
+		{[]string{"create", "hospitals"}, "a document is required"},
+		{[]string{"frobnicate"}, "unknown command"},
+	}
+	for _, tt := range tests {
+		code, _, stderr := runCLI(t, dir, "", tt.args...)
+		if code != 2 || !strings.Contains(stderr, tt.want) {
+			t.Errorf("%v: code %d, stderr %q, want %q", tt.args, code, stderr, tt.want)
+		}
+	}
+}
+
+// ------------------ Доповнення ------------------
+func TestCLICompletion(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"l"}, []string{"login", "logout", "list"}},
+		{[]string{"update", "w"}, []string{"webhooks"}},
+		{[]string{"list", "w"}, []string{"wards", "webhooks"}},
+		{[]string{"list", "beds", "--w"}, []string{"--wardId"}},
+		{[]string{"get", "hospitals", "--server", "http://x", "--i"}, []string{"--includeDeleted"}},
+		{[]string{"list", "hospitals", "-o", "y"}, []string{"yaml"}},
+		{[]string{"completion", ""}, []string{"bash", "fish", "zsh"}},
+		{[]string{"get", "hospitals", "h1", ""}, nil},
+	}
+	for _, tt := range tests {
+		if got := hospitalctl.Complete(tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		code, stdout, _ := runCLI(t, t.TempDir(), "", "completion", shell)
+		if code != 0 || !strings.Contains(stdout, "hospitalctl __complete") {
+			t.Errorf("completion %s: code %d, output %q", shell, code, stdout)
+		}
+	}
+	code, stdout, _ := runCLI(t, t.TempDir(), "", "__complete", "list", "hosp")
+	if code != 0 || stdout != "hospitals\n" {
+		t.Errorf("__complete output = %q", stdout)
+	}
+}
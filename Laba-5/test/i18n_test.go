@@ -0,0 +1,136 @@
+package math
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+	"hospital-api/i18n"
+	"hospital-api/models"
+)
+
+// ------------------ Повнота каталогів ------------------
+func TestCatalogsComplete(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	en := i18n.Catalog(i18n.English)
+	if len(en) == 0 {
+		t.Fatal("english catalog is empty")
+	}
+	for _, lang := range i18n.Languages() {
+		catalog := i18n.Catalog(lang)
+		for key, msg := range en {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing %q", lang, key)
+				continue
+			}
+			if got, want := verbs.FindAllString(translated, -1), verbs.FindAllString(msg, -1); strings.Join(got, "") != strings.Join(want, "") {
+				t.Errorf("%s: %q has verbs %v, english has %v", lang, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s: %q is not in the english catalog", lang, key)
+			}
+		}
+	}
+}
+
+func TestMatchLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                i18n.English,
+		"uk":              i18n.Ukrainian,
+		"uk-UA,uk;q=0.9":  i18n.Ukrainian,
+		"en;q=0.5, uk":    i18n.Ukrainian,
+		"en-GB":           i18n.English,
+		"fr":              i18n.English,
+		"not a header;;;": i18n.English,
+	}
+	for header, want := range tests {
+		if got := i18n.Match(header); got != want {
+			t.Errorf("Match(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestTranslateFallback(t *testing.T) {
+	if got := i18n.T(i18n.Ukrainian, "search.unknown_type", "x"); !strings.Contains(got, "x") {
+		t.Errorf("argument not substituted: %q", got)
+	}
+	if got := i18n.T("de", "auth.forbidden"); got != i18n.T(i18n.English, "auth.forbidden") {
+		t.Errorf("unknown language should fall back to english, got %q", got)
+	}
+	if got := i18n.T(i18n.Ukrainian, "no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key should be returned as is, got %q", got)
+	}
+}
+
+// ------------------ Локалізовані відповіді ------------------
+func TestLocalizedResponses(t *testing.T) {
+	router := handlers.NewAPI()
+
+	tests := []struct {
+		name     string
+		path     string
+		language string
+		status   int
+		want     string
+	}{
+		{"root en", "/", "", http.StatusOK, i18n.T(i18n.English, "api.root")},
+		{"root uk", "/", "uk-UA", http.StatusOK, i18n.T(i18n.Ukrainian, "api.root")},
+		{"unauthorized en", "/metrics", "fr", http.StatusUnauthorized, i18n.T(i18n.English, "auth.invalid_token")},
+		{"unauthorized uk", "/metrics", "uk", http.StatusUnauthorized, i18n.T(i18n.Ukrainian, "auth.invalid_token")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.language != "" {
+				req.Header.Set("Accept-Language", tt.language)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			wantLang := i18n.Match(tt.language)
+			if got := rec.Header().Get("Content-Language"); got != wantLang {
+				t.Errorf("Content-Language = %q, want %q", got, wantLang)
+			}
+			if !strings.Contains(rec.Header().Get("Vary"), "Accept-Language") {
+				t.Errorf("Vary = %q, want Accept-Language", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestLocalizedValidation(t *testing.T) {
+	err := models.Hospital{Beds: -1}.Validate()
+	var verrs models.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+	if verrs[0].Code != "validation.required" || verrs[0].Message != "is required" {
+		t.Errorf("english error = %+v", verrs[0])
+	}
+
+	uk := verrs.Localize(i18n.Ukrainian)
+	for i, fe := range uk {
+		if fe.Field != verrs[i].Field || fe.Code != verrs[i].Code {
+			t.Errorf("localize changed field or code: %+v", fe)
+		}
+		if want := i18n.T(i18n.Ukrainian, fe.Code); fe.Message != want {
+			t.Errorf("message = %q, want %q", fe.Message, want)
+		}
+	}
+	if verrs[0].Message != "is required" {
+		t.Error("Localize must not modify the original errors")
+	}
+}
@@ -0,0 +1,171 @@
+package math
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hospital-api/handlers"
+	"hospital-api/idempotency"
+)
+
+// idempotentServer - лічильник викликів за Idempotency-Key middleware
+func idempotentServer(status int) (http.Handler, *int32) {
+	var calls int32
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `,"body":` + string(body) + `}`))
+	})
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, func(*http.Request) string { return "user" })
+	return mw(h), &calls
+}
+
+func postWithKey(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.Header, key)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	h, calls := idempotentServer(http.StatusCreated)
+
+	first := postWithKey(h, "k1", `{"a":1}`)
+	second := postWithKey(h, "k1", `{"a":1}`)
+
+	if *calls != 1 {
+		t.Fatalf("handler called %d times, want 1", *calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %q, want %d %q", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Errorf("replay is missing %s header", idempotency.ReplayedHeader)
+	}
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("replay Content-Type = %q", second.Header().Get("Content-Type"))
+	}
+	if first.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Error("first response must not be marked as replayed")
+	}
+}
+
+func TestIdempotencyKeyReusedWithDifferentBody(t *testing.T) {
+	h, calls := idempotentServer(http.StatusCreated)
+
+	postWithKey(h, "k1", `{"a":1}`)
+	rr := postWithKey(h, "k1", `{"a":2}`)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", rr.Code)
+	}
+	if *calls != 1 {
+		t.Errorf("handler called %d times, want 1", *calls)
+	}
+}
+
+func TestIdempotencyWithoutKey(t *testing.T) {
+	h, calls := idempotentServer(http.StatusCreated)
+
+	postWithKey(h, "", `{"a":1}`)
+	postWithKey(h, "", `{"a":1}`)
+
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want 2", *calls)
+	}
+}
+
+func TestIdempotencyServerErrorIsNotStored(t *testing.T) {
+	h, calls := idempotentServer(http.StatusInternalServerError)
+
+	postWithKey(h, "k1", `{"a":1}`)
+	rr := postWithKey(h, "k1", `{"a":1}`)
+
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want 2 (5xx must release the key)", *calls)
+	}
+	if rr.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Error("5xx response must not be replayed")
+	}
+}
+
+// failingCompleteStore - сховище, що не може зберегти відповідь
+type failingCompleteStore struct {
+	*idempotency.MemoryStore
+}
+
+func (failingCompleteStore) Complete(context.Context, idempotency.Record) error {
+	return errors.New("store is down")
+}
+
+func TestIdempotencyCompleteFailureReleasesKey(t *testing.T) {
+	var calls int32
+	h := idempotency.Middleware(failingCompleteStore{idempotency.NewMemoryStore()}, time.Hour, func(*http.Request) string { return "user" })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	postWithKey(h, "k1", `{"a":1}`)
+	rr := postWithKey(h, "k1", `{"a":1}`)
+
+	if rr.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("retry = %d after %d calls; want 201 after 2 (key must not stay in progress)", rr.Code, calls)
+	}
+}
+
+func TestIdempotencyOnAPI(t *testing.T) {
+	handlers.Idempotency = idempotency.NewMemoryStore()
+	defer func() { handlers.Idempotency = idempotency.MongoStore{} }()
+	router := handlers.NewAPI()
+
+	// Без API-ключа - 401; такі відповіді не зберігаються, повтор теж 401, а не 422
+	for i, body := range []string{`{"a":1}`, `{"a":2}`} {
+		rr := postWithKey(router, "k1", body)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status = %d, want 401", i, rr.Code)
+		}
+	}
+}
+
+// Ключ перевіряється після авторизації: чужий запит з тим самим
+// Idempotency-Key і тілом не отримує збережену відповідь
+func TestIdempotencyReplayRequiresAuth(t *testing.T) {
+	h, calls := idempotentServer(http.StatusCreated)
+	router := handlers.NewRouter()
+	router.UseInner(func(http.Handler) http.Handler { return h })
+	router.Group("/appointments", handlers.APIKeyMiddleware).HandleFunc(http.MethodPost, "", nil)
+
+	send := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/appointments", strings.NewReader(`{"a":1}`))
+		req.Header.Set(idempotency.Header, "k1")
+		req.Header.Set("X-API-KEY", apiKey)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := send("my-secret-key"); rr.Code != http.StatusCreated {
+		t.Fatalf("first request: status = %d, want 201", rr.Code)
+	}
+	rr := send("wrong-key")
+	if rr.Code != http.StatusUnauthorized || rr.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Errorf("wrong key: status = %d, replayed = %q; want 401 without replay", rr.Code, rr.Header().Get(idempotency.ReplayedHeader))
+	}
+	if rr := send("my-secret-key"); rr.Header().Get(idempotency.ReplayedHeader) != "true" || *calls != 1 {
+		t.Errorf("retry with the key: replayed = %q, calls = %d", rr.Header().Get(idempotency.ReplayedHeader), *calls)
+	}
+}
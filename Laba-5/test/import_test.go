@@ -0,0 +1,41 @@
+package math
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+)
+
+// ------------------ Битий рядок CSV - помилка рядка, а не всього імпорту ------------------
+func TestImportMalformedCSVRow(t *testing.T) {
+	router := handlers.NewAPI()
+	file := "name,beds\nFirst,10\nba\"d,2\nThird,5\n"
+	req := httptest.NewRequest(http.MethodPost, "/hospitals/import?dryRun=true", strings.NewReader(file))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("X-API-KEY", "my-secret-key")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var result struct {
+		Total  int `json:"total"`
+		Valid  int `json:"valid"`
+		Failed int `json:"failed"`
+		Errors []struct {
+			Line  int    `json:"line"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("%d %q: %v", rec.Code, rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || result.Total != 3 || result.Valid != 2 || result.Failed != 1 {
+		t.Errorf("import = %d %+v", rec.Code, result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Line != 3 || !strings.Contains(result.Errors[0].Error, "quote") {
+		t.Errorf("errors = %+v; want bare quote on line 3", result.Errors)
+	}
+}
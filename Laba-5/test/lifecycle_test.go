@@ -0,0 +1,124 @@
+package math
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+	"hospital-api/models"
+)
+
+// ------------------ Дозволені переходи стану запису ------------------
+func TestAppointmentTransitions(t *testing.T) {
+	allowed := map[string][]string{
+		"check-in": {models.AppointmentBooked},
+		"start":    {models.AppointmentCheckedIn},
+		"complete": {models.AppointmentInProgress},
+		"cancel":   {models.AppointmentBooked, models.AppointmentCheckedIn},
+		"no-show":  {models.AppointmentBooked},
+	}
+	if len(models.AppointmentTransitions) != len(allowed) {
+		t.Fatalf("%d transitions; want %d", len(models.AppointmentTransitions), len(allowed))
+	}
+	for _, tr := range models.AppointmentTransitions {
+		for _, status := range models.AppointmentStatuses {
+			want := false
+			for _, from := range allowed[tr.Action] {
+				want = want || from == status
+			}
+			if got := tr.Allows(status); got != want {
+				t.Errorf("%s from %s = %v; want %v", tr.Action, status, got, want)
+			}
+		}
+		// Старі записи без статусу - booked
+		if tr.Allows("") != tr.Allows(models.AppointmentBooked) {
+			t.Errorf("%s: empty status differs from booked", tr.Action)
+		}
+	}
+
+	var legacy models.Appointment
+	if legacy.State() != models.AppointmentBooked {
+		t.Errorf("State() of legacy appointment = %q", legacy.State())
+	}
+
+	// Лише скасовані записи й неявки не займають час лікаря: відновлення
+	// й імпорт решти перевіряють слот
+	for _, status := range models.AppointmentStatuses {
+		l := models.AppointmentLifecycle{Status: status}
+		want := status == models.AppointmentCancelled || status == models.AppointmentNoShow
+		if l.Released() != want {
+			t.Errorf("Released() of %s = %v; want %v", status, l.Released(), want)
+		}
+	}
+}
+
+func TestAppointmentStatusValidation(t *testing.T) {
+	if err := (models.AppointmentCancellation{Reason: "  "}).Validate(); err == nil {
+		t.Error("blank cancel reason accepted")
+	}
+	if err := (models.AppointmentCancellation{Reason: "patient called"}).Validate(); err != nil {
+		t.Errorf("cancel reason rejected: %v", err)
+	}
+}
+
+// ------------------ Переходи - лише для admin, тіло перевіряється до БД ------------------
+func TestAppointmentTransitionRoutes(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+	admin := loginToken(t, router, "admin", "admin123")
+
+	post := func(path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, tr := range models.AppointmentTransitions {
+		path := "/appointments/507f1f77bcf86cd799439011/" + tr.Action
+		if rec := post(path, "", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("POST %s without token = %d; want 401", path, rec.Code)
+		}
+		if rec := post(path, reader, ""); rec.Code != http.StatusForbidden {
+			t.Errorf("POST %s as reader = %d; want 403", path, rec.Code)
+		}
+		if rec := post("/appointments/nope/"+tr.Action, admin, `{"reason":"x"}`); rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s with invalid id = %d; want 400", tr.Action, rec.Code)
+		}
+	}
+
+	rec := post("/appointments/507f1f77bcf86cd799439011/cancel", admin, `{"reason":""}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "reason") {
+		t.Errorf("cancel without reason = %d %q; want 400", rec.Code, rec.Body.String())
+	}
+
+	// PUT переносить запис через ту саму перевірку слоту, тож неповне тіло
+	// відхиляється до звернення до БД
+	req := httptest.NewRequest(http.MethodPut, "/appointments/507f1f77bcf86cd799439011", strings.NewReader(`{"doctorId":"507f1f77bcf86cd799439012"}`))
+	req.Header.Set("Authorization", "Bearer "+admin)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "date") {
+		t.Errorf("PUT without date = %d %q; want 400", rec.Code, rec.Body.String())
+	}
+
+	// Імпорт записів іде через бронювання, але dry run лише перевіряє рядки
+	req = httptest.NewRequest(http.MethodPost, "/appointments/import?dryRun=true", strings.NewReader(
+		`{"patientId":"507f1f77bcf86cd799439011","doctorId":"507f1f77bcf86cd799439012","date":"2025-03-03T09:00:00Z"}`+"\n"+`{"doctorId":"507f1f77bcf86cd799439012"}`+"\n"))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Authorization", "Bearer "+admin)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if body := rec.Body.String(); rec.Code != http.StatusUnprocessableEntity || !strings.Contains(body, `"valid":1`) || !strings.Contains(body, `"failed":1`) {
+		t.Errorf("appointment import dry run = %d %s", rec.Code, body)
+	}
+	if rec := post("/appointments/nope/restore", admin, ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("restore with invalid id = %d; want 400", rec.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package math
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+)
+
+// ------------------ Видача ліків лише адміністратором ------------------
+func TestDispenseRequiresAdmin(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{"anonymous dispense", http.MethodPost, "/medications/507f1f77bcf86cd799439011/dispense", "", http.StatusUnauthorized},
+		{"reader dispense", http.MethodPost, "/medications/507f1f77bcf86cd799439011/dispense", reader, http.StatusForbidden},
+		{"anonymous dispensations", http.MethodGet, "/medications/507f1f77bcf86cd799439011/dispensations", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"patientId":"507f1f77bcf86cd799439012","quantity":1}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("%s %s = %d; want %d", tt.method, tt.path, rec.Code, tt.code)
+			}
+		})
+	}
+}
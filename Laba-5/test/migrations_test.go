@@ -0,0 +1,174 @@
+package math
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"hospital-api/db"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memoryLog - облік міграцій у пам'яті замість schema_migrations
+type memoryLog map[int]db.MigrationRecord
+
+func (l memoryLog) Applied(context.Context) (map[int]db.MigrationRecord, error) {
+	out := make(map[int]db.MigrationRecord, len(l))
+	for v, rec := range l {
+		out[v] = rec
+	}
+	return out, nil
+}
+
+func (l memoryLog) Record(_ context.Context, rec db.MigrationRecord) error {
+	l[rec.Version] = rec
+	return nil
+}
+
+func (l memoryLog) Forget(_ context.Context, version int) error {
+	delete(l, version)
+	return nil
+}
+
+// fakeMigrator - мігратор із кроками, що лише записують свій виклик у calls
+func fakeMigrator(calls *[]string, versions ...int) *db.Migrator {
+	var steps []db.Migration
+	for _, v := range versions {
+		name := "step" + strconv.Itoa(v)
+		steps = append(steps, db.Migration{
+			Version: v,
+			Name:    name,
+			Up:      func(context.Context, *mongo.Database) error { *calls = append(*calls, "up "+name); return nil },
+			Down:    func(context.Context, *mongo.Database) error { *calls = append(*calls, "down "+name); return nil },
+		})
+	}
+	return &db.Migrator{Steps: steps, Log: memoryLog{}}
+}
+
+func versions(ms []db.Migration) []int {
+	var out []int
+	for _, m := range ms {
+		out = append(out, m.Version)
+	}
+	return out
+}
+
+// ------------------ Міграції: порядок, повтор, відкат ------------------
+func TestMigrateUpOrderAndRerun(t *testing.T) {
+	var calls []string
+	mg := fakeMigrator(&calls, 3, 1, 2)
+	ctx := context.Background()
+
+	done, err := mg.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("applied %v; want [1 2 3]", got)
+	}
+	if want := []string{"up step1", "up step2", "up step3"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v; want %v", calls, want)
+	}
+
+	// Повторний запуск нічого не робить
+	calls = nil
+	done, err = mg.Up(ctx)
+	if err != nil || len(done) != 0 || len(calls) != 0 {
+		t.Errorf("rerun: done=%v calls=%v err=%v", versions(done), calls, err)
+	}
+}
+
+func TestMigrateDownSteps(t *testing.T) {
+	var calls []string
+	mg := fakeMigrator(&calls, 1, 2, 3)
+	ctx := context.Background()
+	if _, err := mg.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	calls = nil
+	done, err := mg.Down(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !reflect.DeepEqual(got, []int{3, 2}) {
+		t.Errorf("reverted %v; want [3 2]", got)
+	}
+	if want := []string{"down step3", "down step2"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v; want %v", calls, want)
+	}
+
+	statuses, err := mg.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := []bool{}
+	for _, st := range statuses {
+		applied = append(applied, st.Applied)
+	}
+	if !reflect.DeepEqual(applied, []bool{true, false, false}) {
+		t.Errorf("applied flags = %v; want [true false false]", applied)
+	}
+
+	// Відкат більшої кількості, ніж застосовано, зупиняється на порожньому
+	done, err = mg.Down(ctx, 5)
+	if err != nil || !reflect.DeepEqual(versions(done), []int{1}) {
+		t.Errorf("down 5: done=%v err=%v", versions(done), err)
+	}
+}
+
+func TestMigrateRefusesUnknownVersions(t *testing.T) {
+	var calls []string
+	mg := fakeMigrator(&calls, 1, 2)
+	mg.Log.Record(context.Background(), db.MigrationRecord{Version: 7, Name: "from a newer build", AppliedAt: time.Now()})
+
+	if _, err := mg.Up(context.Background()); !errors.Is(err, db.ErrUnknownMigration) {
+		t.Errorf("Up error = %v; want ErrUnknownMigration", err)
+	}
+	if _, err := mg.Down(context.Background(), 1); !errors.Is(err, db.ErrUnknownMigration) {
+		t.Errorf("Down error = %v; want ErrUnknownMigration", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("steps ran despite unknown version: %v", calls)
+	}
+
+	statuses, err := mg.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := statuses[len(statuses)-1]
+	if len(statuses) != 3 || last.Version != 7 || !last.Unknown || !last.Applied {
+		t.Errorf("statuses = %+v", statuses)
+	}
+}
+
+func TestMigrateUpStopsOnError(t *testing.T) {
+	var calls []string
+	mg := fakeMigrator(&calls, 1, 2, 3)
+	mg.Steps[1].Up = func(context.Context, *mongo.Database) error { return errors.New("boom") }
+
+	done, err := mg.Up(context.Background())
+	if err == nil || !reflect.DeepEqual(versions(done), []int{1}) {
+		t.Fatalf("done=%v err=%v; want [1] and an error", versions(done), err)
+	}
+	statuses, _ := mg.Status(context.Background())
+	if !statuses[0].Applied || statuses[1].Applied || statuses[2].Applied {
+		t.Errorf("statuses = %+v", statuses)
+	}
+}
+
+func TestKnownMigrationsAreOrdered(t *testing.T) {
+	seen := map[int]bool{}
+	prev := 0
+	for _, m := range db.Migrations() {
+		if m.Version <= prev || seen[m.Version] || m.Up == nil || m.Down == nil {
+			t.Errorf("migration %d (%s) out of order, duplicated or incomplete", m.Version, m.Name)
+		}
+		seen[m.Version] = true
+		prev = m.Version
+	}
+}
@@ -0,0 +1,363 @@
+package math
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+)
+
+type openAPISpec struct {
+	Paths map[string]map[string]struct {
+		Parameters []struct {
+			Name string `json:"name"`
+			In   string `json:"in"`
+		} `json:"parameters"`
+	} `json:"paths"`
+}
+
+func fetchSpec(t *testing.T, router *handlers.Router) openAPISpec {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d", rec.Code)
+	}
+	var spec openAPISpec
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	return spec
+}
+
+// ------------------ Кожен маршрут є в специфікації і навпаки ------------------
+func TestOpenAPICoversRoutes(t *testing.T) {
+	router := handlers.NewAPI()
+	spec := fetchSpec(t, router)
+
+	operations := 0
+	for _, ops := range spec.Paths {
+		operations += len(ops)
+	}
+
+	routes := router.Routes()
+	if operations != len(routes) {
+		t.Errorf("spec has %d operations, router has %d routes", operations, len(routes))
+	}
+	for _, route := range routes {
+		path := strings.ReplaceAll(route.Pattern, "{$}", "")
+		if path == "" {
+			path = "/"
+		}
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is not documented", route.Method, path)
+		}
+	}
+}
+
+// ------------------ Query-параметри кожного маршруту задокументовані саме на ньому ------------------
+func TestOpenAPIDocumentsQueryParams(t *testing.T) {
+	router := handlers.NewAPI()
+	used := routeQueryParams(t, filepath.Join("..", "handlers"))
+	if len(used) == 0 {
+		t.Fatal("no routes found in handlers")
+	}
+
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Pattern
+		documented := map[string]bool{}
+		for _, p := range route.Params {
+			documented[p.Name] = true
+		}
+		read, ok := used[key]
+		if !ok {
+			if len(documented) > 0 {
+				t.Errorf("%s documents query parameters, but its registration was not found in the source", key)
+			}
+			continue
+		}
+		for name := range documented {
+			if _, ok := read[name]; !ok {
+				t.Errorf("%s documents query parameter %q that its handler never reads", key, name)
+			}
+		}
+		for name, pos := range read {
+			if !documented[name] {
+				t.Errorf("%s reads query parameter %q (%s), but it is missing from the spec", key, name, pos)
+			}
+		}
+	}
+}
+
+// handlerSource - функції пакета: які query-параметри читає кожна сама
+// і які інші функції пакета вона згадує
+type handlerSource struct {
+	fset   *token.FileSet
+	params map[string]map[string]queryUse
+	refs   map[string][]string
+}
+
+// routeQueryParams знаходить у джерелах реєстрації маршрутів
+// (group.HandleFunc(http.MethodGet, "/x", h, mw...)) і повертає для кожного
+// "METHOD /шлях" query-параметри, які читають обробник, його middleware і
+// все, що вони викликають. Маршрути, чий шлях не складається з літералів
+// (наприклад, restoreRoute), пропускаються.
+func routeQueryParams(t *testing.T, dir string) map[string]map[string]string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := handlerSource{fset: token.NewFileSet(), params: map[string]map[string]queryUse{}, refs: map[string][]string{}}
+	var decls []*ast.FuncDecl
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(src.fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Body != nil {
+				decls = append(decls, fn)
+				src.params[fn.Name.Name] = src.queryGets(fn.Body)
+			}
+		}
+	}
+	for _, fn := range decls {
+		src.refs[fn.Name.Name] = src.funcRefs(fn.Body)
+	}
+
+	routes := map[string]map[string]string{}
+	for _, fn := range decls {
+		src.registrations(fn, routes)
+	}
+	return routes
+}
+
+// group - префікс шляху групи і її middleware
+type group struct {
+	prefix string
+	mws    []ast.Expr
+}
+
+func (src handlerSource) registrations(fn *ast.FuncDecl, routes map[string]map[string]string) {
+	groups := map[string]group{}
+	for _, field := range fn.Type.Params.List {
+		if star, ok := field.Type.(*ast.StarExpr); ok && isIdent(star.X, "Router") {
+			for _, name := range field.Names {
+				groups[name.Name] = group{}
+			}
+		}
+	}
+
+	// Локальні змінні (serve := func(...) {...}) теж можуть бути обробниками
+	locals := map[string]ast.Expr{}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+				if id, ok := n.Lhs[0].(*ast.Ident); ok {
+					locals[id.Name] = n.Rhs[0]
+					if g, ok := resolveGroup(n.Rhs[0], groups); ok {
+						groups[id.Name] = g
+					}
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "HandleFunc" && sel.Sel.Name != "Handle") || len(n.Args) < 3 {
+				return true
+			}
+			g, ok := resolveGroup(sel.X, groups)
+			method, okMethod := httpMethod(n.Args[0])
+			path, okPath := stringLit(n.Args[1])
+			if !ok || !okMethod || !okPath {
+				return true
+			}
+			pattern := g.prefix + path
+			if pattern == "" {
+				pattern = "/"
+			}
+			exprs := append(append([]ast.Expr{}, g.mws...), n.Args[2:]...)
+			for _, expr := range exprs {
+				if id, ok := expr.(*ast.Ident); ok && locals[id.Name] != nil {
+					exprs = append(exprs, locals[id.Name])
+				}
+			}
+			uses := map[string]queryUse{}
+			for _, expr := range exprs {
+				for _, name := range src.funcRefs(expr) {
+					src.collect(name, uses, map[string]bool{})
+				}
+				for name, use := range src.queryGets(expr) {
+					uses[name] = use
+				}
+			}
+			read := map[string]string{}
+			for name, use := range uses {
+				if use.method == "" || use.method == method {
+					read[name] = use.pos
+				}
+			}
+			routes[method+" "+pattern] = read
+		}
+		return true
+	})
+}
+
+// collect додає до read параметри функції name і всього, що вона згадує
+func (src handlerSource) collect(name string, read map[string]queryUse, seen map[string]bool) {
+	if seen[name] {
+		return
+	}
+	seen[name] = true
+	for param, use := range src.params[name] {
+		read[param] = use
+	}
+	for _, ref := range src.refs[name] {
+		src.collect(ref, read, seen)
+	}
+}
+
+// resolveGroup обчислює групу для router, router.Group("/x", mw...) і .Secured(...)
+func resolveGroup(expr ast.Expr, groups map[string]group) (group, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		g, ok := groups[e.Name]
+		return g, ok
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return group{}, false
+		}
+		parent, ok := resolveGroup(sel.X, groups)
+		if !ok {
+			return group{}, false
+		}
+		switch sel.Sel.Name {
+		case "Secured":
+			return parent, true
+		case "Group":
+			prefix, ok := stringLit(e.Args[0])
+			if !ok {
+				return group{}, false
+			}
+			mws := append(append([]ast.Expr{}, parent.mws...), e.Args[1:]...)
+			return group{prefix: parent.prefix + prefix, mws: mws}, true
+		}
+	}
+	return group{}, false
+}
+
+// queryUse - де читається query-параметр; method - якщо читання стоїть
+// під if r.Method == http.MethodX, тобто лише для цього методу
+type queryUse struct {
+	pos    string
+	method string
+}
+
+// queryGets шукає виклики query.Get("x") та r.URL.Query().Get("x")
+func (src handlerSource) queryGets(node ast.Node) map[string]queryUse {
+	found := map[string]queryUse{}
+	var walk func(node ast.Node, method string)
+	walk = func(node ast.Node, method string) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ifStmt, ok := n.(*ast.IfStmt); ok {
+				if m, ok := methodGuard(ifStmt.Cond); ok && ifStmt.Init == nil {
+					walk(ifStmt.Cond, method)
+					walk(ifStmt.Body, m)
+					if ifStmt.Else != nil {
+						walk(ifStmt.Else, method)
+					}
+					return false
+				}
+			}
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Get" || !isQueryValues(sel.X) {
+				return true
+			}
+			if name, ok := stringLit(call.Args[0]); ok {
+				found[name] = queryUse{pos: src.fset.Position(call.Args[0].Pos()).String(), method: method}
+			}
+			return true
+		})
+	}
+	walk(node, "")
+	return found
+}
+
+// methodGuard розпізнає умову r.Method == http.MethodX
+func methodGuard(cond ast.Expr) (string, bool) {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.EQL {
+		return "", false
+	}
+	if sel, ok := bin.X.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Method" {
+		return "", false
+	}
+	if _, ok := bin.Y.(*ast.SelectorExpr); !ok {
+		return "", false
+	}
+	return httpMethod(bin.Y)
+}
+
+// funcRefs - імена функцій пакета, згадані у node
+func (src handlerSource) funcRefs(node ast.Node) []string {
+	var refs []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if _, isFunc := src.params[id.Name]; isFunc {
+				refs = append(refs, id.Name)
+			}
+		}
+		return true
+	})
+	return refs
+}
+
+func httpMethod(expr ast.Expr) (string, bool) {
+	if sel, ok := expr.(*ast.SelectorExpr); ok && isIdent(sel.X, "http") && strings.HasPrefix(sel.Sel.Name, "Method") {
+		return strings.ToUpper(strings.TrimPrefix(sel.Sel.Name, "Method")), true
+	}
+	return stringLit(expr)
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+func isQueryValues(x ast.Expr) bool {
+	switch v := x.(type) {
+	case *ast.Ident:
+		return v.Name == "query"
+	case *ast.CallExpr:
+		sel, ok := v.Fun.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == "Query"
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package math
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-api/handlers"
+	"hospital-api/recurrence"
+)
+
+// ------------------ Розбір RRULE ------------------
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		err  string
+	}{
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16", ""},
+		{"freq=daily;interval=2;until=20250401", ""},
+		{"FREQ=WEEKLY;UNTIL=20250401T090000Z;WKST=SU", ""},
+		{"FREQ=WEEKLY;BYDAY=MO", "must end"},
+		{"FREQ=MONTHLY;COUNT=3", "not supported"},
+		{"FREQ=WEEKLY;BYDAY=1MO;COUNT=3", "BYDAY"},
+		{"FREQ=WEEKLY;COUNT=3;UNTIL=20250401", "together"},
+		{"FREQ=DAILY;COUNT=0", "positive"},
+		{"FREQ=DAILY;COUNT=2;BYHOUR=9", "BYHOUR"},
+		{"COUNT=2", "FREQ is required"},
+		{"FREQ=DAILY;COUNT=2;COUNT=3", "twice"},
+	}
+	for _, tt := range tests {
+		_, err := recurrence.Parse(tt.rule)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("Parse(%q): %v", tt.rule, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("Parse(%q) = %v; want error with %q", tt.rule, err, tt.err)
+		}
+	}
+
+	rule, _ := recurrence.Parse("RRULE:freq=weekly;byday=mo,th;interval=1;count=16")
+	if got := rule.String(); got != "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+// ------------------ Розгортання в дати ------------------
+func TestExpandRRule(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	// Понеділок перед переходом на літній час (30 березня 2025)
+	start := time.Date(2025, time.March, 17, 9, 30, 0, 0, kyiv)
+
+	rule, _ := recurrence.Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16")
+	dates, err := rule.Expand(start, kyiv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 16 {
+		t.Fatalf("%d dates; want 16 (eight weeks)", len(dates))
+	}
+	for i, d := range dates {
+		if wd := d.Weekday(); wd != time.Monday && wd != time.Thursday {
+			t.Errorf("date %d on %s", i, wd)
+		}
+		if d.Hour() != 9 || d.Minute() != 30 {
+			t.Errorf("date %d at %s; want 09:30 local time", i, d.Format("15:04 MST"))
+		}
+	}
+	if last := dates[15]; last.Format(time.DateOnly) != "2025-05-08" {
+		t.Errorf("last date %s; want 2025-05-08", last.Format(time.DateOnly))
+	}
+
+	tests := []struct {
+		rule  string
+		dates []string
+	}{
+		{"FREQ=DAILY;INTERVAL=3;COUNT=3", []string{"2025-03-17", "2025-03-20", "2025-03-23"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;UNTIL=20250415", []string{"2025-03-18", "2025-04-01", "2025-04-15"}},
+		// start сам не підходить під BYDAY - перша дата пізніше
+		{"FREQ=WEEKLY;BYDAY=WE;COUNT=2", []string{"2025-03-19", "2025-03-26"}},
+		{"FREQ=DAILY;BYDAY=SA,SU;COUNT=2", []string{"2025-03-22", "2025-03-23"}},
+		// Правило, що ніколи не збігається, не зациклюється
+		{"FREQ=DAILY;INTERVAL=7;BYDAY=TU;COUNT=3", nil},
+	}
+	for _, tt := range tests {
+		rule, err := recurrence.Parse(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dates, err := rule.Expand(start, kyiv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, d := range dates {
+			got = append(got, d.Format(time.DateOnly))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.dates, " ") {
+			t.Errorf("%s: %v; want %v", tt.rule, got, tt.dates)
+		}
+	}
+
+	forever, _ := recurrence.Parse("FREQ=DAILY;UNTIL=20991231")
+	if _, err := forever.Expand(start, kyiv); err != recurrence.ErrTooManyOccurrences {
+		t.Errorf("long rule: %v", err)
+	}
+}
+
+// ExpandFrom відраховує правило від start, але повертає лише дати від from
+func TestExpandRRuleFrom(t *testing.T) {
+	start := time.Date(2019, time.January, 7, 9, 0, 0, 0, time.UTC) // понеділок
+	from := time.Date(2025, time.March, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule  string
+		dates []string
+	}{
+		// Вирівнювання INTERVAL лишається від start
+		{"FREQ=WEEKLY;INTERVAL=2;UNTIL=20250415", []string{"2025-03-24", "2025-04-07"}},
+		// COUNT рахує й пропущені дати: ці 2 повтори були давно
+		{"FREQ=WEEKLY;COUNT=2", nil},
+		// UNTIL з датою включає весь день
+		{"FREQ=DAILY;UNTIL=20250320", []string{"2025-03-18", "2025-03-19", "2025-03-20"}},
+	}
+	for _, tt := range tests {
+		rule, err := recurrence.Parse(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dates, err := rule.ExpandFrom(start, from, time.UTC)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.rule, err)
+		}
+		var got []string
+		for _, d := range dates {
+			got = append(got, d.Format(time.DateOnly))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.dates, " ") {
+			t.Errorf("%s: %v; want %v", tt.rule, got, tt.dates)
+		}
+	}
+}
+
+// ------------------ Розділення серії ------------------
+func TestSplitRRule(t *testing.T) {
+	start := time.Date(2025, time.March, 17, 9, 0, 0, 0, time.UTC)
+	rule, _ := recurrence.Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16")
+	dates, _ := rule.Expand(start, time.UTC)
+	pivot := dates[5]
+
+	head, _ := rule.EndBefore(pivot).Expand(start, time.UTC)
+	tail, _ := rule.Skip(5).Expand(pivot, time.UTC)
+	if len(head) != 5 || len(tail) != 11 {
+		t.Fatalf("split into %d + %d; want 5 + 11", len(head), len(tail))
+	}
+	if !tail[0].Equal(pivot) || !tail[10].Equal(dates[15]) {
+		t.Errorf("tail %v..%v; want %v..%v", tail[0], tail[10], pivot, dates[15])
+	}
+}
+
+// ------------------ Маршрути серій перевіряють запит до БД ------------------
+func TestSeriesRoutes(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+	admin := loginToken(t, router, "admin", "admin123")
+
+	send := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	occurrence := "/appointment-series/507f1f77bcf86cd799439011/occurrences/507f1f77bcf86cd799439012"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		code   int
+		want   string
+	}{
+		{"reader creates", http.MethodPost, "/appointment-series", reader, `{}`, http.StatusForbidden, ""},
+		{"bad rrule", http.MethodPost, "/appointment-series", admin,
+			`{"patientId":"507f1f77bcf86cd799439011","doctorId":"507f1f77bcf86cd799439012","start":"2025-03-17T09:00:00Z","rrule":"FREQ=WEEKLY"}`,
+			http.StatusBadRequest, "rrule"},
+		{"empty series", http.MethodPost, "/appointment-series", admin, `{}`, http.StatusBadRequest, "patientId"},
+		{"reader reschedules", http.MethodPut, occurrence, reader, `{}`, http.StatusForbidden, ""},
+		{"bad scope", http.MethodPut, occurrence + "?scope=all", admin, `{"date":"2025-03-18T09:00:00Z"}`, http.StatusBadRequest, "scope"},
+		{"nothing to change", http.MethodPut, occurrence, admin, `{}`, http.StatusBadRequest, "date"},
+		{"bad appointment id", http.MethodPut, "/appointment-series/507f1f77bcf86cd799439011/occurrences/nope", admin, `{}`, http.StatusBadRequest, ""},
+		{"cancel without reason", http.MethodPost, occurrence + "/cancel?scope=following", admin, `{}`, http.StatusBadRequest, "reason"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := send(tt.method, tt.path, tt.token, tt.body)
+			if rec.Code != tt.code || !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("%s %s = %d %q; want %d with %q", tt.method, tt.path, rec.Code, rec.Body.String(), tt.code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package math
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"hospital-api/handlers"
+	"hospital-api/i18n"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ------------------ Діапазон дат для звітів ------------------
+func TestReportDateRange(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	moment := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     bson.M
+		badParam string
+	}{
+		{"no bounds", "", "", bson.M{}, ""},
+		{"from date", "2025-03-10", "", bson.M{"date": bson.M{"$gte": day}}, ""},
+		{"to date includes the whole day", "", "2025-03-10", bson.M{"date": bson.M{"$lt": day.Add(24 * time.Hour)}}, ""},
+		{"to timestamp is inclusive", "", "2025-03-10T14:30:00Z", bson.M{"date": bson.M{"$lte": moment}}, ""},
+		{"both bounds", "2025-03-10", "2025-03-10T14:30:00Z", bson.M{"date": bson.M{"$gte": day, "$lte": moment}}, ""},
+		{"bad from", "10.03.2025", "", nil, "from"},
+		{"bad to", "2025-03-10", "2025-13-01", nil, "to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := handlers.DateRange(tt.from, tt.to)
+			if tt.badParam != "" {
+				var dateErr *handlers.ReportDateError
+				if !errors.As(err, &dateErr) || dateErr.Param != tt.badParam {
+					t.Fatalf("error = %v; want ReportDateError for %s", err, tt.badParam)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DateRange = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Помилки параметрів звітів локалізовані й не доходять до бази
+func TestReportBadParams(t *testing.T) {
+	router := handlers.NewAPI()
+	token := loginToken(t, router, "reader", "reader123")
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"from=yesterday", i18n.T(i18n.Ukrainian, "report.date", "from")},
+		{"from=2025-03-01&to=03/10", i18n.T(i18n.Ukrainian, "report.date", "to")},
+		{"interval=month", i18n.T(i18n.Ukrainian, "report.interval")},
+		{"format=xlsx", i18n.T(i18n.Ukrainian, "report.format")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/reports/appointments?"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Accept-Language", "uk")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d; want 400: %s", rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("body = %q; want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
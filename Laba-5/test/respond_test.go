@@ -0,0 +1,133 @@
+package math
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hospital-api/handlers"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func loginWithAccept(router http.Handler, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"reader","password":"reader123"}`))
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestContentNegotiation(t *testing.T) {
+	router := handlers.NewAPI()
+
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+		bodyPrefix  string
+	}{
+		{"default json", "", http.StatusOK, "application/json; charset=utf-8", `{"token":`},
+		{"wildcard", "*/*", http.StatusOK, "application/json; charset=utf-8", `{"token":`},
+		{"xml", "application/xml", http.StatusOK, "application/xml; charset=utf-8", `<?xml version="1.0" encoding="UTF-8"?>` + "\n<response><token>"},
+		{"msgpack", "application/msgpack", http.StatusOK, "application/msgpack", "\x81\xa5token"},
+		{"q values", "application/xml;q=0.5, application/json", http.StatusOK, "application/json; charset=utf-8", `{"token":`},
+		{"type wildcard", "application/*;q=0.9, text/csv;q=0.1", http.StatusOK, "application/json; charset=utf-8", `{"token":`},
+		// Мапу без структури в CSV не покласти
+		{"csv not representable", "text/csv", http.StatusNotAcceptable, "", ""},
+		// Непідтримуваний тип відсікається ще до обробника
+		{"unsupported", "image/png", http.StatusNotAcceptable, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := loginWithAccept(router, tt.accept)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.contentType)
+			}
+			if !strings.HasPrefix(rec.Body.String(), tt.bodyPrefix) {
+				t.Errorf("body = %q, want prefix %q", rec.Body.String(), tt.bodyPrefix)
+			}
+		})
+	}
+}
+
+func TestResponseCompression(t *testing.T) {
+	router := handlers.NewAPI()
+
+	decoders := map[string]func([]byte) ([]byte, error){
+		"gzip": func(b []byte) ([]byte, error) {
+			zr, err := gzip.NewReader(bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(zr)
+		},
+		"zstd": func(b []byte) ([]byte, error) {
+			zr, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			defer zr.Close()
+			return zr.DecodeAll(b, nil)
+		},
+	}
+
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"gzip", "gzip"},
+		{"gzip, zstd", "zstd"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"br", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+			body := rec.Body.Bytes()
+			if decode := decoders[tt.want]; decode != nil {
+				var err error
+				if body, err = decode(body); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var spec map[string]interface{}
+			if err := json.Unmarshal(body, &spec); err != nil || spec["openapi"] == nil {
+				t.Errorf("body is not the OpenAPI spec: %v", err)
+			}
+		})
+	}
+}
+
+func TestSmallResponsesAreNotCompressed(t *testing.T) {
+	router := handlers.NewAPI()
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"reader","password":"reader123"}`))
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q for a small response", got)
+	}
+}
@@ -0,0 +1,181 @@
+package math
+
+import (
+	"testing"
+	"time"
+
+	"hospital-api/i18n"
+	"hospital-api/models"
+	"hospital-api/scheduling"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	rosterDepartment = primitive.NewObjectID()
+	dayShift         = models.Shift{ID: primitive.NewObjectID(), Name: "day", DepartmentID: rosterDepartment, Start: "08:00", End: "20:00", Coverage: map[string]int{"nurse": 1}}
+	nightShift       = models.Shift{ID: primitive.NewObjectID(), Name: "night", DepartmentID: rosterDepartment, Start: "20:00", End: "08:00", Coverage: map[string]int{"nurse": 1}}
+	monday           = time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+)
+
+func nurse(name, shift string) models.Staff {
+	return models.Staff{ID: primitive.NewObjectID(), Name: name, Role: "nurse", Shift: shift, DepartmentID: rosterDepartment}
+}
+
+func entryOn(t *testing.T, shift models.Shift, staff models.Staff, day int) models.RosterEntry {
+	t.Helper()
+	e, err := scheduling.DefaultRules.Entry(shift, staff, monday.AddDate(0, 0, day))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// ------------------ Нічна зміна закінчується наступного дня ------------------
+func TestShiftWindow(t *testing.T) {
+	start, end, err := nightShift.Window(monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := monday.Add(20 * time.Hour); !start.Equal(want) {
+		t.Errorf("start = %v; want %v", start, want)
+	}
+	if want := monday.Add(32 * time.Hour); !end.Equal(want) {
+		t.Errorf("end = %v; want %v", end, want)
+	}
+}
+
+// ------------------ Табличні тести норм графіка ------------------
+func TestCheckAssignment(t *testing.T) {
+	anna := nurse("Анна", "")
+
+	tests := []struct {
+		name     string
+		existing []models.RosterEntry
+		entry    models.RosterEntry
+		rule     string
+	}{
+		{"enough rest", []models.RosterEntry{entryOn(t, dayShift, anna, 0)}, entryOn(t, dayShift, anna, 1), ""},
+		{"day after night", []models.RosterEntry{entryOn(t, nightShift, anna, 0)}, entryOn(t, dayShift, anna, 1), scheduling.RuleMinRest},
+		{"overlap", []models.RosterEntry{entryOn(t, dayShift, anna, 0)}, entryOn(t, nightShift, anna, 0), scheduling.RuleMinRest},
+		{"fifth twelve-hour shift", []models.RosterEntry{
+			entryOn(t, dayShift, anna, 0), entryOn(t, dayShift, anna, 1),
+			entryOn(t, dayShift, anna, 2), entryOn(t, dayShift, anna, 3),
+		}, entryOn(t, dayShift, anna, 4), scheduling.RuleMaxWeeklyHours},
+		{"next week does not count", []models.RosterEntry{
+			entryOn(t, dayShift, anna, 0), entryOn(t, dayShift, anna, 1),
+			entryOn(t, dayShift, anna, 2), entryOn(t, dayShift, anna, 3),
+		}, entryOn(t, dayShift, anna, 7), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := scheduling.CheckAssignment(scheduling.DefaultRules, tt.entry, tt.existing)
+			if tt.rule == "" {
+				if len(violations) != 0 {
+					t.Errorf("unexpected violations: %+v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || violations[0].Rule != tt.rule {
+				t.Errorf("violations = %+v; want one %s", violations, tt.rule)
+			}
+		})
+	}
+}
+
+func TestCheckCoverage(t *testing.T) {
+	anna := nurse("Анна", "")
+	shifts := []models.Shift{dayShift}
+	entries := []models.RosterEntry{entryOn(t, dayShift, anna, 0)}
+
+	violations := scheduling.Check(scheduling.DefaultRules, shifts, entries, monday, monday.AddDate(0, 0, 2))
+	if len(violations) != 1 {
+		t.Fatalf("violations = %+v; want one", violations)
+	}
+	if v := violations[0]; v.Rule != scheduling.RuleCoverage || v.Date != "2025-03-04" || *v.ShiftID != dayShift.ID {
+		t.Errorf("violation = %+v; want day shift uncovered on 2025-03-04", v)
+	}
+
+	// Повідомлення перекладається за кодом, решта полів не змінюється
+	uk := scheduling.Localize(violations, i18n.Ukrainian)[0]
+	if want := i18n.T(i18n.Ukrainian, "roster.coverage", violations[0].Args...); uk.Code != "roster.coverage" || uk.Message != want || uk.Message == violations[0].Message {
+		t.Errorf("localized = %q; want %q", uk.Message, want)
+	}
+}
+
+// ------------------ Генератор: повне покриття без порушень і рівне навантаження ------------------
+func TestGenerateFairRoster(t *testing.T) {
+	staff := []models.Staff{nurse("Анна", "night"), nurse("Богдан", ""), nurse("Віра", ""), nurse("Галина", "day")}
+	shifts := []models.Shift{dayShift, nightShift}
+
+	entries, violations := scheduling.Generate(scheduling.DefaultRules, shifts, staff, nil, monday.AddDate(0, 0, 2))
+	if len(violations) != 0 {
+		t.Fatalf("unexpected violations: %+v", violations)
+	}
+	if len(entries) != 14 {
+		t.Fatalf("len(entries) = %d; want 14", len(entries))
+	}
+	if entries[0].Date != "2025-03-03" {
+		t.Errorf("first date = %s; want the Monday", entries[0].Date)
+	}
+
+	hours := map[primitive.ObjectID]float64{}
+	for _, e := range entries {
+		hours[e.StaffID] += e.Hours()
+	}
+	for _, s := range staff {
+		if h := hours[s.ID]; h < 36 || h > 48 {
+			t.Errorf("%s works %.0f hours; want a fair share between 36 and 48", s.Name, h)
+		}
+	}
+	if entries[1].StaffID != staff[0].ID {
+		t.Errorf("night shift on Monday should go to the nurse who prefers nights")
+	}
+}
+
+func TestGenerateReportsShortage(t *testing.T) {
+	staff := []models.Staff{nurse("Анна", "")}
+
+	entries, violations := scheduling.Generate(scheduling.DefaultRules, []models.Shift{dayShift, nightShift}, staff, nil, monday)
+	if len(entries) == 0 {
+		t.Fatal("expected the single nurse to be scheduled")
+	}
+	for _, v := range violations {
+		if v.Rule != scheduling.RuleCoverage {
+			t.Errorf("generator produced a %s violation: %+v", v.Rule, v)
+		}
+	}
+	if len(violations) == 0 {
+		t.Error("expected uncovered shifts to be reported")
+	}
+}
+
+// Зміна з невалідним часом не зупиняє генератор: про неї є порушення,
+// а інші зміни заповнені
+func TestGenerateSkipsBrokenShift(t *testing.T) {
+	broken := models.Shift{ID: primitive.NewObjectID(), Name: "broken", DepartmentID: rosterDepartment, Start: "25:00", End: "08:00", Coverage: map[string]int{"nurse": 1}}
+	staff := []models.Staff{nurse("Анна", ""), nurse("Богдан", "")}
+
+	entries, violations := scheduling.Generate(scheduling.DefaultRules, []models.Shift{broken, dayShift}, staff, nil, monday)
+	if len(entries) != 7 {
+		t.Errorf("len(entries) = %d; want the day shift covered all week", len(entries))
+	}
+	for _, e := range entries {
+		if e.ShiftID != dayShift.ID {
+			t.Errorf("entry on shift %s; want only the day shift", e.ShiftID.Hex())
+		}
+	}
+	var shiftTime int
+	for _, v := range violations {
+		if v.Rule == scheduling.RuleShiftTime {
+			shiftTime++
+			if *v.ShiftID != broken.ID || v.Message != i18n.T(i18n.Default, "roster.shift_invalid", "broken") {
+				t.Errorf("violation = %+v", v)
+			}
+		}
+	}
+	if shiftTime != 1 {
+		t.Errorf("%d shift_time violations; want exactly 1: %+v", shiftTime, violations)
+	}
+}
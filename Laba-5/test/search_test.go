@@ -0,0 +1,149 @@
+package math
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"hospital-api/handlers"
+	"hospital-api/search"
+)
+
+// ------------------ Нормалізація ------------------
+func TestSearchTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Мар’яна ҐОНТА", []string{"маряна", "гонта"}},
+		{"Йосип Їжак", []string{"иосип", "іжак"}},
+		{"Євген, кардіолог", []string{"евген", "кардіолог"}},
+		{"Café Crème 500mg", []string{"cafe", "creme", "500mg"}},
+	}
+	for _, tt := range tests {
+		if got := search.Tokens(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokens(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{"шевченко", "шевченко", 2, 0},
+		{"шевчнеко", "шевченко", 2, 1}, // перестановка
+		{"шевченк", "шевченко", 2, 1},
+		{"коваль", "кравець", 1, 2}, // більше за max - max+1
+	}
+	for _, tt := range tests {
+		if got := search.Distance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("Distance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
+
+// ------------------ Індекс ------------------
+func searchFixture() *search.Index {
+	ix := search.NewIndex()
+	ix.Load([]search.Document{
+		{Resource: "doctors", ID: "d1", Title: "Тарас Шевченко", Fields: []search.Field{{Text: "Тарас Шевченко", Weight: 3}, {Text: "кардіолог", Weight: 1.5}}},
+		{Resource: "doctors", ID: "d2", Title: "Олена Коваль", Fields: []search.Field{{Text: "Олена Коваль", Weight: 3}, {Text: "невролог", Weight: 1.5}}},
+		{Resource: "staff", ID: "s1", Title: "Ганна Кардіолог", Fields: []search.Field{{Text: "Ганна Кардіолог", Weight: 3}, {Text: "медсестра", Weight: 1.5}}},
+		{Resource: "medications", ID: "m1", Title: "Paracetamol", Fields: []search.Field{{Text: "Paracetamol", Weight: 3}, {Text: "Дарниця", Weight: 1}}},
+		{Resource: "departments", ID: "p1", Title: "Кардіологія", Fields: []search.Field{{Text: "Кардіологія", Weight: 3}}},
+	})
+	return ix
+}
+
+func hitIDs(hits []search.Hit) []string {
+	ids := []string{}
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestSearchIndex(t *testing.T) {
+	ix := searchFixture()
+
+	tests := []struct {
+		name  string
+		query string
+		opts  search.Options
+		want  []string
+	}{
+		{"exact", "Шевченко", search.Options{}, []string{"d1"}},
+		{"case and diacritics", "ШЕВЧЕНКО тарас", search.Options{}, []string{"d1"}},
+		{"typo", "Шевчнеко", search.Options{}, []string{"d1"}},
+		{"latin finds cyrillic", "Shevchenko", search.Options{}, []string{"d1"}},
+		{"cyrillic finds latin", "парацетамол", search.Options{}, []string{"m1"}},
+		{"prefix", "Шевч", search.Options{}, []string{"d1"}},
+		{"latin prefix", "parac", search.Options{}, []string{"m1"}},
+		{"all words must match", "Олена Шевченко", search.Options{}, []string{}},
+		// Точний збіг в імені, потім префікс в імені, потім спеціальність
+		{"ranking", "кардіолог", search.Options{}, []string{"s1", "p1", "d1"}},
+		{"resource filter", "кардіолог", search.Options{Resources: []string{"doctors"}}, []string{"d1"}},
+		{"limit", "кардіолог", search.Options{Limit: 1}, []string{"s1"}},
+		{"regex metacharacters are plain text", ".*", search.Options{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hitIDs(ix.Search(tt.query, tt.opts)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// ------------------ GET /search ------------------
+func TestSearchEndpoint(t *testing.T) {
+	router := handlers.NewAPI()
+	token := loginToken(t, router, "reader", "reader123")
+	handlers.Search.Load([]search.Document{
+		{Resource: "doctors", ID: "d1", Title: "Тарас Шевченко", Fields: []search.Field{{Text: "Тарас Шевченко", Weight: 3}}},
+	})
+	defer handlers.Search.Load(nil)
+
+	tests := []struct {
+		name   string
+		url    string
+		token  string
+		status int
+	}{
+		{"no token", "/search?q=taras", "", http.StatusUnauthorized},
+		{"empty query", "/search?q=", token, http.StatusBadRequest},
+		{"unknown type", "/search?q=taras&type=patients", token, http.StatusBadRequest},
+		{"bad limit", "/search?q=taras&limit=1000", token, http.StatusBadRequest},
+		{"ok", "/search?q=taras&type=doctors,staff", token, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Hits []search.Hit `json:"hits"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Hits) != 1 || resp.Hits[0].ID != "d1" {
+				t.Errorf("hits = %+v, want d1", resp.Hits)
+			}
+		})
+	}
+}
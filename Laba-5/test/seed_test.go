@@ -0,0 +1,102 @@
+package math
+
+import (
+	"strings"
+	"testing"
+
+	"hospital-api/seed"
+)
+
+const seedJSON = `{
+  "hospitals": [{"ref": "city", "name": "City Hospital", "beds": 120}],
+  "departments": [{"ref": "cardio", "name": "Cardiology", "hospitalId": "@city", "floor": 2}],
+  "doctors": [{"ref": "house", "name": "Dr. House", "department": "@cardio", "experienceYears": 20}]
+}`
+
+const seedYAML = `
+hospitals:
+  - ref: city
+    name: City Hospital
+    beds: 120
+departments:
+  - name: Cardiology
+    hospitalId: "@city"
+`
+
+// ------------------ Фікстури: посилання і строгий розбір ------------------
+func TestSeedResolve(t *testing.T) {
+	ds, err := mustParse(t, seedJSON, "json").Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Hospitals) != 1 || len(ds.Departments) != 1 || len(ds.Doctors) != 1 {
+		t.Fatalf("dataset = %+v", ds)
+	}
+	if ds.Hospitals[0].ID != ds.Refs["city"] || ds.Hospitals[0].ID.IsZero() {
+		t.Errorf("hospital id = %s; want ref city %s", ds.Hospitals[0].ID.Hex(), ds.Refs["city"].Hex())
+	}
+	if ds.Departments[0].HospitalID != ds.Refs["city"] {
+		t.Errorf("department hospitalId = %s; want %s", ds.Departments[0].HospitalID.Hex(), ds.Refs["city"].Hex())
+	}
+	if ds.Doctors[0].Department != ds.Refs["cardio"] {
+		t.Errorf("doctor department = %s; want %s", ds.Doctors[0].Department.Hex(), ds.Refs["cardio"].Hex())
+	}
+}
+
+func TestSeedResolveYAML(t *testing.T) {
+	ds, err := mustParse(t, seedYAML, "yaml").Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Departments) != 1 || ds.Departments[0].HospitalID != ds.Refs["city"] {
+		t.Errorf("departments = %+v; refs = %v", ds.Departments, ds.Refs)
+	}
+	if ds.Hospitals[0].Beds != 120 {
+		t.Errorf("beds = %d; want 120", ds.Hospitals[0].Beds)
+	}
+}
+
+func TestSeedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		wantErr string
+	}{
+		{"duplicate ref", `{"hospitals":[{"ref":"a","name":"A"}],"doctors":[{"ref":"a","name":"B"}]}`, "json", `doctors[0]: duplicate ref "a"`},
+		{"unknown ref", `{"departments":[{"name":"X","hospitalId":"@nope"}]}`, "json", `departments[0]: hospitalId: unknown reference "@nope"`},
+		{"bson field name", `{"hospitals":[{"ref":"a","name":"A"}],"departments":[{"name":"X","hospital_id":"@a"}]}`, "json", `unknown field "hospital_id"`},
+		{"yaml bson field name", "doctors:\n  - name: X\n    experience_years: 3\n", "yaml", `unknown field "experience_years"`},
+		{"invalid record", `{"medications":[{"name":"","stock":-1}]}`, "json", "medications[0]: name:"},
+		{"non-string ref", `{"hospitals":[{"ref":1,"name":"A"}]}`, "json", "ref must be a non-empty string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mustParse(t, tt.data, tt.format).Resolve()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve error = %v; want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSeedUnknownSection(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		data := `{"patients": []}`
+		if format == "yaml" {
+			data = "patients: []\n"
+		}
+		if _, err := seed.Parse([]byte(data), format); err == nil {
+			t.Errorf("%s: unknown section accepted", format)
+		}
+	}
+}
+
+func mustParse(t *testing.T, data, format string) *seed.Fixtures {
+	t.Helper()
+	fixtures, err := seed.Parse([]byte(data), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fixtures
+}
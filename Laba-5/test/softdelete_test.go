@@ -0,0 +1,65 @@
+package math
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hospital-api/handlers"
+)
+
+// ------------------ Видалені записи бачить лише адміністратор ------------------
+func TestIncludeDeletedRequiresAdmin(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+
+	tests := []struct {
+		name   string
+		path   string
+		apiKey bool
+		token  string
+	}{
+		{"api key only", "/hospitals?includeDeleted=true", true, ""},
+		{"api key and reader token", "/doctors?includeDeleted=true", true, reader},
+		{"open resource without token", "/medications?includeDeleted=true", false, ""},
+		{"reader on jwt resource", "/staff?includeDeleted=true", false, reader},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey {
+				req.Header.Set("X-API-KEY", "my-secret-key")
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("GET %s = %d; want 403", tt.path, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRestoreRequiresAdminToken(t *testing.T) {
+	router := handlers.NewAPI()
+	reader := loginToken(t, router, "reader", "reader123")
+
+	for _, path := range []string{"/hospitals", "/departments", "/doctors", "/staff", "/medications", "/appointments", "/wards", "/beds", "/shifts"} {
+		req := httptest.NewRequest(http.MethodPost, path+"/507f1f77bcf86cd799439011/restore", nil)
+		req.Header.Set("X-API-KEY", "my-secret-key")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("POST %s/{id}/restore without token = %d; want 401", path, rec.Code)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+reader)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("POST %s/{id}/restore as reader = %d; want 403", path, rec.Code)
+		}
+	}
+}
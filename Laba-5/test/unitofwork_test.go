@@ -0,0 +1,137 @@
+package math
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"hospital-api/db"
+)
+
+// Без db.Client RunInTransaction працює в режимі компенсації - саме його
+// й перевіряємо: кроки до збою мають бути скасовані у зворотному порядку.
+
+// inventory - склад у пам'яті, на якому імітуємо видачу ліків
+type inventory struct {
+	stock  int
+	issued []string
+	log    []string
+}
+
+func (inv *inventory) dispense(u *db.UnitOfWork, patient string, qty int, failAt string) error {
+	err := u.Step(func(context.Context) error {
+		if failAt == "stock" {
+			return errors.New("stock update failed")
+		}
+		inv.stock -= qty
+		inv.log = append(inv.log, "stock")
+		return nil
+	}, func(context.Context) error {
+		inv.stock += qty
+		inv.log = append(inv.log, "undo stock")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = u.Step(func(context.Context) error {
+		inv.issued = append(inv.issued, patient)
+		inv.log = append(inv.log, "issue")
+		return nil
+	}, func(context.Context) error {
+		inv.issued = inv.issued[:len(inv.issued)-1]
+		inv.log = append(inv.log, "undo issue")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.Step(func(context.Context) error {
+		if failAt == "audit" {
+			return errors.New("audit insert failed")
+		}
+		inv.log = append(inv.log, "audit")
+		return nil
+	}, nil)
+}
+
+func TestUnitOfWorkCommits(t *testing.T) {
+	inv := &inventory{stock: 10}
+	err := db.RunInTransaction(context.Background(), func(u *db.UnitOfWork) error {
+		if u.Transactional() {
+			t.Error("without a client the work must not be transactional")
+		}
+		return inv.dispense(u, "p1", 3, "")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.stock != 7 || len(inv.issued) != 1 {
+		t.Errorf("stock = %d, issued = %v; want 7 and [p1]", inv.stock, inv.issued)
+	}
+	if got := strings.Join(inv.log, ","); got != "stock,issue,audit" {
+		t.Errorf("log = %s", got)
+	}
+}
+
+func TestUnitOfWorkRollsBackPartialWork(t *testing.T) {
+	inv := &inventory{stock: 10}
+	err := db.RunInTransaction(context.Background(), func(u *db.UnitOfWork) error {
+		return inv.dispense(u, "p1", 3, "audit")
+	})
+	if err == nil || err.Error() != "audit insert failed" {
+		t.Fatalf("err = %v, want the failing step's error", err)
+	}
+	if inv.stock != 10 || len(inv.issued) != 0 {
+		t.Errorf("stock = %d, issued = %v; want everything undone", inv.stock, inv.issued)
+	}
+	if got := strings.Join(inv.log, ","); got != "stock,issue,undo issue,undo stock" {
+		t.Errorf("log = %s, want undo in reverse order", got)
+	}
+}
+
+func TestUnitOfWorkFailedStepIsNotUndone(t *testing.T) {
+	inv := &inventory{stock: 10}
+	db.RunInTransaction(context.Background(), func(u *db.UnitOfWork) error {
+		return inv.dispense(u, "p1", 3, "stock")
+	})
+	if inv.stock != 10 || len(inv.log) != 0 {
+		t.Errorf("stock = %d, log = %v; the failed step must not be compensated", inv.stock, inv.log)
+	}
+}
+
+func TestUnitOfWorkKeepsSentinelErrors(t *testing.T) {
+	errConflict := errors.New("conflict")
+	undoErr := errors.New("undo failed")
+
+	err := db.RunInTransaction(context.Background(), func(u *db.UnitOfWork) error {
+		u.Step(func(context.Context) error { return nil }, func(context.Context) error { return undoErr })
+		return errConflict
+	})
+	if !errors.Is(err, errConflict) {
+		t.Errorf("err = %v, want errors.Is(err, errConflict)", err)
+	}
+	if !strings.Contains(err.Error(), "undo failed") {
+		t.Errorf("err = %v, want the rollback failure reported", err)
+	}
+}
+
+func TestUnitOfWorkUndoIgnoresCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var undoErr error
+
+	db.RunInTransaction(ctx, func(u *db.UnitOfWork) error {
+		u.Step(func(context.Context) error { return nil }, func(ctx context.Context) error {
+			undoErr = ctx.Err()
+			return nil
+		})
+		cancel()
+		return context.Canceled
+	})
+	if undoErr != nil {
+		t.Errorf("undo ran with a cancelled context: %v", undoErr)
+	}
+}
@@ -0,0 +1,239 @@
+package math
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hospital-api/events"
+	"hospital-api/models"
+	"hospital-api/webhooks"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore - Store у пам'яті замість Mongo
+type memoryStore struct {
+	mu          sync.Mutex
+	hooks       []models.Webhook
+	deadLetters []models.DeadLetter
+}
+
+func (s *memoryStore) Subscriptions(_ context.Context, eventType string) ([]models.Webhook, error) {
+	var out []models.Webhook
+	for _, h := range s.hooks {
+		if h.Active && webhooks.Matches(h.Events, eventType) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryStore) SaveDeadLetter(_ context.Context, letter models.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = append(s.deadLetters, letter)
+	return nil
+}
+
+// receiver - httptest-отримувач, що перевіряє підпис і відмовляє перші failures разів
+type receiver struct {
+	t        *testing.T
+	secret   string
+	failures int32
+	calls    atomic.Int32
+	mu       sync.Mutex
+	payloads []webhooks.Payload
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	err := webhooks.Verify(rc.secret, r.Header.Get(webhooks.HeaderTimestamp), r.Header.Get(webhooks.HeaderSignature), body, time.Minute)
+	if err != nil {
+		rc.t.Errorf("bad delivery: %v", err)
+	}
+	if n := rc.calls.Add(1); n <= rc.failures || rc.failures < 0 {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+		return
+	}
+	var p webhooks.Payload
+	json.Unmarshal(body, &p)
+	rc.mu.Lock()
+	rc.payloads = append(rc.payloads, p)
+	rc.mu.Unlock()
+}
+
+func newDispatcher(store webhooks.Store) *webhooks.Dispatcher {
+	d := webhooks.NewDispatcher(store, 2)
+	d.MaxAttempts = 3
+	d.BaseDelay = time.Millisecond
+	d.MaxDelay = 5 * time.Millisecond
+	return d
+}
+
+// ------------------ Підпис і його перевірка ------------------
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"type":"appointments.created"}`)
+	now := time.Now().Unix()
+	sig := webhooks.Sign("s3cret", now, body)
+	ts := strconv.FormatInt(now, 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		want      error
+	}{
+		{"valid", "s3cret", ts, body, nil},
+		{"wrong secret", "other", ts, body, webhooks.ErrBadSignature},
+		{"tampered body", "s3cret", ts, []byte(`{"type":"medications.deleted"}`), webhooks.ErrBadSignature},
+		{"stale timestamp", "s3cret", strconv.FormatInt(now-3600, 10), body, webhooks.ErrStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := webhooks.Verify(tt.secret, tt.timestamp, sig, tt.body, 5*time.Minute); err != tt.want {
+				t.Errorf("Verify = %v; want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	d := webhooks.NewDispatcher(&memoryStore{}, 1)
+	d.BaseDelay, d.MaxDelay = time.Second, 10*time.Second
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, w := range want {
+		if got := d.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v; want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestWebhookPatterns(t *testing.T) {
+	if !webhooks.Matches([]string{"appointments.*"}, "appointments.created") {
+		t.Error("appointments.* should match appointments.created")
+	}
+	if !webhooks.Matches([]string{"*"}, "medications.updated") {
+		t.Error("* should match everything")
+	}
+	if webhooks.Matches([]string{"medications.updated"}, "medications.deleted") {
+		t.Error("medications.updated should not match medications.deleted")
+	}
+}
+
+// ------------------ Доставка з повторами ------------------
+func TestWebhookRetriesUntilDelivered(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret", failures: 2}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	store := &memoryStore{hooks: []models.Webhook{
+		{ID: primitive.NewObjectID(), URL: server.URL, Secret: "s3cret", Events: []string{"appointments.created"}, Active: true},
+		{ID: primitive.NewObjectID(), URL: server.URL, Secret: "s3cret", Events: []string{"medications.*"}, Active: true},
+	}}
+	d := newDispatcher(store)
+
+	id := primitive.NewObjectID().Hex()
+	if err := d.Dispatch(context.Background(), events.Event{Type: events.Created, Resource: "appointments", ResourceID: id}); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	if n := rc.calls.Load(); n != 3 {
+		t.Errorf("receiver called %d times; want 3", n)
+	}
+	if len(rc.payloads) != 1 || rc.payloads[0].Type != "appointments.created" || rc.payloads[0].ResourceID != id {
+		t.Errorf("payloads = %+v", rc.payloads)
+	}
+	if len(store.deadLetters) != 0 {
+		t.Errorf("unexpected dead letters: %+v", store.deadLetters)
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret", failures: -1}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	hook := models.Webhook{ID: primitive.NewObjectID(), URL: server.URL, Secret: "s3cret", Events: []string{"*"}, Active: true}
+	store := &memoryStore{hooks: []models.Webhook{hook}}
+	d := newDispatcher(store)
+
+	d.Dispatch(context.Background(), events.Event{Type: events.Updated, Resource: "medications", ResourceID: "m1"})
+	d.Close()
+
+	if n := rc.calls.Load(); n != 3 {
+		t.Errorf("receiver called %d times; want MaxAttempts = 3", n)
+	}
+	if len(store.deadLetters) != 1 {
+		t.Fatalf("dead letters = %d; want 1", len(store.deadLetters))
+	}
+	letter := store.deadLetters[0]
+	if letter.WebhookID != hook.ID || letter.Attempts != 3 || letter.LastStatus != http.StatusServiceUnavailable || letter.EventType != "medications.updated" {
+		t.Errorf("dead letter = %+v", letter)
+	}
+}
+
+// Переповнена черга не блокує Enqueue: зайві доставки йдуть у dead letters
+func TestWebhookQueueOverflow(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+	}))
+	defer server.Close()
+
+	hook := models.Webhook{ID: primitive.NewObjectID(), URL: server.URL, Secret: "s3cret", Events: []string{"*"}, Active: true}
+	store := &memoryStore{}
+	d := webhooks.NewDispatcher(store, 1)
+	delivery := func(i int) webhooks.Delivery {
+		return webhooks.Delivery{ID: strconv.Itoa(i), Webhook: hook, EventType: "medications.updated", Payload: []byte(`{}`)}
+	}
+
+	// Єдиний воркер зайнятий першою доставкою, черга на 256 місць
+	d.Enqueue(delivery(0))
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 300; i++ {
+			d.Enqueue(delivery(i))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Enqueue blocked on a full queue")
+	}
+
+	close(release)
+	d.Close()
+
+	if n := calls.Load(); n != 257 {
+		t.Errorf("receiver called %d times; want 257", n)
+	}
+	if len(store.deadLetters) != 44 {
+		t.Fatalf("dead letters = %d; want 44", len(store.deadLetters))
+	}
+	for _, letter := range store.deadLetters {
+		if letter.WebhookID != hook.ID || letter.Attempts != 0 || letter.LastError != webhooks.ErrQueueFull.Error() {
+			t.Errorf("dead letter = %+v", letter)
+			break
+		}
+	}
+}
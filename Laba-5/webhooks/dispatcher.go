@@ -0,0 +1,210 @@
+// Package webhooks надсилає доменні події hospital-api зовнішнім системам:
+// підписані HMAC запити, повтори з експоненційною затримкою і список
+// невдалих доставок (dead letters). Доставки виконує пул воркерів.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"hospital-api/events"
+	"hospital-api/models"
+)
+
+// ErrQueueFull - доставку не поставлено в чергу, бо вона переповнена
+var ErrQueueFull = errors.New("delivery queue is full")
+
+// Payload - тіло запиту доставки. Type - "ресурс.дія", як у підписках.
+type Payload struct {
+	Type       string      `json:"type"`
+	Resource   string      `json:"resource"`
+	ResourceID string      `json:"resourceId"`
+	Time       time.Time   `json:"time"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// Delivery - одна подія для однієї підписки
+type Delivery struct {
+	ID        string
+	Webhook   models.Webhook
+	EventType string
+	Payload   []byte
+	Attempts  int
+}
+
+// Dispatcher доставляє події підпискам. Невдала спроба повторюється
+// через Backoff, а після MaxAttempts доставка йде в dead letters.
+type Dispatcher struct {
+	Store       Store
+	Client      *http.Client
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	pool    *WorkerPool
+	pending sync.WaitGroup
+}
+
+func NewDispatcher(store Store, workers int) *Dispatcher {
+	return &Dispatcher{
+		Store:       store,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: 6,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+		pool:        NewWorkerPool(workers, 256),
+	}
+}
+
+// Backoff - затримка після attempt невдалих спроб: BaseDelay, 2×, 4×... до MaxDelay
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	delay := d.BaseDelay
+	for i := 1; i < attempt && delay < d.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.MaxDelay {
+		delay = d.MaxDelay
+	}
+	return delay
+}
+
+// Run доставляє всі події з bus, доки ctx не скасовано
+func (d *Dispatcher) Run(ctx context.Context, bus *events.Bus) {
+	for {
+		sub := bus.Subscribe(events.Filter{}, 256)
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					// Шина відключила нас через переповнений буфер
+					log.Println("webhooks: event backlog overflow, some events were not delivered")
+					open = false
+					continue
+				}
+				if err := d.Dispatch(ctx, e); err != nil {
+					log.Println("webhooks:", err)
+				}
+			}
+		}
+	}
+}
+
+// Dispatch ставить у чергу доставку події кожній відповідній підписці
+func (d *Dispatcher) Dispatch(ctx context.Context, e events.Event) error {
+	eventType := e.Resource + "." + e.Type
+	hooks, err := d.Store.Subscriptions(ctx, eventType)
+	if err != nil {
+		return fmt.Errorf("load subscriptions for %s: %w", eventType, err)
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload{
+		Type:       eventType,
+		Resource:   e.Resource,
+		ResourceID: e.ResourceID,
+		Time:       e.Time,
+		Data:       e.Data,
+	})
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		d.Enqueue(Delivery{ID: randomHex(16), Webhook: hook, EventType: eventType, Payload: body})
+	}
+	return nil
+}
+
+// Enqueue ставить доставку в чергу пулу. Не блокується: якщо черга
+// повна, доставка одразу йде в dead letters, звідки її можна повторити.
+func (d *Dispatcher) Enqueue(delivery Delivery) {
+	d.pending.Add(1)
+	d.schedule(delivery)
+}
+
+// schedule ставить спробу в чергу або, якщо місця немає, зберігає dead letter
+func (d *Dispatcher) schedule(delivery Delivery) {
+	if d.pool.TryAddJob(Job{run: func() { d.attempt(delivery) }}) {
+		return
+	}
+	d.deadLetter(delivery, 0, ErrQueueFull)
+	d.pending.Done()
+}
+
+// Close чекає, поки всі доставки (разом із повторами) завершаться
+func (d *Dispatcher) Close() {
+	d.pending.Wait()
+	d.pool.Close()
+}
+
+func (d *Dispatcher) attempt(delivery Delivery) {
+	delivery.Attempts++
+	status, err := d.send(delivery)
+	if err == nil {
+		d.pending.Done()
+		return
+	}
+
+	if delivery.Attempts >= d.MaxAttempts {
+		d.deadLetter(delivery, status, err)
+		d.pending.Done()
+		return
+	}
+
+	time.AfterFunc(d.Backoff(delivery.Attempts), func() { d.schedule(delivery) })
+}
+
+func (d *Dispatcher) deadLetter(delivery Delivery, status int, err error) {
+	letter := models.DeadLetter{
+		WebhookID:  delivery.Webhook.ID,
+		DeliveryID: delivery.ID,
+		EventType:  delivery.EventType,
+		Payload:    string(delivery.Payload),
+		Attempts:   delivery.Attempts,
+		LastStatus: status,
+		LastError:  err.Error(),
+		FailedAt:   time.Now().UTC(),
+	}
+	if err := d.Store.SaveDeadLetter(context.Background(), letter); err != nil {
+		log.Println("webhooks: cannot save dead letter:", err)
+	}
+}
+
+// send виконує одну спробу; успіх - будь-яка відповідь 2xx
+func (d *Dispatcher) send(delivery Delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.Webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
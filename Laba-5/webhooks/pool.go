@@ -0,0 +1,56 @@
+package webhooks
+
+import "sync"
+
+// Job - одна задача для воркера
+type Job struct {
+	run func()
+}
+
+// WorkerPool - фіксована кількість воркерів, що виконують задачі з черги
+// (ідея з Laba-4). Черга буферизована, щоб AddJob не чекав на вільного воркера.
+type WorkerPool struct {
+	jobs chan Job
+	wg   *sync.WaitGroup
+}
+
+func NewWorkerPool(numWorkers, queueSize int) *WorkerPool {
+	wp := &WorkerPool{
+		jobs: make(chan Job, queueSize),
+		wg:   &sync.WaitGroup{},
+	}
+
+	for i := 1; i <= numWorkers; i++ {
+		go func() {
+			for job := range wp.jobs {
+				job.run()
+				wp.wg.Done()
+			}
+		}()
+	}
+
+	return wp
+}
+
+func (wp *WorkerPool) AddJob(job Job) {
+	wp.wg.Add(1)
+	wp.jobs <- job
+}
+
+// TryAddJob додає задачу, лише якщо в черзі є місце; false - черга повна
+func (wp *WorkerPool) TryAddJob(job Job) bool {
+	wp.wg.Add(1)
+	select {
+	case wp.jobs <- job:
+		return true
+	default:
+		wp.wg.Done()
+		return false
+	}
+}
+
+// Close чекає завершення всіх доданих задач і зупиняє воркерів
+func (wp *WorkerPool) Close() {
+	wp.wg.Wait()
+	close(wp.jobs)
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки кожної доставки
+const (
+	HeaderEvent     = "X-Hospital-Event"
+	HeaderDelivery  = "X-Hospital-Delivery"
+	HeaderTimestamp = "X-Hospital-Timestamp"
+	HeaderSignature = "X-Hospital-Signature"
+)
+
+// Sign повертає підпис "sha256=<hex>" від HMAC-SHA256 рядка "timestamp.body".
+// Мітка часу входить у підпис, щоб перехоплений запит не можна було повторити пізніше.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+var (
+	ErrBadSignature = errors.New("webhook signature mismatch")
+	ErrStale        = errors.New("webhook timestamp outside tolerance")
+)
+
+// Verify - перевірка на боці отримувача: підпис і давність мітки часу
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrStale
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return ErrStale
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(strings.TrimSpace(signature))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// NewSecret генерує випадковий секрет підписки
+func NewSecret() string {
+	return "whsec_" + randomHex(24)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
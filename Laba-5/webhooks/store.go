@@ -0,0 +1,60 @@
+package webhooks
+
+import (
+	"context"
+	"strings"
+
+	"hospital-api/db"
+	"hospital-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Store - звідки диспетчер бере підписки і куди складає невдалі доставки
+type Store interface {
+	Subscriptions(ctx context.Context, eventType string) ([]models.Webhook, error)
+	SaveDeadLetter(ctx context.Context, letter models.DeadLetter) error
+}
+
+// MongoStore - Store на колекціях webhooks і webhook_dead_letters
+type MongoStore struct{}
+
+func (MongoStore) Subscriptions(ctx context.Context, eventType string) ([]models.Webhook, error) {
+	cursor, err := db.Collection("webhooks").Find(ctx, bson.M{
+		"active": true,
+		"events": bson.M{"$in": Patterns(eventType)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var hooks []models.Webhook
+	err = cursor.All(ctx, &hooks)
+	return hooks, err
+}
+
+func (MongoStore) SaveDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	_, err := db.Collection("webhook_dead_letters").InsertOne(ctx, letter)
+	return err
+}
+
+// Patterns - усі шаблони підписки, що охоплюють тип події:
+// сам тип, "ресурс.*" і "*"
+func Patterns(eventType string) []string {
+	patterns := []string{eventType, "*"}
+	if resource, _, ok := strings.Cut(eventType, "."); ok {
+		patterns = append(patterns, resource+".*")
+	}
+	return patterns
+}
+
+// Matches - чи підписка з шаблонами events отримує подію eventType
+func Matches(events []string, eventType string) bool {
+	for _, e := range events {
+		for _, p := range Patterns(eventType) {
+			if e == p {
+				return true
+			}
+		}
+	}
+	return false
+}